@@ -1,9 +1,11 @@
 package config
 
 import (
-	"log"
+	"time"
 
 	"github.com/caarlos0/env/v11"
+
+	"ssle/services/logging"
 )
 
 type Config struct {
@@ -12,13 +14,37 @@ type Config struct {
 	CrtFile string `env:"CERTIFICATE" envDefault:"node.crt"`
 	KeyFile string `env:"KEY" envDefault:"node.key"`
 
+	BootstrapToken string `env:"BOOTSTRAP_TOKEN"`
+
 	JoinUrl string `env:"JOIN_URL,required"`
 	CAFile  string `env:"CA_FILE" envDefault:"ca.crt"`
 
 	DNSBindAddr string `env:"DNS_BIND_ADDR" envDefault:"127.0.0.143"`
 	DNSUpstream string `env:"DNS_UPSTREAM" envDefault:"127.0.0.53:53"`
 
+	DiscoveryStaleGrace time.Duration `env:"DISCOVERY_STALE_GRACE" envDefault:"5m"`
+	// Services not queried for this long are no longer watched nor cached
+	DiscoveryIdleTimeout time.Duration `env:"DISCOVERY_IDLE_TIMEOUT" envDefault:"10m"`
+
 	EventsLog string `env:"EVENTS_LOG" envDefault:"events.log"`
+
+	// Workload certificates are issued to every container and written to
+	// <dir>/<container> when set
+	WorkloadCertsDir string `env:"WORKLOAD_CERTS_DIR"`
+
+	// Address of the connect proxies, containers must reach the agent with
+	// their own address for their upstream proxies to accept them
+	ConnectBindAddr string `env:"CONNECT_BIND_ADDR" envDefault:"0.0.0.0"`
+
+	// One of debug, info, warn or error, formatted as text or json
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
+	LogFormat string `env:"LOG_FORMAT" envDefault:"text"`
+
+	// Serves /metrics, /healthz and /readyz
+	HTTPAddr string `env:"HTTP_ADDR" envDefault:"127.0.0.1:9101"`
+	// Serves /v1/authorize without authentication, it must only be reachable
+	// by the local workloads
+	AuthorizeAddr string `env:"AUTHORIZE_ADDR" envDefault:"127.0.0.1:9102"`
 }
 
 func LoadConfig() Config {
@@ -27,7 +53,7 @@ func LoadConfig() Config {
 		Prefix: "AGENT_",
 	})
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+		logging.Fatal("Error loading configuration", "err", err)
 	}
 
 	return config
@@ -0,0 +1,75 @@
+package connect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Name of the port advertised for the inbound proxy of an instance
+const PortName = "connect"
+
+// Proxies of an instance, set with the ssle.connect=<listen port>[:<target
+// port>] label for connections from other services and with
+// ssle.connect.upstream.<service>=<local port> labels for connections to them
+type Config struct {
+	// Port receiving the mTLS connections of other services, forwarded in
+	// plaintext to the target port of the container
+	ListenPort uint16
+	TargetPort uint16
+
+	// Local port forwarding to each upstream service
+	Upstreams map[string]uint16
+}
+
+func parsePort(raw string) (uint16, error) {
+	port, err := strconv.ParseUint(raw, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q", raw)
+	}
+	return uint16(port), nil
+}
+
+// Parse the connect labels of a container, returns nil if it has none
+func ParseLabels(labels map[string]string) (*Config, error) {
+	config := &Config{Upstreams: map[string]uint16{}}
+	found := false
+
+	if value, ok := labels["ssle.connect"]; ok {
+		found = true
+
+		listen, target, hasTarget := strings.Cut(value, ":")
+		port, err := parsePort(listen)
+		if err != nil {
+			return nil, err
+		}
+		config.ListenPort = port
+		config.TargetPort = port
+
+		if hasTarget {
+			config.TargetPort, err = parsePort(target)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for label, value := range labels {
+		service, ok := strings.CutPrefix(label, "ssle.connect.upstream.")
+		if !ok {
+			continue
+		}
+		found = true
+
+		port, err := parsePort(value)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %v: %w", service, err)
+		}
+		config.Upstreams[service] = port
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return config, nil
+}
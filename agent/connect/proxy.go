@@ -0,0 +1,379 @@
+package connect
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"ssle/agent/discovery"
+	"ssle/agent/metrics"
+	"ssle/agent/workload"
+	pb "ssle/services"
+	"ssle/services/spiffe"
+)
+
+const (
+	handshakeTimeout = 10 * time.Second
+	dialTimeout      = 5 * time.Second
+)
+
+var errNoIdentity = errors.New("workload certificate not issued yet")
+
+// Runs the proxies of the instances with connect labels. Connections between
+// proxies use mTLS with the workload certificates of the instances.
+type Manager struct {
+	mu sync.Mutex
+
+	client    pb.AgentAPIClient
+	discovery *discovery.Cache
+	sources   *discovery.Sources
+	workloads *workload.Manager
+	bindAddr  string
+
+	listeners map[string][]net.Listener
+}
+
+func NewManager(
+	client pb.AgentAPIClient,
+	discovery *discovery.Cache,
+	sources *discovery.Sources,
+	workloads *workload.Manager,
+	bindAddr string,
+) *Manager {
+	return &Manager{
+		client:    client,
+		discovery: discovery,
+		sources:   sources,
+		workloads: workloads,
+		bindAddr:  bindAddr,
+		listeners: map[string][]net.Listener{},
+	}
+}
+
+// Start the proxies of an instance, target is the address of the container
+// the inbound connections are forwarded to. Either every proxy is listening
+// or none is, instances can't share their ports with another one on the node.
+func (manager *Manager) Start(service string, instance string, config *Config, target netip.Addr) error {
+	manager.Stop(instance)
+
+	if config.ListenPort != 0 && !target.IsValid() {
+		return errors.New("container has no address for the inbound proxy")
+	}
+
+	ports := []uint16{}
+	if config.ListenPort != 0 {
+		ports = append(ports, config.ListenPort)
+	}
+	for _, port := range config.Upstreams {
+		ports = append(ports, port)
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	listeners := map[uint16]net.Listener{}
+	for _, port := range ports {
+		listener, err := manager.listen(port)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return err
+		}
+		listeners[port] = listener
+	}
+
+	if config.ListenPort != 0 {
+		go manager.serveInbound(listeners[config.ListenPort], service, instance, netip.AddrPortFrom(target, config.TargetPort))
+	}
+	for upstream, port := range config.Upstreams {
+		go manager.serveUpstream(listeners[port], service, instance, upstream)
+	}
+
+	manager.listeners[instance] = slices.Collect(maps.Values(listeners))
+
+	slog.Info("Started connect proxies", "service", service, "instance", instance, "listeners", len(listeners))
+	return nil
+}
+
+// Stop accepting connections for an instance, established ones are kept
+func (manager *Manager) Stop(instance string) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	for _, listener := range manager.listeners[instance] {
+		listener.Close()
+	}
+	delete(manager.listeners, instance)
+}
+
+func (manager *Manager) listen(port uint16) (net.Listener, error) {
+	for other, listeners := range manager.listeners {
+		for _, listener := range listeners {
+			if listener.Addr().(*net.TCPAddr).Port == int(port) {
+				return nil, fmt.Errorf("port %d is already used by %v", port, other)
+			}
+		}
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(manager.bindAddr, strconv.Itoa(int(port))))
+	if err != nil {
+		return nil, fmt.Errorf("port %d: %w", port, err)
+	}
+	return listener, nil
+}
+
+// Verify the certificate of the other proxy against the workload CAs and
+// return its workload id. If service isn't empty the id must belong to it.
+func verifyPeer(identity *workload.Identity, cs tls.ConnectionState, usage x509.ExtKeyUsage, service string) (string, error) {
+	if identity == nil {
+		return "", errNoIdentity
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return "", errors.New("no peer certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	leaf := cs.PeerCertificates[0]
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         identity.CAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Node certificates are issued by the same CA but have no workload id
+	id, err := spiffe.FromCertificate(leaf)
+	if err != nil {
+		return "", err
+	}
+
+	if service != "" {
+		_, peerService, err := spiffe.Parse(id)
+		if err != nil {
+			return "", err
+		}
+		if peerService != service {
+			return "", fmt.Errorf("expected service %v, got %v", service, id)
+		}
+	}
+
+	return id, nil
+}
+
+func (manager *Manager) serverTLSConfig(instance string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ClientAuth: tls.RequireAnyClientCert,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			identity := manager.workloads.Identity(instance)
+			if identity == nil {
+				return nil, errNoIdentity
+			}
+			return &identity.Certificate, nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, err := verifyPeer(manager.workloads.Identity(instance), cs, x509.ExtKeyUsageClientAuth, "")
+			return err
+		},
+	}
+}
+
+func (manager *Manager) clientTLSConfig(instance string, upstream string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		// Proxies are identified by their workload id rather than a hostname,
+		// the certificate is verified in VerifyConnection instead
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			identity := manager.workloads.Identity(instance)
+			if identity == nil {
+				return nil, errNoIdentity
+			}
+			return &identity.Certificate, nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, err := verifyPeer(manager.workloads.Identity(instance), cs, x509.ExtKeyUsageServerAuth, upstream)
+			return err
+		},
+	}
+}
+
+func accept(listener net.Listener, handle func(net.Conn)) {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			slog.Error("Error accepting connection", "addr", listener.Addr(), "err", err)
+			continue
+		}
+
+		go handle(conn)
+	}
+}
+
+func (manager *Manager) serveInbound(listener net.Listener, service string, instance string, target netip.AddrPort) {
+	config := manager.serverTLSConfig(instance)
+	accept(listener, func(conn net.Conn) {
+		manager.handleInbound(tls.Server(conn, config), service, target)
+	})
+}
+
+// Accept a connection from another proxy if the intentions allow its
+// service, and forward it to the container
+func (manager *Manager) handleInbound(conn *tls.Conn, service string, target netip.AddrPort) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	err := conn.HandshakeContext(ctx)
+	if err != nil {
+		slog.Warn("Rejected inbound connection", "service", service, "remote", conn.RemoteAddr(), "err", err)
+		metrics.ConnectConnections.WithLabelValues(metrics.ConnectInbound, metrics.ConnectRejected).Inc()
+		return
+	}
+
+	source, _ := spiffe.FromCertificate(conn.ConnectionState().PeerCertificates[0])
+	res, err := manager.client.AuthorizeConnection(ctx, &pb.AuthorizeConnectionRequest{
+		Source:      &source,
+		Destination: &service,
+	})
+	if err != nil {
+		slog.Error("Error authorizing inbound connection", "service", service, "source", source, "err", err)
+		metrics.ConnectConnections.WithLabelValues(metrics.ConnectInbound, metrics.ConnectError).Inc()
+		return
+	}
+	if !res.GetAllowed() {
+		slog.Warn("Denied inbound connection", "service", service, "source", source, "intention", res.GetIntention())
+		metrics.ConnectConnections.WithLabelValues(metrics.ConnectInbound, metrics.ConnectDenied).Inc()
+		return
+	}
+
+	targetConn, err := net.DialTimeout("tcp", target.String(), dialTimeout)
+	if err != nil {
+		slog.Error("Error connecting to service", "service", service, "target", target, "err", err)
+		metrics.ConnectConnections.WithLabelValues(metrics.ConnectInbound, metrics.ConnectError).Inc()
+		return
+	}
+
+	metrics.ConnectConnections.WithLabelValues(metrics.ConnectInbound, metrics.ConnectAccepted).Inc()
+	pipe(conn, targetConn)
+}
+
+func (manager *Manager) serveUpstream(listener net.Listener, service string, instance string, upstream string) {
+	config := manager.clientTLSConfig(instance, upstream)
+	accept(listener, func(conn net.Conn) {
+		manager.handleUpstream(conn, service, upstream, config)
+	})
+}
+
+// Forward a connection from the container to an instance of the upstream
+// service through its inbound proxy
+func (manager *Manager) handleUpstream(conn net.Conn, service string, upstream string, config *tls.Config) {
+	defer conn.Close()
+
+	// Only the containers of the service may use its identity
+	addr, err := netip.ParseAddrPort(conn.RemoteAddr().String())
+	if source, found := manager.sources.Service(addr.Addr()); err != nil || !found || source != service {
+		slog.Warn("Rejected upstream connection from another container", "service", service, "upstream", upstream, "remote", conn.RemoteAddr())
+		metrics.ConnectConnections.WithLabelValues(metrics.ConnectOutbound, metrics.ConnectRejected).Inc()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	specs, err := manager.discovery.Discover(ctx, &pb.DiscoverRequest{
+		Service:       &upstream,
+		SourceService: &service,
+	})
+	if err != nil {
+		slog.Error("Error discovering upstream", "service", service, "upstream", upstream, "err", err)
+		metrics.ConnectConnections.WithLabelValues(metrics.ConnectOutbound, metrics.ConnectError).Inc()
+		return
+	}
+
+	for _, i := range rand.Perm(len(specs)) {
+		for _, target := range connectAddrs(specs[i]) {
+			dialer := &tls.Dialer{
+				NetDialer: &net.Dialer{Timeout: dialTimeout},
+				Config:    config,
+			}
+			upstreamConn, err := dialer.DialContext(ctx, "tcp", target)
+			if err != nil {
+				slog.Warn("Error connecting to upstream instance", "service", service, "upstream", upstream, "target", target, "err", err)
+				continue
+			}
+
+			metrics.ConnectConnections.WithLabelValues(metrics.ConnectOutbound, metrics.ConnectAccepted).Inc()
+			pipe(conn, upstreamConn)
+			return
+		}
+	}
+
+	slog.Error("No upstream instance reachable", "service", service, "upstream", upstream, "instances", len(specs))
+	metrics.ConnectConnections.WithLabelValues(metrics.ConnectOutbound, metrics.ConnectError).Inc()
+}
+
+// Addresses of the inbound proxy of an instance
+func connectAddrs(spec *pb.ServiceSpec) []string {
+	for _, port := range spec.Ports {
+		if port.GetName() != PortName {
+			continue
+		}
+
+		addrs := []string{}
+		for _, addr := range spec.Addresses {
+			addrs = append(addrs, net.JoinHostPort(addr, strconv.Itoa(int(port.GetPort()))))
+		}
+		return addrs
+	}
+
+	return nil
+}
+
+type closeWriter interface {
+	CloseWrite() error
+}
+
+// Copy both directions until they are closed, half closes are forwarded so
+// protocols relying on them keep working
+func pipe(a net.Conn, b net.Conn) {
+	defer b.Close()
+
+	done := make(chan struct{})
+	go func() {
+		io.Copy(a, b)
+		if conn, ok := a.(closeWriter); ok {
+			conn.CloseWrite()
+		}
+		close(done)
+	}()
+
+	io.Copy(b, a)
+	if conn, ok := b.(closeWriter); ok {
+		conn.CloseWrite()
+	}
+	<-done
+}
@@ -0,0 +1,250 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	"ssle/agent/metrics"
+	pb "ssle/services"
+)
+
+const (
+	watchRetryPeriod = 5 * time.Second
+	evictPeriod      = time.Minute
+)
+
+type cacheEntry struct {
+	services []*pb.ServiceSpec
+
+	// Whether the entry is known to reflect the registry state, entries are
+	// marked stale when the service changes or the watch is lost.
+	fresh      bool
+	staleSince time.Time
+	lastUsed   time.Time
+}
+
+// Cache of discovery results kept up to date by watching the registry for
+// changes to the cached services.
+type Cache struct {
+	mu sync.Mutex
+
+	client      pb.AgentAPIClient
+	staleGrace  time.Duration
+	idleTimeout time.Duration
+
+	entries map[string]map[string]*cacheEntry
+	// Services with a running watch job, and whether their watch is connected
+	watching map[string]context.CancelFunc
+	watched  map[string]bool
+	// Bumped on every invalidation so results fetched concurrently with a
+	// change are not considered fresh
+	generation map[string]uint64
+	// Last time each service was queried, services left idle for longer than
+	// idleTimeout stop being watched and cached
+	lastUsed map[string]time.Time
+}
+
+func NewCache(client pb.AgentAPIClient, staleGrace time.Duration, idleTimeout time.Duration) *Cache {
+	cache := &Cache{
+		client:      client,
+		staleGrace:  staleGrace,
+		idleTimeout: idleTimeout,
+		entries:     map[string]map[string]*cacheEntry{},
+		watching:    map[string]context.CancelFunc{},
+		watched:     map[string]bool{},
+		generation:  map[string]uint64{},
+		lastUsed:    map[string]time.Time{},
+	}
+	go cache.evictJob()
+
+	return cache
+}
+
+func requestKey(req *pb.DiscoverRequest) string {
+	tags := slices.Sorted(slices.Values(req.Tags))
+
+	return fmt.Sprintf(
+		"%s/%s/%s/%s/%t/%s/%s",
+		req.GetLocation(),
+		req.GetDatacenter(),
+		req.GetNode(),
+		req.GetInstance(),
+		req.GetIncludeUnhealthy(),
+		strings.Join(tags, ","),
+		req.GetSourceService(),
+	)
+}
+
+func (cache *Cache) Discover(ctx context.Context, req *pb.DiscoverRequest) ([]*pb.ServiceSpec, error) {
+	service := req.GetService()
+	key := requestKey(req)
+
+	cache.mu.Lock()
+	now := time.Now()
+	cache.lastUsed[service] = now
+
+	entry := cache.entries[service][key]
+	if entry != nil {
+		entry.lastUsed = now
+	}
+	if entry != nil && entry.fresh {
+		cache.mu.Unlock()
+		return entry.services, nil
+	}
+
+	if cache.watching[service] == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		cache.watching[service] = cancel
+		go cache.watchJob(ctx, service)
+	}
+	generation := cache.generation[service]
+	cache.mu.Unlock()
+
+	start := time.Now()
+	res, err := cache.client.Discover(ctx, req)
+	metrics.DiscoverDuration.WithLabelValues(status.Code(err).String()).Observe(time.Since(start).Seconds())
+	if err != nil {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+
+		entry := cache.entries[service][key]
+		if entry != nil && time.Since(entry.staleSince) < cache.staleGrace {
+			slog.WarnContext(ctx, "Serving stale services", "service", service, "err", err)
+			return entry.services, nil
+		}
+
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now = time.Now()
+	if cache.entries[service] == nil {
+		cache.entries[service] = map[string]*cacheEntry{}
+	}
+	cache.entries[service][key] = &cacheEntry{
+		services:   res.Services,
+		fresh:      cache.watched[service] && cache.generation[service] == generation,
+		staleSince: now,
+		lastUsed:   now,
+	}
+	cache.lastUsed[service] = now
+
+	return res.Services, nil
+}
+
+// Mark all cached results for a service as stale, they are still kept so they
+// can be served if the registry can't be reached.
+func (cache *Cache) invalidateLocked(service string) {
+	cache.generation[service] += 1
+
+	now := time.Now()
+	for _, entry := range cache.entries[service] {
+		if entry.fresh {
+			entry.fresh = false
+			entry.staleSince = now
+		}
+	}
+}
+
+// Run f with the cache locked unless the watch behind ctx was stopped, so that
+// a stopped watch doesn't change the state of a newer one.
+func (cache *Cache) updateWatch(ctx context.Context, f func()) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if ctx.Err() == nil {
+		f()
+	}
+}
+
+func (cache *Cache) watch(ctx context.Context, service string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := cache.client.Watch(ctx, &pb.WatchRequest{Service: &service})
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		cache.updateWatch(ctx, func() {
+			if res.Notification == nil {
+				// The watch is established or the policies changed,
+				// anything cached before may be stale
+				cache.watched[service] = true
+			}
+			cache.invalidateLocked(service)
+		})
+	}
+}
+
+func (cache *Cache) watchJob(ctx context.Context, service string) {
+	for ctx.Err() == nil {
+		err := cache.watch(ctx, service)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("Error watching services", "service", service, "err", err)
+
+		cache.updateWatch(ctx, func() {
+			cache.watched[service] = false
+			cache.invalidateLocked(service)
+		})
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(watchRetryPeriod):
+		}
+	}
+}
+
+// Drop the results nobody asked for within the idle timeout, and stop watching
+// services that weren't queried at all.
+func (cache *Cache) evictIdle() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	for service, lastUsed := range cache.lastUsed {
+		if now.Sub(lastUsed) < cache.idleTimeout {
+			for key, entry := range cache.entries[service] {
+				if now.Sub(entry.lastUsed) >= cache.idleTimeout {
+					delete(cache.entries[service], key)
+				}
+			}
+			continue
+		}
+
+		if cancel := cache.watching[service]; cancel != nil {
+			cancel()
+		}
+		delete(cache.watching, service)
+		delete(cache.watched, service)
+		delete(cache.entries, service)
+		delete(cache.generation, service)
+		delete(cache.lastUsed, service)
+
+		slog.Debug("Stopped watching idle service", "service", service)
+	}
+}
+
+func (cache *Cache) evictJob() {
+	for {
+		time.Sleep(evictPeriod)
+		cache.evictIdle()
+	}
+}
@@ -0,0 +1,155 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "ssle/services"
+)
+
+// Registry client whose discover calls always fail
+type unreachableClient struct {
+	pb.AgentAPIClient
+}
+
+func (client *unreachableClient) Discover(ctx context.Context, in *pb.DiscoverRequest, opts ...grpc.CallOption) (*pb.DiscoverResponse, error) {
+	return nil, errors.New("registry unreachable")
+}
+
+func newTestCache(staleGrace time.Duration, idleTimeout time.Duration) *Cache {
+	return &Cache{
+		client:      &unreachableClient{},
+		staleGrace:  staleGrace,
+		idleTimeout: idleTimeout,
+		entries:     map[string]map[string]*cacheEntry{},
+		watching:    map[string]context.CancelFunc{},
+		watched:     map[string]bool{},
+		generation:  map[string]uint64{},
+		lastUsed:    map[string]time.Time{},
+	}
+}
+
+func TestStaleGrace(t *testing.T) {
+	tests := []struct {
+		name      string
+		cached    bool
+		staleFor  time.Duration
+		wantServe bool
+	}{
+		{name: "nothing cached", cached: false, wantServe: false},
+		{name: "within grace", cached: true, staleFor: time.Second, wantServe: true},
+		{name: "grace expired", cached: true, staleFor: time.Hour, wantServe: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newTestCache(time.Minute, time.Hour)
+
+			service := "web"
+			req := &pb.DiscoverRequest{Service: &service}
+			// Keep the cache from starting a watch job
+			cache.watching[service] = func() {}
+
+			cached := []*pb.ServiceSpec{{}}
+			if test.cached {
+				cache.entries[service] = map[string]*cacheEntry{
+					requestKey(req): {
+						services:   cached,
+						staleSince: time.Now().Add(-test.staleFor),
+						lastUsed:   time.Now(),
+					},
+				}
+			}
+
+			services, err := cache.Discover(context.Background(), req)
+			if test.wantServe {
+				if err != nil {
+					t.Fatalf("expected stale services to be served, got %v", err)
+				}
+				if len(services) != len(cached) || services[0] != cached[0] {
+					t.Fatalf("expected the cached services, got %v", services)
+				}
+			} else if err == nil {
+				t.Fatalf("expected an error, got %v", services)
+			}
+		})
+	}
+}
+
+func TestInvalidateKeepsStaleSince(t *testing.T) {
+	cache := newTestCache(time.Minute, time.Hour)
+
+	staleSince := time.Now().Add(-time.Hour)
+	cache.entries["web"] = map[string]*cacheEntry{
+		"fresh": {fresh: true, staleSince: staleSince},
+		"stale": {fresh: false, staleSince: staleSince},
+	}
+
+	cache.invalidateLocked("web")
+
+	if cache.generation["web"] != 1 {
+		t.Fatalf("expected generation 1, got %d", cache.generation["web"])
+	}
+	for key, entry := range cache.entries["web"] {
+		if entry.fresh {
+			t.Errorf("%s: expected entry to be stale", key)
+		}
+	}
+	if !cache.entries["web"]["fresh"].staleSince.After(staleSince) {
+		t.Errorf("fresh: expected stale time to be reset")
+	}
+	if !cache.entries["web"]["stale"].staleSince.Equal(staleSince) {
+		t.Errorf("stale: expected stale time to be kept")
+	}
+}
+
+func TestEvictIdle(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceIdleFor time.Duration
+		entryIdleFor   time.Duration
+		wantWatching   bool
+		wantEntry      bool
+	}{
+		{name: "in use", serviceIdleFor: time.Second, entryIdleFor: time.Second, wantWatching: true, wantEntry: true},
+		{name: "idle entry", serviceIdleFor: time.Second, entryIdleFor: time.Hour, wantWatching: true, wantEntry: false},
+		{name: "idle service", serviceIdleFor: time.Hour, entryIdleFor: time.Hour, wantWatching: false, wantEntry: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := newTestCache(time.Minute, time.Minute)
+
+			now := time.Now()
+			stopped := false
+			cache.watching["web"] = func() { stopped = true }
+			cache.watched["web"] = true
+			cache.generation["web"] = 1
+			cache.lastUsed["web"] = now.Add(-test.serviceIdleFor)
+			cache.entries["web"] = map[string]*cacheEntry{
+				"key": {fresh: true, lastUsed: now.Add(-test.entryIdleFor)},
+			}
+
+			cache.evictIdle()
+
+			if _, watching := cache.watching["web"]; watching != test.wantWatching {
+				t.Errorf("expected watching %t, got %t", test.wantWatching, watching)
+			}
+			if stopped == test.wantWatching {
+				t.Errorf("expected watch stopped %t, got %t", !test.wantWatching, stopped)
+			}
+			if _, found := cache.entries["web"]["key"]; found != test.wantEntry {
+				t.Errorf("expected entry kept %t, got %t", test.wantEntry, found)
+			}
+			if !test.wantWatching {
+				if _, found := cache.lastUsed["web"]; found {
+					t.Errorf("expected idle service to be forgotten")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package discovery
+
+import (
+	"net/netip"
+	"sync"
+)
+
+// Services of the managed containers by address, so that queries sent by a
+// container are discovered on behalf of its service
+type Sources struct {
+	mu sync.Mutex
+
+	services  map[netip.Addr]string
+	instances map[string][]netip.Addr
+}
+
+func NewSources() *Sources {
+	return &Sources{
+		services:  map[netip.Addr]string{},
+		instances: map[string][]netip.Addr{},
+	}
+}
+
+func (sources *Sources) Add(instance string, service string, addrs []netip.Addr) {
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+
+	sources.removeLocked(instance)
+	for _, addr := range addrs {
+		sources.services[addr] = service
+	}
+	sources.instances[instance] = addrs
+}
+
+func (sources *Sources) Remove(instance string) {
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+
+	sources.removeLocked(instance)
+}
+
+func (sources *Sources) removeLocked(instance string) {
+	for _, addr := range sources.instances[instance] {
+		delete(sources.services, addr)
+	}
+	delete(sources.instances, instance)
+}
+
+func (sources *Sources) Service(addr netip.Addr) (string, bool) {
+	sources.mu.Lock()
+	defer sources.mu.Unlock()
+
+	service, found := sources.services[addr.Unmap()]
+	return service, found
+}
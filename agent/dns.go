@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/netip"
 	"strings"
 	"time"
@@ -13,8 +13,15 @@ import (
 	"codeberg.org/miekg/dns/dnsconf"
 
 	"ssle/agent/config"
+	"ssle/agent/metrics"
 	"ssle/agent/state"
 	pb "ssle/services"
+	"ssle/services/logging"
+)
+
+const (
+	clusterDomain = "cluster.internal."
+	clusterTTL    = 30
 )
 
 type ClusterDnsHandler struct {
@@ -22,85 +29,275 @@ type ClusterDnsHandler struct {
 	state  *state.State
 }
 
+// Build a discover request from the labels of a cluster name, which are laid
+// out as [instance.[node.[datacenter.[location.]]]]service
+func discoverRequestFromLabels(parts []string) (*pb.DiscoverRequest, bool) {
+	if len(parts) < 1 || len(parts) > 5 {
+		return nil, false
+	}
+
+	queryLastIdx := len(parts) - 1
+
+	req := &pb.DiscoverRequest{
+		Service: &parts[queryLastIdx],
+	}
+	if queryLastIdx > 0 {
+		req.Location = &parts[queryLastIdx-1]
+	}
+	if queryLastIdx > 1 {
+		req.Datacenter = &parts[queryLastIdx-2]
+	}
+	if queryLastIdx > 2 {
+		req.Node = &parts[queryLastIdx-3]
+	}
+	if queryLastIdx > 3 {
+		req.Instance = &parts[queryLastIdx-4]
+	}
+
+	return req, true
+}
+
+// Fully qualified name that resolves to a single service instance
+func instanceName(spec *pb.ServiceSpec) string {
+	return fmt.Sprintf(
+		"%s.%s.%s.%s.%s.%s",
+		*spec.Instance,
+		*spec.Node,
+		*spec.Datacenter,
+		*spec.Location,
+		*spec.ServiceName,
+		clusterDomain,
+	)
+}
+
+func addressRecords(name string, qtype uint16, spec *pb.ServiceSpec) []dns.RR {
+	records := []dns.RR{}
+
+	for _, addr := range spec.Addresses {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			slog.Warn("Hostnames not supported", "address", addr)
+			continue
+		}
+
+		if ip.Is4() && qtype == dns.TypeA {
+			records = append(records, &dns.A{
+				Hdr: dns.Header{Name: name, Class: dns.ClassINET, TTL: clusterTTL},
+				A:   ip.AsSlice(),
+			})
+		} else if ip.Is6() && qtype == dns.TypeAAAA {
+			records = append(records, &dns.AAAA{
+				Hdr:  dns.Header{Name: name, Class: dns.ClassINET, TTL: clusterTTL},
+				AAAA: ip.AsSlice(),
+			})
+		}
+	}
+
+	return records
+}
+
+func srvRecords(name string, portName string, proto string, spec *pb.ServiceSpec) []dns.RR {
+	records := []dns.RR{}
+
+	for _, port := range spec.Ports {
+		portProto := "tcp"
+		if port.Protocol != nil {
+			portProto = *port.Protocol
+		}
+
+		if *port.Name != portName || portProto != proto {
+			continue
+		}
+
+		records = append(records, &dns.SRV{
+			Hdr:      dns.Header{Name: name, Class: dns.ClassINET, TTL: clusterTTL},
+			Priority: 0,
+			Weight:   1,
+			Port:     uint16(*port.Port),
+			Target:   instanceName(spec),
+		})
+	}
+
+	return records
+}
+
+// Split the service labels of a SRV query in the form of _port._proto.path
+func parseSrvLabels(parts []string) (string, string, []string, bool) {
+	if len(parts) < 3 {
+		return "", "", nil, false
+	}
+
+	portName, found := strings.CutPrefix(parts[0], "_")
+	if !found {
+		return "", "", nil, false
+	}
+
+	proto, found := strings.CutPrefix(parts[1], "_")
+	if !found {
+		return "", "", nil, false
+	}
+
+	return portName, proto, parts[2:], true
+}
+
+// Maximum size of a response to r, UDP clients are limited to the EDNS0
+// buffer size they advertised while TCP clients can receive a full message
+func maxResponseSize(w dns.ResponseWriter, r *dns.Msg) int {
+	if w.LocalAddr().Network() == "tcp" {
+		return dns.MaxMsgSize
+	}
+
+	return max(int(r.UDPSize), dns.MinMsgSize)
+}
+
+// Pack m, replacing it with a SERVFAIL response if it can't be packed so the
+// client doesn't wait for its timeout. Returns false if even that fails.
+func packResponse(m *dns.Msg) bool {
+	err := m.Pack()
+	if err == nil {
+		return true
+	}
+	slog.Error("Error packing DNS response", "err", err)
+
+	m.Answer, m.Ns, m.Extra, m.Pseudo = nil, nil, nil, nil
+	m.Response = true
+	m.Truncated = false
+	m.Rcode = dns.RcodeServerFailure
+	if err := m.Pack(); err != nil {
+		slog.Error("Error packing DNS failure response", "err", err)
+		return false
+	}
+
+	return true
+}
+
+// Write m to the client, dropping records and setting the TC bit if it
+// doesn't fit in maxSize so the client knows to retry over TCP
+func writeResponse(w dns.ResponseWriter, m *dns.Msg, maxSize int) {
+	if !packResponse(m) {
+		return
+	}
+
+	// The additional section is optional, so drop it before any answers
+	if len(m.Data) > maxSize && len(m.Extra) > 0 {
+		m.Extra = nil
+		if !packResponse(m) {
+			return
+		}
+	}
+
+	for len(m.Data) > maxSize && len(m.Answer) > 0 {
+		m.Truncated = true
+		m.Answer = m.Answer[:len(m.Answer)-1]
+		if !packResponse(m) {
+			return
+		}
+	}
+
+	io.Copy(w, m)
+}
+
+// Record the type of the first question of r, with the rcode of the response
+func observeQuery(handler string, r *dns.Msg, rcode string, start time.Time) {
+	qtype := "NONE"
+	if len(r.Question) > 0 {
+		qtype = dns.TypeToString[dns.RRToType(r.Question[0])]
+		if qtype == "" {
+			qtype = "OTHER"
+		}
+	}
+
+	metrics.ObserveDNSQuery(handler, qtype, rcode, time.Since(start))
+}
+
 func (h *ClusterDnsHandler) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg) {
+	start := time.Now()
+
 	// re-use r
+	maxSize := maxResponseSize(w, r)
+
 	r.MsgHeader.Authoritative = true
 	r.Answer, r.Ns, r.Extra, r.Pseudo = nil, nil, nil, nil
 	r.Response = true
+	// Queries with an OPT record get one back, advertising the buffer size of
+	// the server instead of echoing their options
+	if r.UDPSize != 0 {
+		r.UDPSize = dns.DefaultMsgSize
+	}
 
 	answers := []dns.RR{}
+	extra := []dns.RR{}
 
 	for _, question := range r.Question {
 		header := question.Header()
-		path, found := strings.CutSuffix(header.Name, ".cluster.internal.")
+		path, found := strings.CutSuffix(header.Name, "."+clusterDomain)
 
 		if !found {
 			r.MsgHeader.Rcode = dns.RcodeNameError
+			break
 		}
 
-		if header.Class != dns.TypeA && header.Class != dns.TypeAAAA {
+		qtype := dns.RRToType(question)
+		if qtype != dns.TypeA && qtype != dns.TypeAAAA && qtype != dns.TypeSRV {
 			continue
 		}
 
 		parts := strings.Split(path, ".")
-		if len(parts) < 1 || len(parts) > 5 {
+
+		portName, proto := "", ""
+		if qtype == dns.TypeSRV {
+			portName, proto, parts, found = parseSrvLabels(parts)
+			if !found {
+				r.MsgHeader.Rcode = dns.RcodeNameError
+				break
+			}
+		}
+
+		req, found := discoverRequestFromLabels(parts)
+		if !found {
 			r.MsgHeader.Rcode = dns.RcodeNameError
 			break
 		}
 
-		queryLastIdx := len(parts) - 1
-
-		req := &pb.DiscoverRequest{
-			Service: &parts[queryLastIdx],
-		}
-		if queryLastIdx > 0 {
-			req.Location = &parts[queryLastIdx-1]
-		}
-		if queryLastIdx > 1 {
-			req.Datacenter = &parts[queryLastIdx-2]
-		}
-		if queryLastIdx > 3 {
-			req.Node = &parts[queryLastIdx-3]
-		}
-		if queryLastIdx > 4 {
-			req.Instance = &parts[queryLastIdx-4]
+		// Policies may only let some services discover others
+		if addr, err := netip.ParseAddrPort(w.RemoteAddr().String()); err == nil {
+			if svc, found := h.state.Sources.Service(addr.Addr()); found {
+				req.SourceService = &svc
+			}
 		}
 
-		res, err := h.state.AgentClient.Discover(ctx, req)
+		specs, err := h.state.Discovery.Discover(ctx, req)
 		if err != nil {
-			log.Printf("Error obtaining service: %v", err)
+			slog.ErrorContext(ctx, "Error obtaining service", "service", req.GetService(), "err", err)
 			r.MsgHeader.Rcode = dns.RcodeNameError
 			break
 		}
 
-		for _, spec := range res.Services {
-			for _, addr := range spec.Addresses {
-				ip, err := netip.ParseAddr(addr)
-				if err == nil {
-					if ip.Is4() && header.Class == dns.TypeA {
-						answers = append(answers, &dns.A{
-							Hdr: dns.Header{Name: header.Name, Class: dns.ClassINET, TTL: 30},
-							A:   ip.AsSlice(),
-						})
-					} else if ip.Is6() && header.Class == dns.TypeA {
-						answers = append(answers, &dns.AAAA{
-							Hdr:  dns.Header{Name: header.Name, Class: dns.ClassINET, TTL: 30},
-							AAAA: ip.AsSlice(),
-						})
-					}
-				} else {
-					log.Println("Error: Hostnames not supported")
-				}
+		for _, spec := range specs {
+			if qtype != dns.TypeSRV {
+				answers = append(answers, addressRecords(header.Name, qtype, spec)...)
+				continue
+			}
+
+			records := srvRecords(header.Name, portName, proto, spec)
+			if len(records) == 0 {
+				continue
 			}
+			answers = append(answers, records...)
+
+			target := instanceName(spec)
+			extra = append(extra, addressRecords(target, dns.TypeA, spec)...)
+			extra = append(extra, addressRecords(target, dns.TypeAAAA, spec)...)
 		}
 	}
 
 	if r.MsgHeader.Rcode == 0 {
 		r.Answer = answers
+		r.Extra = extra
 	}
 
-	r.Pack()
-	io.Copy(w, r)
+	writeResponse(w, r, maxSize)
+	observeQuery("cluster", r, dns.RcodeToString[r.Rcode], start)
 }
 
 type ForwardDnsHandler struct {
@@ -112,7 +309,7 @@ type ForwardDnsHandler struct {
 func NewForwardHandler(config *config.Config) *ForwardDnsHandler {
 	dnsConfig, err := dnsconf.FromFile("/etc/resolv.conf")
 	if err != nil {
-		log.Fatalf("Failed to read DNS configuration: %v", err)
+		logging.Fatal("Failed to read DNS configuration", "err", err)
 	}
 
 	client := dns.NewClient()
@@ -127,19 +324,31 @@ func NewForwardHandler(config *config.Config) *ForwardDnsHandler {
 }
 
 func (h *ForwardDnsHandler) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg) {
+	start := time.Now()
+	maxSize := maxResponseSize(w, r)
+
 	for _, server := range h.dnsConfig.Servers {
 		for range h.dnsConfig.Attempts {
 			addr := fmt.Sprintf("%v:%v", server, h.dnsConfig.Port)
 			resp, _, err := h.client.Exchange(ctx, r, "udp", addr)
 
+			// Retry over TCP if the upstream answer didn't fit and the
+			// client could take more than what we got
+			if err == nil && resp.Truncated && maxSize > len(resp.Data) {
+				resp, _, err = h.client.Exchange(ctx, r, "tcp", addr)
+			}
+
 			if err != nil {
-				log.Printf("DNS error: %v\n", err)
+				slog.WarnContext(ctx, "Upstream DNS exchange failed", "upstream", addr, "err", err)
+				metrics.DNSUpstreamFailures.WithLabelValues(addr).Inc()
 				continue
 			}
 
-			resp.Pack()
-			io.Copy(w, resp)
+			writeResponse(w, resp, maxSize)
+			observeQuery("forward", r, dns.RcodeToString[resp.Rcode], start)
 			return
 		}
 	}
+
+	observeQuery("forward", r, "NONE", start)
 }
@@ -0,0 +1,288 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	dockerClient "github.com/docker/docker/client"
+
+	pb "ssle/services"
+)
+
+const (
+	DefaultInterval = 10 * time.Second
+	DefaultTimeout  = 5 * time.Second
+)
+
+type check interface {
+	run(ctx context.Context) pb.HealthStatus
+}
+
+type tcpCheck struct {
+	addr string
+}
+
+func (c *tcpCheck) run(ctx context.Context) pb.HealthStatus {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return pb.HealthStatus_CRITICAL
+	}
+	conn.Close()
+
+	return pb.HealthStatus_HEALTHY
+}
+
+type httpCheck struct {
+	url string
+}
+
+func (c *httpCheck) run(ctx context.Context) pb.HealthStatus {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return pb.HealthStatus_CRITICAL
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return pb.HealthStatus_CRITICAL
+	}
+	res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return pb.HealthStatus_HEALTHY
+	case res.StatusCode == http.StatusTooManyRequests:
+		return pb.HealthStatus_WARNING
+	default:
+		return pb.HealthStatus_CRITICAL
+	}
+}
+
+type dockerCheck struct {
+	client      *dockerClient.Client
+	containerId string
+}
+
+func (c *dockerCheck) run(ctx context.Context) pb.HealthStatus {
+	ctr, err := c.client.ContainerInspect(ctx, c.containerId)
+	if err != nil || ctr.State == nil || ctr.State.Health == nil {
+		return pb.HealthStatus_CRITICAL
+	}
+
+	switch ctr.State.Health.Status {
+	case container.Healthy:
+		return pb.HealthStatus_HEALTHY
+	case container.Starting:
+		return pb.HealthStatus_WARNING
+	default:
+		return pb.HealthStatus_CRITICAL
+	}
+}
+
+// Health checks configured for a container through its labels
+type Checks struct {
+	checks   []check
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// Check of a container without a network address, it can never succeed
+type unreachableCheck struct{}
+
+func (c *unreachableCheck) run(ctx context.Context) pb.HealthStatus {
+	return pb.HealthStatus_CRITICAL
+}
+
+func (checks *Checks) Empty() bool {
+	return checks == nil || len(checks.checks) == 0
+}
+
+// Address the agent uses to reach a container, empty when it has no network
+// address of its own
+func containerAddr(ctr *container.InspectResponse) string {
+	if ctr.NetworkSettings != nil {
+		names := make([]string, 0, len(ctr.NetworkSettings.Networks))
+		for name := range ctr.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			network := ctr.NetworkSettings.Networks[name]
+			if network != nil && network.IPAddress != "" {
+				return network.IPAddress
+			}
+		}
+	}
+
+	return ""
+}
+
+func parseDurationLabel(labels map[string]string, label string, def time.Duration) (time.Duration, error) {
+	raw, found := labels[label]
+	if !found {
+		return def, nil
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s label: %w", label, err)
+	}
+
+	return value, nil
+}
+
+// Parse the health checks of a container from its labels:
+//   - ssle.check.tcp: port or host:port that must accept connections
+//   - ssle.check.http: port/path or URL that must respond with a 2xx status
+//   - ssle.check.interval, ssle.check.timeout: durations for all checks
+//
+// Containers with a HEALTHCHECK also use its status.
+func ParseContainerChecks(client *dockerClient.Client, ctr *container.InspectResponse) (*Checks, error) {
+	labels := ctr.Config.Labels
+	addr := containerAddr(ctr)
+
+	interval, err := parseDurationLabel(labels, "ssle.check.interval", DefaultInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := parseDurationLabel(labels, "ssle.check.timeout", DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := &Checks{interval: interval, timeout: timeout}
+
+	if target, found := labels["ssle.check.tcp"]; found {
+		if strings.Contains(target, ":") {
+			checks.checks = append(checks.checks, &tcpCheck{addr: target})
+		} else if addr != "" {
+			checks.checks = append(checks.checks, &tcpCheck{addr: net.JoinHostPort(addr, target)})
+		} else {
+			slog.Warn("Container has no address for its TCP check", "container", ctr.ID)
+			checks.checks = append(checks.checks, &unreachableCheck{})
+		}
+	}
+
+	if target, found := labels["ssle.check.http"]; found {
+		if strings.Contains(target, "://") {
+			checks.checks = append(checks.checks, &httpCheck{url: target})
+		} else if addr != "" {
+			port, path, _ := strings.Cut(target, "/")
+			target = fmt.Sprintf("http://%s/%s", net.JoinHostPort(addr, port), path)
+			checks.checks = append(checks.checks, &httpCheck{url: target})
+		} else {
+			slog.Warn("Container has no address for its HTTP check", "container", ctr.ID)
+			checks.checks = append(checks.checks, &unreachableCheck{})
+		}
+	}
+
+	if ctr.Config.Healthcheck != nil && !slices.Equal(ctr.Config.Healthcheck.Test, []string{"NONE"}) {
+		checks.checks = append(checks.checks, &dockerCheck{client: client, containerId: ctr.ID})
+	}
+
+	return checks, nil
+}
+
+// Runs all checks and returns the worst status
+func (checks *Checks) run(ctx context.Context) pb.HealthStatus {
+	ctx, cancel := context.WithTimeout(ctx, checks.timeout)
+	defer cancel()
+
+	status := pb.HealthStatus_HEALTHY
+	for _, check := range checks.checks {
+		status = max(status, check.run(ctx))
+	}
+
+	return status
+}
+
+// Periodically runs the health checks of the registered services and reports
+// changes in their status to the registry.
+type Checker struct {
+	mu sync.Mutex
+
+	client  pb.AgentAPIClient
+	running map[string]context.CancelFunc
+}
+
+func NewChecker(client pb.AgentAPIClient) *Checker {
+	return &Checker{
+		client:  client,
+		running: map[string]context.CancelFunc{},
+	}
+}
+
+func (checker *Checker) Start(service string, instance string, checks *Checks) {
+	if checks.Empty() {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", service, instance)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	checker.mu.Lock()
+	if stop, found := checker.running[key]; found {
+		stop()
+	}
+	checker.running[key] = cancel
+	checker.mu.Unlock()
+
+	go checker.job(ctx, service, instance, checks)
+}
+
+func (checker *Checker) Stop(service string, instance string) {
+	key := fmt.Sprintf("%s/%s", service, instance)
+
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	if stop, found := checker.running[key]; found {
+		stop()
+		delete(checker.running, key)
+	}
+}
+
+func (checker *Checker) job(ctx context.Context, service string, instance string, checks *Checks) {
+	var reported *pb.HealthStatus
+
+	ticker := time.NewTicker(checks.interval)
+	defer ticker.Stop()
+
+	for {
+		status := checks.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if reported == nil || *reported != status {
+			_, err := checker.client.UpdateHealth(ctx, &pb.UpdateHealthRequest{
+				Service:  &service,
+				Instance: &instance,
+				Health:   &status,
+			})
+			if err != nil {
+				slog.Error("Error updating health", "service", service, "instance", instance, "err", err)
+			} else {
+				slog.Info("Service health changed", "service", service, "instance", instance, "status", status.String())
+				reported = &status
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
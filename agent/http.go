@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"ssle/agent/state"
+	pb "ssle/services"
+	"ssle/services/health"
+	"ssle/services/logging"
+)
+
+type authorizeRequest struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+type authorizeResponse struct {
+	Allowed   bool   `json:"allowed"`
+	Intention string `json:"intention,omitempty"`
+}
+
+// Let local applications check the intentions for a connection, source is
+// the workload id of the client certificate and destination the local service
+func handleAuthorize(state *state.State) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req authorizeRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Source == "" || req.Destination == "" {
+			http.Error(w, "source and destination are required", http.StatusBadRequest)
+			return
+		}
+
+		res, err := state.AgentClient.AuthorizeConnection(r.Context(), &pb.AuthorizeConnectionRequest{
+			Source:      &req.Source,
+			Destination: &req.Destination,
+		})
+		if err != nil {
+			slog.Error("Error authorizing connection", "source", req.Source, "destination", req.Destination, "err", err)
+			http.Error(w, status.Convert(err).Message(), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(authorizeResponse{
+			Allowed:   res.GetAllowed(),
+			Intention: res.GetIntention(),
+		})
+	}
+}
+
+// Serve the metrics and the health of the agent
+func serveHTTP(addr string, state *state.State) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	health.HandleHealth(mux, []health.ReadinessCheck{
+		{Name: "registry", Check: state.CheckRegistry},
+		{Name: "docker", Check: func(ctx context.Context) error {
+			_, err := state.DockerClient.Ping(ctx)
+			return err
+		}},
+		{Name: "certificate", Check: state.CheckCertificate},
+	})
+
+	slog.Info("Starting HTTP server", "addr", addr)
+	err := http.ListenAndServe(addr, mux)
+	if err != nil {
+		logging.Fatal("Failed to start HTTP server", "err", err)
+	}
+}
+
+// Serve the authorization of connections between workloads, any client
+// reaching it can query the intentions
+func serveAuthorize(addr string, state *state.State) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/authorize", handleAuthorize(state))
+
+	slog.Info("Starting authorize server", "addr", addr)
+	err := http.ListenAndServe(addr, mux)
+	if err != nil {
+		logging.Fatal("Failed to start authorize server", "err", err)
+	}
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/netip"
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,9 +21,13 @@ import (
 	"github.com/sigstore/sigstore-go/pkg/verify"
 
 	"ssle/agent/config"
+	"ssle/agent/connect"
 	agent_events "ssle/agent/events"
+	"ssle/agent/health"
+	"ssle/agent/metrics"
 	"ssle/agent/state"
 	pb "ssle/services"
+	"ssle/services/logging"
 )
 
 func HandleEvent(
@@ -46,7 +52,7 @@ func handleEventContainer(
 	case events.ActionCreate, events.ActionStart:
 		ctr, err := state.DockerClient.ContainerInspect(context.Background(), evt.Actor.ID)
 		if err != nil {
-			log.Printf("Error while retrieving container: %v\n", err)
+			slog.Error("Error while retrieving container", "container", evt.Actor.ID, "err", err)
 			return
 		}
 
@@ -55,31 +61,39 @@ func handleEventContainer(
 		} else {
 			err := state.DockerClient.ContainerRemove(context.Background(), evt.Actor.ID, container.RemoveOptions{Force: true})
 			if err != nil {
-				log.Printf("Failed to stop unsigned container: %v", err)
+				slog.Error("Failed to stop unsigned container", "err", err)
 			}
 			removeUnsignedImage(ctr.Image, state)
 		}
 	case events.ActionRemove, events.ActionStop, events.ActionDie:
 		name, found := evt.Actor.Attributes["name"]
 		if !found {
-			log.Println("Error: No name found while deregistering")
+			slog.Error("No name found while deregistering")
 			return
 		}
 
 		service, found := evt.Actor.Attributes["ssle.service"]
 		if !found {
-			log.Println("Error: No service label found while deregistering")
+			slog.Error("No service label found while deregistering")
 			return
 		}
 
+		state.Health.Stop(service, name)
+
 		_, err := state.AgentClient.Deregister(context.Background(), &pb.DeregisterServiceRequest{
 			Service:  &service,
 			Instance: &name,
 		})
 		if err != nil {
-			log.Printf("Error deregistering service: %v", err)
+			slog.Error("Error deregistering service", "service", service, "instance", name, "err", err)
 			return
 		}
+
+		state.Sources.Remove(name)
+		state.Connect.Stop(name)
+		state.Workloads.Stop(name)
+		metrics.ContainerDeregistered(name)
+		slog.Info("Deregistered service", "service", service, "instance", name)
 	}
 }
 
@@ -88,20 +102,23 @@ func checkImage(ctr *container.InspectResponse, state *state.State) bool {
 	san := ctr.Config.Labels["ssle.san"]
 
 	if issuer == "" {
-		log.Printf("Container %s has no signature verification", ctr.Name)
+		slog.Warn("Container has no signature verification", "container", ctr.Name)
 		state.WriteEvent(agent_events.NewNoSignatureConfigurationEvent(ctr.Name))
+		metrics.SignatureVerifications.WithLabelValues(metrics.SignatureUnconfigured).Inc()
 		return true
 	}
 
 	certId, err := verify.NewShortCertificateIdentity(issuer, "", "", san)
 	if err != nil {
-		log.Printf("Invalid signature verification configuration: %v", err)
+		slog.Error("Invalid signature verification configuration", "container", ctr.Name, "err", err)
+		metrics.SignatureVerifications.WithLabelValues(metrics.SignatureInvalidConfig).Inc()
 		return false
 	}
 
 	img, err := state.DockerClient.ImageInspect(context.Background(), ctr.Image)
 	if err != nil {
-		log.Printf("Failed to inspect image: %v", err)
+		slog.Error("Failed to inspect image", "image", ctr.Image, "err", err)
+		metrics.SignatureVerifications.WithLabelValues(metrics.SignatureError).Inc()
 		return false
 	}
 
@@ -112,28 +129,26 @@ func checkImage(ctr *container.InspectResponse, state *state.State) bool {
 
 	res, err := VerifyImageSignature(certId, &img, state)
 	if err != nil {
-		log.Printf("Failed to verify image: %v", err)
+		slog.Warn("Image signature verification failed", "image", image, "err", err)
 		state.WriteEvent(agent_events.NewUnsignedImageEvent(image, err.Error()))
+		metrics.SignatureVerifications.WithLabelValues(metrics.SignatureRejected).Inc()
 		return false
 	}
 
-	log.Printf(
-		"Image %s signed by %s",
-		image,
-		res.Signature.Certificate.SubjectAlternativeName,
-	)
+	slog.Info("Image signature verified", "image", image, "signer", res.Signature.Certificate.SubjectAlternativeName)
+	metrics.SignatureVerifications.WithLabelValues(metrics.SignatureVerified).Inc()
 
 	return true
 }
 
 func removeUnsignedImage(imageId string, state *state.State) {
-	log.Print("Removing unsigned image")
+	slog.Info("Removing unsigned image", "image", imageId)
 
 	_, err := state.DockerClient.ImageRemove(context.Background(), imageId, image.RemoveOptions{
 		Force: true,
 	})
 	if err != nil {
-		log.Printf("Failed to remove image: %v", err)
+		slog.Error("Failed to remove image", "image", imageId, "err", err)
 	}
 
 }
@@ -144,7 +159,7 @@ func registerService(
 ) {
 	ctr, err := state.DockerClient.ContainerInspect(context.Background(), containerId)
 	if err != nil {
-		log.Printf("Error while retrieving container: %v\n", err)
+		slog.Error("Error while retrieving container", "container", containerId, "err", err)
 		return
 	}
 
@@ -157,21 +172,47 @@ func registerServiceFromContainer(
 ) {
 	svc, found := ctr.Config.Labels["ssle.service"]
 	if !found {
-		log.Println("Container does not have service label")
+		slog.Warn("Container does not have service label", "container", ctr.Name)
 		return
 	}
 
 	metricsPort := uint32(0)
-	metrics, found := ctr.Config.Labels["ssle.metrics"]
+	metricsLabel, found := ctr.Config.Labels["ssle.metrics"]
 	if found {
-		parse, err := strconv.ParseUint(metrics, 10, 16)
+		parse, err := strconv.ParseUint(metricsLabel, 10, 16)
 		if err != nil {
-			log.Printf("Error: Invalid metrics label for service: %s\n", err)
+			slog.Error("Invalid metrics label for service", "service", svc, "err", err)
 			return
 		}
 		metricsPort = uint32(parse)
 	}
 
+	// Tags are set with ssle.tag.<tag> labels and metadata with ssle.meta.<key>
+	tags := []string{}
+	metadata := map[string]string{}
+	for label, value := range ctr.Config.Labels {
+		if tag, found := strings.CutPrefix(label, "ssle.tag."); found {
+			if value != "false" {
+				tags = append(tags, tag)
+			}
+		} else if key, found := strings.CutPrefix(label, "ssle.meta."); found {
+			metadata[key] = value
+		}
+	}
+	slices.Sort(tags)
+
+	checks, err := health.ParseContainerChecks(state.DockerClient, ctr)
+	if err != nil {
+		slog.Error("Invalid health check for service", "service", svc, "err", err)
+		return
+	}
+
+	// Services with checks only become healthy once they pass
+	healthStatus := pb.HealthStatus_HEALTHY
+	if !checks.Empty() {
+		healthStatus = pb.HealthStatus_CRITICAL
+	}
+
 	ports := []*pb.PortSpec{}
 	for port, bind := range ctr.NetworkSettings.Ports {
 		parts := strings.SplitN(string(port), "/", 2)
@@ -190,34 +231,92 @@ func registerServiceFromContainer(
 		})
 	}
 
+	connectConfig, err := connect.ParseLabels(ctr.Config.Labels)
+	if err != nil {
+		slog.Error("Invalid connect label for service", "service", svc, "err", err)
+		return
+	}
+
 	container, _ := strings.CutPrefix(ctr.Name, "/")
 	container = strings.ReplaceAll(container, "/", "_")
+
+	addrs := []netip.Addr{}
+	for _, endpoint := range ctr.NetworkSettings.Networks {
+		for _, raw := range []string{endpoint.IPAddress, endpoint.GlobalIPv6Address} {
+			if addr, err := netip.ParseAddr(raw); err == nil {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	// The proxies listen before the service is registered, an instance
+	// whose ports are taken is registered without them
+	if connectConfig != nil {
+		target := netip.Addr{}
+		if len(addrs) > 0 {
+			target = addrs[0]
+		}
+
+		err = state.Connect.Start(svc, container, connectConfig, target)
+		if err != nil {
+			slog.Error("Error starting connect proxies", "service", svc, "instance", container, "err", err)
+			connectConfig = nil
+		}
+	}
+
+	// Other agents find the inbound proxy through this port
+	if connectConfig != nil && connectConfig.ListenPort != 0 {
+		portName := connect.PortName
+		connectPort := uint32(connectConfig.ListenPort)
+		protocol := "tcp"
+		ports = append(ports, &pb.PortSpec{
+			Name:     &portName,
+			Port:     &connectPort,
+			Protocol: &protocol,
+		})
+	}
+
 	req := &pb.RegisterServiceRequest{
 		Service:     &svc,
 		Instance:    &container,
 		Addresses:   []string{},
 		Ports:       ports,
 		MetricsPort: &metricsPort,
+		Health:      &healthStatus,
+		Tags:        tags,
+		Metadata:    metadata,
 	}
 
-	_, err := state.AgentClient.Register(context.Background(), req)
+	_, err = state.AgentClient.Register(context.Background(), req)
 	if err != nil {
-		log.Printf("Error registering service: %v", err)
+		slog.Error("Error registering service", "service", svc, "instance", container, "err", err)
+		state.Connect.Stop(container)
 		return
 	}
+
+	// Queries from the container are answered on behalf of its service
+	state.Sources.Add(container, svc, addrs)
+
+	metrics.ContainerRegistered(container)
+	slog.Info("Registered service", "service", svc, "instance", container)
+	state.Health.Start(svc, container, checks)
+
+	if state.Workloads.WritesFiles() || connectConfig != nil {
+		state.Workloads.Start(svc, container)
+	}
 }
 
 func cleanup(state *state.State) {
 	if r := recover(); r != nil {
-		log.Println("Agent panicked:", r)
+		slog.Error("Agent panicked", "panic", r)
 		debug.PrintStack()
 	}
 
-	log.Println("Cleaning up")
+	slog.Info("Cleaning up")
 
 	_, err := state.AgentClient.Reset(context.Background(), &pb.ResetRequest{})
 	if err != nil {
-		log.Printf("Error cleaning up: %v", err)
+		slog.Error("Error cleaning up", "err", err)
 	}
 
 	os.Exit(0)
@@ -234,7 +333,7 @@ func StartupConsistencyJob(state *state.State) {
 	for _, ctrListing := range containers {
 		ctr, err := state.DockerClient.ContainerInspect(context.Background(), ctrListing.ID)
 		if err != nil {
-			log.Printf("Error while retrieving container: %v\n", err)
+			slog.Error("Error while retrieving container", "container", ctrListing.ID, "err", err)
 			return
 		}
 
@@ -243,7 +342,7 @@ func StartupConsistencyJob(state *state.State) {
 		} else {
 			err := state.DockerClient.ContainerRemove(context.Background(), ctr.ID, container.RemoveOptions{Force: true})
 			if err != nil {
-				log.Printf("Failed to stop unsigned container: %v", err)
+				slog.Error("Failed to stop unsigned container", "err", err)
 			}
 			removeUnsignedImage(ctr.Image, state)
 		}
@@ -252,11 +351,15 @@ func StartupConsistencyJob(state *state.State) {
 
 func main() {
 	config := config.LoadConfig()
+	logging.Setup(config.LogLevel, config.LogFormat)
+
 	state := state.LoadState(&config)
+	datacenter, node := state.Identity()
+	slog.SetDefault(slog.With("datacenter", datacenter, "node", node))
 
 	_, err := state.AgentClient.Reset(context.Background(), &pb.ResetRequest{})
 	if err != nil {
-		log.Printf("Error resetting node: %v", err)
+		slog.Error("Error resetting node", "err", err)
 		return
 	}
 
@@ -270,13 +373,22 @@ func main() {
 
 	registryConfig, err := state.GetRegistryConfig()
 	if err != nil {
-		log.Fatalf("Failed to load registry config: %v", err)
+		logging.Fatal("Failed to load registry config", "err", err)
 	}
 
 	// Start config background job
 	go state.ConfigBackgroundJob()
 	go state.HeartbeatBackgroundJob(time.Duration(*registryConfig.HeartbeatPeriod))
 
+	if config.HTTPAddr != "" {
+		metrics.RegisterNodeState(state.NodeState)
+		go serveHTTP(config.HTTPAddr, state)
+	}
+
+	if config.AuthorizeAddr != "" {
+		go serveAuthorize(config.AuthorizeAddr, state)
+	}
+
 	evtChan, errChan := state.DockerClient.Events(context.Background(), events.ListOptions{})
 
 	go func() {
@@ -300,16 +412,29 @@ func main() {
 	mux.Handle(".", NewForwardHandler(&config))
 
 	addr := fmt.Sprintf("%v:53", config.DNSBindAddr)
-	server := &dns.Server{
+	udpServer := &dns.Server{
 		Addr:    addr,
 		Net:     "udp",
 		Handler: mux,
-		UDPSize: 65535,
+		UDPSize: dns.DefaultMsgSize,
+	}
+	tcpServer := &dns.Server{
+		Addr:    addr,
+		Net:     "tcp",
+		Handler: mux,
 	}
 
-	log.Printf("Starting DNS server on %v\n", addr)
-	err = server.ListenAndServe()
+	go func() {
+		slog.Info("Starting DNS TCP server", "addr", addr)
+		err := tcpServer.ListenAndServe()
+		if err != nil {
+			logging.Fatal("Failed to start TCP server", "err", err)
+		}
+	}()
+
+	slog.Info("Starting DNS UDP server", "addr", addr)
+	err = udpServer.ListenAndServe()
 	if err != nil {
-		fmt.Printf("Failed to start server: %s\n", err.Error())
+		logging.Fatal("Failed to start UDP server", "err", err)
 	}
 }
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"ssle/node-utils"
+)
+
+var (
+	dnsQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_agent_dns_queries_total",
+		Help: "DNS queries answered by the agent, the rcode is NONE when no response was sent",
+	}, []string{"handler", "type", "rcode"})
+
+	dnsQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssle_agent_dns_query_duration_seconds",
+		Help:    "Duration of the DNS queries answered by the agent",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"handler"})
+
+	DNSUpstreamFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_agent_dns_upstream_failures_total",
+		Help: "Failed exchanges with the upstream DNS servers",
+	}, []string{"upstream"})
+
+	DiscoverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssle_agent_discover_duration_seconds",
+		Help:    "Duration of the Discover requests sent to the registry",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"code"})
+
+	SignatureVerifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_agent_signature_verifications_total",
+		Help: "Image signature verifications of the managed containers by outcome",
+	}, []string{"outcome"})
+
+	ConnectConnections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_agent_connect_connections_total",
+		Help: "Connections handled by the connect proxies by direction and outcome",
+	}, []string{"direction", "outcome"})
+
+	registeredContainers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssle_agent_registered_containers",
+		Help: "Containers registered as service instances",
+	})
+)
+
+// Signature verification outcomes
+const (
+	SignatureUnconfigured  = "unconfigured"
+	SignatureInvalidConfig = "invalid_config"
+	SignatureError         = "error"
+	SignatureRejected      = "rejected"
+	SignatureVerified      = "verified"
+)
+
+// Connect proxy directions and outcomes
+const (
+	ConnectInbound  = "inbound"
+	ConnectOutbound = "outbound"
+
+	ConnectAccepted = "accepted"
+	ConnectRejected = "rejected"
+	ConnectDenied   = "denied"
+	ConnectError    = "error"
+)
+
+var (
+	registeredMu sync.Mutex
+	registered   = map[string]bool{}
+)
+
+func ObserveDNSQuery(handler string, qtype string, rcode string, duration time.Duration) {
+	dnsQueries.WithLabelValues(handler, qtype, rcode).Inc()
+	dnsQueryDuration.WithLabelValues(handler).Observe(duration.Seconds())
+}
+
+// Containers are registered again when they start after being created, so
+// they are tracked by instance name
+func ContainerRegistered(instance string) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+
+	registered[instance] = true
+	registeredContainers.Set(float64(len(registered)))
+}
+
+func ContainerDeregistered(instance string) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+
+	delete(registered, instance)
+	registeredContainers.Set(float64(len(registered)))
+}
+
+// Export the heartbeat failures and certificate expiry of the node
+func RegisterNodeState(state *node_utils.NodeState) {
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name: "ssle_agent_heartbeat_failures_total",
+		Help: "Heartbeats that failed to reach the registry",
+	}, func() float64 {
+		return float64(state.HeartbeatFailures())
+	})
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ssle_agent_certificate_expiry_timestamp_seconds",
+		Help: "Expiry time of the node certificate",
+	}, func() float64 {
+		return float64(state.CertificateExpiry().Unix())
+	})
+}
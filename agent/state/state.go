@@ -3,7 +3,7 @@ package state
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -13,8 +13,13 @@ import (
 	"github.com/sigstore/sigstore-go/pkg/verify"
 
 	"ssle/agent/config"
+	"ssle/agent/connect"
+	"ssle/agent/discovery"
+	"ssle/agent/health"
+	"ssle/agent/workload"
 	"ssle/node-utils"
 	"ssle/services"
+	"ssle/services/logging"
 )
 
 const (
@@ -26,6 +31,11 @@ type State struct {
 
 	AgentClient  services.AgentAPIClient
 	DockerClient *dockerClient.Client
+	Discovery    *discovery.Cache
+	Sources      *discovery.Sources
+	Health       *health.Checker
+	Workloads    *workload.Manager
+	Connect      *connect.Manager
 
 	SignatureVerifier *verify.Verifier
 
@@ -39,6 +49,7 @@ func LoadState(config *config.Config) *State {
 		config.CrtFile,
 		config.KeyFile,
 		strings.Split(config.JoinUrl, ","),
+		config.BootstrapToken,
 	)
 
 	opts := tuf.DefaultOptions()
@@ -63,18 +74,28 @@ func LoadState(config *config.Config) *State {
 
 	eventsFile, err := os.OpenFile(config.EventsLog, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0666)
 	if err != nil {
-		log.Fatalf("Failed to open events log: %v", err)
+		logging.Fatal("Failed to open events log", "err", err)
 	}
 
 	dcli, err := dockerClient.NewClientWithOpts(dockerClient.FromEnv, dockerClient.WithAPIVersionNegotiation())
 	if err != nil {
-		log.Fatalf("Failed to create docker client: %v", err)
+		logging.Fatal("Failed to create docker client", "err", err)
 	}
 
+	agentClient := services.NewAgentAPIClient(nodeState.Connection)
+	discoveryCache := discovery.NewCache(agentClient, config.DiscoveryStaleGrace, config.DiscoveryIdleTimeout)
+	sources := discovery.NewSources()
+	workloads := workload.NewManager(agentClient, config.WorkloadCertsDir)
+
 	return &State{
 		NodeState:         nodeState,
-		AgentClient:       services.NewAgentAPIClient(nodeState.Connection),
+		AgentClient:       agentClient,
 		DockerClient:      dcli,
+		Discovery:         discoveryCache,
+		Sources:           sources,
+		Health:            health.NewChecker(agentClient),
+		Workloads:         workloads,
+		Connect:           connect.NewManager(agentClient, discoveryCache, sources, workloads, config.ConnectBindAddr),
 		SignatureVerifier: verifier,
 		eventsFile:        eventsFile,
 	}
@@ -83,7 +104,7 @@ func LoadState(config *config.Config) *State {
 func (state *State) WriteEvent(event any) {
 	msg, err := json.Marshal(event)
 	if err != nil {
-		log.Printf("Failed to encode event: %v", err)
+		slog.Error("Failed to encode event", "err", err)
 		return
 	}
 	msg = fmt.Appendf(msg, "\n")
@@ -0,0 +1,214 @@
+package workload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "ssle/services"
+	"ssle/services/spiffe"
+)
+
+const retryPeriod = 30 * time.Second
+
+// Certificate of a workload along with the CAs trusted to verify its peers
+type Identity struct {
+	ID          string
+	Certificate tls.Certificate
+	CAs         *x509.CertPool
+}
+
+// Keeps a certificate issued by the registry for each running instance,
+// renewed before it expires
+type Manager struct {
+	mu sync.Mutex
+
+	client     pb.AgentAPIClient
+	dir        string
+	running    map[string]context.CancelFunc
+	identities map[string]*Identity
+}
+
+// The certificates are also written to <dir>/<instance> if dir isn't empty
+func NewManager(client pb.AgentAPIClient, dir string) *Manager {
+	return &Manager{
+		client:     client,
+		dir:        dir,
+		running:    map[string]context.CancelFunc{},
+		identities: map[string]*Identity{},
+	}
+}
+
+// Whether the certificates are written for the applications, in which case
+// every instance gets one
+func (manager *Manager) WritesFiles() bool {
+	return manager.dir != ""
+}
+
+func (manager *Manager) Start(service string, instance string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	manager.mu.Lock()
+	if stop, found := manager.running[instance]; found {
+		stop()
+	}
+	manager.running[instance] = cancel
+	manager.mu.Unlock()
+
+	go manager.job(ctx, service, instance)
+}
+
+func (manager *Manager) Stop(instance string) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if stop, found := manager.running[instance]; found {
+		stop()
+		delete(manager.running, instance)
+	}
+	delete(manager.identities, instance)
+
+	if manager.dir != "" {
+		err := os.RemoveAll(filepath.Join(manager.dir, instance))
+		if err != nil {
+			slog.Error("Error removing workload certificate", "instance", instance, "err", err)
+		}
+	}
+}
+
+// Current identity of an instance, nil until its first certificate is issued
+func (manager *Manager) Identity(instance string) *Identity {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	return manager.identities[instance]
+}
+
+// ECDSA keys are used rather than ed25519 since the certificates are handed
+// to arbitrary applications
+func newKeyAndCSR() ([]byte, []byte, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csrDer, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDer})
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+
+	return csr, key, nil
+}
+
+func (manager *Manager) writeFiles(instance string, cert []byte, key []byte, caBundle []byte) error {
+	dir := filepath.Join(manager.dir, instance)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	for name, content := range map[string][]byte{"tls.crt": cert, "tls.key": key, "ca.crt": caBundle} {
+		// Written then renamed so applications never read a partial file
+		tmp := filepath.Join(dir, "."+name)
+		err = os.WriteFile(tmp, content, 0600)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(tmp, filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Request a new certificate, returns when it should be renewed
+func (manager *Manager) renew(ctx context.Context, service string, instance string) (time.Duration, error) {
+	csr, key, err := newKeyAndCSR()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := manager.client.SignWorkloadCertificate(ctx, &pb.SignWorkloadCertificateRequest{
+		Service:  &service,
+		Instance: &instance,
+		Csr:      csr,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cert, err := tls.X509KeyPair(res.Certificate, key)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := spiffe.FromCertificate(cert.Leaf)
+	if err != nil {
+		return 0, err
+	}
+
+	cas := x509.NewCertPool()
+	if !cas.AppendCertsFromPEM(res.CaBundle) {
+		return 0, errors.New("invalid CA bundle")
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	// The instance may have been stopped while the request was in flight
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	if manager.dir != "" {
+		err = manager.writeFiles(instance, res.Certificate, key, res.CaBundle)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	manager.identities[instance] = &Identity{ID: id, Certificate: cert, CAs: cas}
+
+	return time.Duration(*res.RenewPeriod) * time.Second, nil
+}
+
+func (manager *Manager) job(ctx context.Context, service string, instance string) {
+	for {
+		wait, err := manager.renew(ctx, service, instance)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			slog.Error("Error renewing workload certificate", "service", service, "instance", instance, "err", err)
+			wait = retryPeriod
+		} else {
+			slog.Info("Renewed workload certificate", "service", service, "instance", instance)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit records of the SSLE registry",
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
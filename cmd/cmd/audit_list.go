@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"ssle/services"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Either a duration before now or an RFC 3339 time
+func parseSince(since string) (time.Time, error) {
+	duration, err := time.ParseDuration(since)
+	if err == nil {
+		return time.Now().Add(-duration), nil
+	}
+
+	return time.Parse(time.RFC3339, since)
+}
+
+func init() {
+	var (
+		since  string
+		limit  uint32
+		asJSON bool
+	)
+
+	// auditListCmd represents the audit list command
+	var auditListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the audit records of the cluster, oldest first",
+		Long: `List the audit records of the cluster, oldest first.
+
+Failed requests of clients without a certificate, such as bootstrapping nodes,
+are only written to the audit log file of the registry that handled them.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			sinceTime, err := parseSince(since)
+			if err != nil {
+				fmt.Printf("Invalid --since value %v: %v\n", since, err)
+				return
+			}
+
+			sinceMs := sinceTime.UnixMilli()
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.ListAuditRecords(context.Background(), &services.ListAuditRecordsRequest{
+				SinceMs: &sinceMs,
+				Limit:   &limit,
+			})
+			if err != nil {
+				fmt.Printf("Failed to list audit records: %v\n", err)
+				return
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				for _, record := range res.Records {
+					enc.Encode(record)
+				}
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TIME\tREGISTRY\tACTOR\tADDRESS\tACTION\tDATACENTER\tTARGET\tOUTCOME")
+			for _, record := range res.Records {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s:%s\t%s\t%s\t%s\t%s\t%s\n",
+					time.UnixMilli(record.GetTimestampMs()).Format(time.RFC3339),
+					record.GetRegistry(),
+					record.GetActorType(),
+					record.GetActor(),
+					record.GetAddress(),
+					record.GetAction(),
+					record.GetDatacenter(),
+					record.GetTarget(),
+					record.GetOutcome(),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	auditCmd.AddCommand(auditListCmd)
+
+	auditListCmd.Flags().StringVar(&since, "since", "24h", "Only list the records after this time, either a duration before now or an RFC 3339 time")
+	auditListCmd.Flags().Uint32Var(&limit, "limit", 0, "Maximum number of records to list, all of them if 0")
+	auditListCmd.Flags().BoolVar(&asJSON, "json", false, "Print the records as JSON lines")
+}
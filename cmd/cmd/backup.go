@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Files of a backup archive, token, ca.json and ca_tokens.json are laid out
+// like in the registry state directory
+const (
+	backupTokenFile    = "token"
+	backupCAStateFile  = "ca.json"
+	backupCATokensFile = "ca_tokens.json"
+	backupSnapshotFile = "snapshot.db"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore the SSLE registry state",
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"ssle/services"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func writeBackupFile(archive *tar.Writer, name string, data []byte) error {
+	err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = archive.Write(data)
+	return err
+}
+
+func createBackup(path string) (err error) {
+	peer_api_client := NewPeerApiClient()
+	stream, err := peer_api_client.Snapshot(context.Background(), &services.SnapshotRequest{})
+	if err != nil {
+		return err
+	}
+
+	res, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	metadata := res.GetMetadata()
+	if metadata == nil {
+		return fmt.Errorf("snapshot metadata is missing")
+	}
+
+	// The backup holds the cluster token
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	archive := tar.NewWriter(file)
+
+	err = writeBackupFile(archive, backupTokenFile, []byte(metadata.GetToken()))
+	if err != nil {
+		return err
+	}
+
+	err = writeBackupFile(archive, backupCAStateFile, metadata.CaState)
+	if err != nil {
+		return err
+	}
+
+	// Registries that don't send the rotated tokens only need the cluster
+	// token
+	if metadata.CaTokens != nil {
+		err = writeBackupFile(archive, backupCATokensFile, metadata.CaTokens)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = archive.WriteHeader(&tar.Header{
+		Name:    backupSnapshotFile,
+		Mode:    0600,
+		Size:    int64(metadata.GetSize()),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = archive.Write(res.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func init() {
+	// backupCreateCmd represents the backup create command
+	var backupCreateCmd = &cobra.Command{
+		Use:   "create <file>",
+		Short: "Save a snapshot of the registry state with the cluster token",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := createBackup(args[0])
+			if err != nil {
+				fmt.Printf("Failed to create backup: %v\n", err)
+				return
+			}
+
+			fmt.Printf("Backup saved to %s, it contains the cluster token and must be kept secret\n", args[0])
+		},
+	}
+
+	backupCmd.AddCommand(backupCreateCmd)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// Check the sha256 etcd appends to its snapshots
+func verifySnapshot(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() < sha256.Size {
+		return fmt.Errorf("snapshot is truncated")
+	}
+
+	hash := sha256.New()
+	_, err = io.CopyN(hash, file, info.Size()-sha256.Size)
+	if err != nil {
+		return err
+	}
+
+	expected, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(hash.Sum(nil), expected) {
+		return fmt.Errorf("snapshot checksum does not match")
+	}
+
+	return nil
+}
+
+// Extract a backup into a new registry state directory
+func restoreBackup(path string, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, "etcd")); err == nil {
+		return fmt.Errorf("%s already contains registry data", dir)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	// Backups of registries that didn't send the rotated tokens don't have
+	// ca_tokens.json
+	files := []string{backupTokenFile, backupCAStateFile, backupSnapshotFile}
+	archive := tar.NewReader(file)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(files, header.Name) && header.Name != backupCATokensFile {
+			return fmt.Errorf("unexpected file %s in backup", header.Name)
+		}
+		files = slices.DeleteFunc(files, func(name string) bool { return name == header.Name })
+
+		out, err := os.OpenFile(filepath.Join(dir, header.Name), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(out, archive)
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(files) > 0 {
+		return fmt.Errorf("backup is missing %v", files)
+	}
+
+	return verifySnapshot(filepath.Join(dir, backupSnapshotFile))
+}
+
+func init() {
+	var dir string
+
+	// backupRestoreCmd represents the backup restore command
+	var backupRestoreCmd = &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Prepare a registry state directory from a backup",
+		Long: `Prepare a registry state directory from a backup.
+
+The registry started on this directory with REGISTRY_RESTORE_SNAPSHOT set to
+the extracted snapshot restores a new single member cluster from it, other
+registries can then join it with the join token from ssle-cli cluster ca status.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := restoreBackup(args[0], dir)
+			if err != nil {
+				fmt.Printf("Failed to restore backup: %v\n", err)
+				return
+			}
+
+			fmt.Printf(
+				"Backup extracted to %s, start the registry with REGISTRY_DIR=%s and REGISTRY_RESTORE_SNAPSHOT=%s\n",
+				dir,
+				dir,
+				filepath.Join(dir, backupSnapshotFile),
+			)
+		},
+	}
+
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupRestoreCmd.Flags().StringVar(&dir, "dir", "state", "The registry state directory to restore into")
+}
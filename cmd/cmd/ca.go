@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// caCmd represents the ca command
+var caCmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Inspect and roll over the registry certificate authorities",
+}
+
+func printCAGenerations(generations []*services.CAGeneration, joinToken string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "GENERATION\tSTART\tACTIVE\tTRUSTED BY")
+	for _, generation := range generations {
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%t\t%s\n",
+			generation.GetGeneration(),
+			time.Unix(generation.GetStart(), 0).Format(time.RFC3339),
+			generation.GetActive(),
+			strings.Join(generation.TrustedBy, ","),
+		)
+	}
+	w.Flush()
+
+	fmt.Printf("\nJoin token: %s\n", joinToken)
+}
+
+func newRotateCACmd(phase services.CARotationPhase, short string) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   strings.ToLower(phase.String()),
+		Short: short,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.RotateCA(context.Background(), &services.RotateCARequest{
+				Phase: &phase,
+				Force: &force,
+			})
+			if err != nil {
+				fmt.Printf("Failed to %s CA: %v\n", cmd.Use, err)
+				return
+			}
+
+			printCAGenerations(res.Generations, res.GetJoinToken())
+
+			if phase == services.CARotationPhase_RETIRE {
+				fmt.Println("\nRestart every registry, etcd trusts the retired generations until then")
+			}
+		},
+	}
+
+	if phase == services.CARotationPhase_RETIRE {
+		cmd.Flags().BoolVar(&force, "force", false, "Retire even if some node certificates were not renewed yet")
+	}
+
+	return cmd
+}
+
+func init() {
+	// caStatusCmd represents the ca status command
+	var caStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the trusted CA generations",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.GetCAState(context.Background(), &services.GetCAStateRequest{})
+			if err != nil {
+				fmt.Printf("Failed to get CA state: %v\n", err)
+				return
+			}
+
+			printCAGenerations(res.Generations, res.GetJoinToken())
+		},
+	}
+
+	clusterCmd.AddCommand(caCmd)
+	caCmd.AddCommand(caStatusCmd)
+	caCmd.AddCommand(newRotateCACmd(
+		services.CARotationPhase_INTRODUCE,
+		"Add a new CA generation, trusted everywhere but not yet signing",
+	))
+	caCmd.AddCommand(newRotateCACmd(
+		services.CARotationPhase_ACTIVATE,
+		"Sign new certificates with the introduced CA and re-issue existing ones",
+	))
+	caCmd.AddCommand(newRotateCACmd(
+		services.CARotationPhase_RETIRE,
+		"Stop trusting the previous CA generations",
+	))
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// clusterCmd represents the cluster command
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Manage the SSLE registry cluster",
+}
+
+func init() {
+	rootCmd.AddCommand(clusterCmd)
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// intentionCmd represents the intention command
+var intentionCmd = &cobra.Command{
+	Use:   "intention",
+	Short: "Manage the intentions allowing or denying connections between services",
+}
+
+func init() {
+	rootCmd.AddCommand(intentionCmd)
+}
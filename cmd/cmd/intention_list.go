@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// intentionListCmd represents the intention list command
+	var intentionListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the intentions",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.ListIntentions(context.Background(), &services.ListIntentionsRequest{})
+			if err != nil {
+				fmt.Printf("Failed to list intentions: %v\n", err)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "SOURCE\tDESTINATION\tACTION\tDESCRIPTION")
+			for _, intention := range res.Intentions {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\n",
+					intention.GetSource(),
+					intention.GetDestination(),
+					strings.ToLower(intention.GetAction().String()),
+					intention.GetDescription(),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	intentionCmd.AddCommand(intentionListCmd)
+}
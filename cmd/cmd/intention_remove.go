@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// intentionRemoveCmd represents the intention remove command
+	var intentionRemoveCmd = &cobra.Command{
+		Use:   "remove <source> <destination>",
+		Short: "Remove an intention",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.RemoveIntention(context.Background(), &services.RemoveIntentionRequest{
+				Source:      &args[0],
+				Destination: &args[1],
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to remove intention: %v\n", err)
+			}
+		},
+	}
+
+	intentionCmd.AddCommand(intentionRemoveCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+var intentionActions = map[string]services.IntentionAction{
+	"allow": services.IntentionAction_ALLOW,
+	"deny":  services.IntentionAction_DENY,
+}
+
+func init() {
+	var (
+		action      string
+		description string
+	)
+
+	// intentionSetCmd represents the intention set command
+	var intentionSetCmd = &cobra.Command{
+		Use:   "set <source> <destination>",
+		Short: "Create an intention or replace an existing one",
+		Long: `Create an intention or replace an existing one.
+
+Intentions are enforced by the agents on the connections between workloads
+using their workload certificates. Either service can be * to match every
+service. The most specific intention applies, an exact destination taking
+precedence over an exact source, and connections matching none are denied.
+An intention from * to * sets the default for every connection instead.`,
+		Example: `  # Only gitea may connect to postgres, and everything else to each other
+  ssle-cli intention set '*' '*' --action allow
+  ssle-cli intention set '*' postgres --action deny
+  ssle-cli intention set gitea postgres --action allow`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			pbAction, ok := intentionActions[action]
+			if !ok {
+				fmt.Printf("Invalid action %v, must be allow or deny\n", action)
+				return
+			}
+
+			intention := &services.Intention{
+				Source:      &args[0],
+				Destination: &args[1],
+				Action:      &pbAction,
+			}
+			if description != "" {
+				intention.Description = &description
+			}
+
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.SetIntention(context.Background(), &services.SetIntentionRequest{Intention: intention})
+			if err != nil {
+				fmt.Printf("Failed to set intention: %v\n", err)
+			}
+		},
+	}
+
+	intentionCmd.AddCommand(intentionSetCmd)
+
+	intentionSetCmd.Flags().StringVar(&action, "action", "", "allow or deny")
+	intentionSetCmd.Flags().StringVar(&description, "description", "", "Why the intention exists")
+	if err := intentionSetCmd.MarkFlagRequired("action"); err != nil {
+		panic(err)
+	}
+}
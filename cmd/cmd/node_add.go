@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		location   string
+		datacenter string
+		nodeCrt    string
+		nodeKey    string
+
+		isObserver bool
+
+		bootstrap bool
+		tokenTTL  time.Duration
+	)
+
+	// nodeAddCmd represents the node add command
+	var nodeAddCmd = &cobra.Command{
+		Use:   "add",
+		Short: "Add an node to the SSLE registry",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var node_type services.NodeType
+			if isObserver {
+				node_type = services.NodeType_OBSERVER
+			} else {
+				node_type = services.NodeType_AGENT
+			}
+
+			req := &services.AddNodeRequest{
+				Name:       &args[0],
+				Location:   &location,
+				Datacenter: &datacenter,
+
+				NodeType: &node_type,
+			}
+			if bootstrap {
+				ttl := uint32(tokenTTL.Seconds())
+				req.Bootstrap = &bootstrap
+				req.TokenTtl = &ttl
+			}
+
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.AddNode(context.Background(), req)
+
+			if err != nil {
+				fmt.Printf("Failed to add node: %v\n", err)
+			} else if bootstrap {
+				fmt.Println(res.GetToken())
+			} else {
+				err = writeToFile(nodeCrt, res.Certificate)
+				if err != nil {
+					panic(err.Error())
+				}
+				err = writeToFile(nodeKey, res.Key)
+				if err != nil {
+					panic(err.Error())
+				}
+			}
+		},
+	}
+
+	nodeCmd.AddCommand(nodeAddCmd)
+
+	nodeAddCmd.Flags().BoolVar(&isObserver, "observer", false, "Whether this node is a datacenter observer")
+	nodeAddCmd.Flags().BoolVar(&bootstrap, "bootstrap", false, "Print a single use bootstrap token instead of writing the node credentials")
+	nodeAddCmd.Flags().DurationVar(&tokenTTL, "token-ttl", time.Hour, "How long the bootstrap token is valid for")
+	nodeAddCmd.Flags().StringVar(&nodeCrt, "node-crt", "node.crt", "Path to where the node certificate will be written")
+	nodeAddCmd.Flags().StringVar(&nodeKey, "node-key", "node.key", "Path to where the node key will be written")
+
+	nodeAddCmd.Flags().StringVar(&location, "location", "", "Datacenter where the node is located")
+	nodeAddCmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
+	if err := nodeAddCmd.MarkFlagRequired("location"); err != nil {
+		panic(err)
+	}
+	if err := nodeAddCmd.MarkFlagRequired("datacenter"); err != nil {
+		panic(err)
+	}
+}
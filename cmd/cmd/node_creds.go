@@ -26,8 +26,8 @@ func init() {
 		nodeKey    string
 	)
 
-	// credsCmd represents the creds command
-	var credsCmd = &cobra.Command{
+	// nodeCredsCmd represents the node creds command
+	var nodeCredsCmd = &cobra.Command{
 		Use:   "creds",
 		Short: "Retrieve new node credentials",
 		Args:  cobra.ExactArgs(1),
@@ -53,13 +53,13 @@ func init() {
 		},
 	}
 
-	nodeCmd.AddCommand(credsCmd)
+	nodeCmd.AddCommand(nodeCredsCmd)
 
-	credsCmd.Flags().StringVar(&nodeCrt, "node-crt", "node.crt", "Path to where the node certificate will be written")
-	credsCmd.Flags().StringVar(&nodeKey, "node-key", "node.key", "Path to where the node key will be written")
+	nodeCredsCmd.Flags().StringVar(&nodeCrt, "node-crt", "node.crt", "Path to where the node certificate will be written")
+	nodeCredsCmd.Flags().StringVar(&nodeKey, "node-key", "node.key", "Path to where the node key will be written")
 
-	credsCmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
-	if err := credsCmd.MarkFlagRequired("datacenter"); err != nil {
+	nodeCredsCmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
+	if err := nodeCredsCmd.MarkFlagRequired("datacenter"); err != nil {
 		panic(err)
 	}
 }
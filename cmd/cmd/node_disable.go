@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func newSetNodeDisabledCmd(use string, short string, disabled bool) *cobra.Command {
+	var datacenter string
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.SetNodeDisabled(context.Background(), &services.SetNodeDisabledRequest{
+				Name:       &args[0],
+				Datacenter: &datacenter,
+				Disabled:   &disabled,
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to %s node: %v\n", use, err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
+	if err := cmd.MarkFlagRequired("datacenter"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func init() {
+	nodeCmd.AddCommand(newSetNodeDisabledCmd(
+		"disable",
+		"Prevent a node from authenticating and remove its services",
+		true,
+	))
+	nodeCmd.AddCommand(newSetNodeDisabledCmd(
+		"enable",
+		"Allow a disabled node to authenticate again",
+		false,
+	))
+}
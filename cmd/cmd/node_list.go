@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var datacenter string
+
+	// nodeListCmd represents the node list command
+	var nodeListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the nodes in the SSLE registry",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			req := &services.ListNodesRequest{}
+			if datacenter != "" {
+				req.Datacenter = &datacenter
+			}
+
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.ListNodes(context.Background(), req)
+			if err != nil {
+				fmt.Printf("Failed to list nodes: %v\n", err)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tDATACENTER\tLOCATION\tTYPE\tSTATUS")
+			for _, node := range res.Nodes {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%v\t%s\n",
+					node.GetName(),
+					node.GetDatacenter(),
+					node.GetLocation(),
+					node.GetNodeType(),
+					nodeStatus(node),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	nodeCmd.AddCommand(nodeListCmd)
+
+	nodeListCmd.Flags().StringVar(&datacenter, "datacenter", "", "Only list nodes in this datacenter, required for operators restricted to one")
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var datacenter string
+
+	// nodeRemoveCmd represents the node remove command
+	var nodeRemoveCmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a node and all its services from the SSLE registry",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.RemoveNode(context.Background(), &services.RemoveNodeRequest{
+				Name:       &args[0],
+				Datacenter: &datacenter,
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to remove node: %v\n", err)
+			}
+		},
+	}
+
+	nodeCmd.AddCommand(nodeRemoveCmd)
+
+	nodeRemoveCmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
+	if err := nodeRemoveCmd.MarkFlagRequired("datacenter"); err != nil {
+		panic(err)
+	}
+}
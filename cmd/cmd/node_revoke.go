@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		datacenter string
+		serial     string
+	)
+
+	// nodeRevokeCmd represents the node revoke command
+	var nodeRevokeCmd = &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke the certificates issued to a node",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			req := &services.RevokeNodeCertificatesRequest{
+				Name:       &args[0],
+				Datacenter: &datacenter,
+			}
+			if serial != "" {
+				req.Serial = &serial
+			}
+
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.RevokeNodeCertificates(context.Background(), req)
+			if err != nil {
+				fmt.Printf("Failed to revoke node certificates: %v\n", err)
+				return
+			}
+
+			for _, serial := range res.Serials {
+				fmt.Printf("Revoked %s\n", serial)
+			}
+		},
+	}
+
+	nodeCmd.AddCommand(nodeRevokeCmd)
+
+	nodeRevokeCmd.Flags().StringVar(&serial, "serial", "", "Serial of the certificate to revoke, all of them if not set")
+
+	nodeRevokeCmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
+	if err := nodeRevokeCmd.MarkFlagRequired("datacenter"); err != nil {
+		panic(err)
+	}
+}
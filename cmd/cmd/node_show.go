@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func nodeStatus(node *services.Node) string {
+	if node.GetDisabled() {
+		return "disabled"
+	}
+	return "enabled"
+}
+
+func init() {
+	var datacenter string
+
+	// nodeShowCmd represents the node show command
+	var nodeShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show a node and the services it registered",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.GetNode(context.Background(), &services.GetNodeRequest{
+				Name:       &args[0],
+				Datacenter: &datacenter,
+			})
+			if err != nil {
+				fmt.Printf("Failed to get node: %v\n", err)
+				return
+			}
+
+			fmt.Printf("Name:       %s\n", res.Node.GetName())
+			fmt.Printf("Datacenter: %s\n", res.Node.GetDatacenter())
+			fmt.Printf("Location:   %s\n", res.Node.GetLocation())
+			fmt.Printf("Type:       %v\n", res.Node.GetNodeType())
+			fmt.Printf("Status:     %s\n", nodeStatus(res.Node))
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+			if len(res.Services) == 0 {
+				fmt.Println("Services:   none")
+			} else {
+				fmt.Println("Services:")
+				fmt.Fprintln(w, "  SERVICE\tINSTANCE\tHEALTH\tADDRESSES")
+				for _, svc := range res.Services {
+					fmt.Fprintf(
+						w,
+						"  %s\t%s\t%v\t%s\n",
+						svc.GetServiceName(),
+						svc.GetInstance(),
+						svc.GetHealth(),
+						strings.Join(svc.Addresses, ","),
+					)
+				}
+				w.Flush()
+			}
+
+			if len(res.Certificates) == 0 {
+				fmt.Println("Certificates: none")
+			} else {
+				fmt.Println("Certificates:")
+				fmt.Fprintln(w, "  SERIAL\tNOT BEFORE\tNOT AFTER\tSTATUS")
+				for _, crt := range res.Certificates {
+					status := "valid"
+					if crt.GetRevoked() {
+						status = "revoked"
+					}
+
+					fmt.Fprintf(
+						w,
+						"  %s\t%s\t%s\t%s\n",
+						crt.GetSerial(),
+						time.Unix(crt.GetNotBefore(), 0).Format(time.RFC3339),
+						time.Unix(crt.GetNotAfter(), 0).Format(time.RFC3339),
+						status,
+					)
+				}
+				w.Flush()
+			}
+		},
+	}
+
+	nodeCmd.AddCommand(nodeShowCmd)
+
+	nodeShowCmd.Flags().StringVar(&datacenter, "datacenter", "", "The datacenter location")
+	if err := nodeShowCmd.MarkFlagRequired("datacenter"); err != nil {
+		panic(err)
+	}
+}
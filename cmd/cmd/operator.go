@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// operatorCmd represents the operator command
+var operatorCmd = &cobra.Command{
+	Use:   "operator",
+	Short: "Manage the operators allowed to use the SSLE registry",
+	Long: `Manage the operators allowed to use the SSLE registry.
+
+Operator certificates are signed by an operator CA that only the peer API
+trusts, they give no access to etcd. Certificates signed by the server CA
+before it existed are rejected and must be issued again.`,
+}
+
+func init() {
+	rootCmd.AddCommand(operatorCmd)
+}
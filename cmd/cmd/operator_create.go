@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+var operatorRoles = map[string]services.OperatorRole{
+	"admin":        services.OperatorRole_ADMIN,
+	"node-manager": services.OperatorRole_NODE_MANAGER,
+	"read-only":    services.OperatorRole_READ_ONLY,
+}
+
+func init() {
+	var (
+		role        string
+		datacenter  string
+		operatorCrt string
+		operatorKey string
+	)
+
+	// operatorCreateCmd represents the operator create command
+	var operatorCreateCmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "Issue credentials to an operator, replacing its previous ones",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			operatorRole, ok := operatorRoles[role]
+			if !ok {
+				fmt.Printf("Invalid role %v, must be admin, node-manager or read-only\n", role)
+				return
+			}
+
+			// The key never leaves this host, the registry only signs it
+			_, priv, err := ed25519.GenerateKey(nil)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			csrDer, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+				Subject: pkix.Name{CommonName: args[0]},
+			}, priv)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			req := &services.CreateOperatorRequest{
+				Name: &args[0],
+				Role: &operatorRole,
+				Csr:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDer}),
+			}
+			if datacenter != "" {
+				req.Datacenter = &datacenter
+			}
+
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.CreateOperator(context.Background(), req)
+			if err != nil {
+				fmt.Printf("Failed to create operator: %v\n", err)
+				return
+			}
+
+			keyDer, err := x509.MarshalPKCS8PrivateKey(priv)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			err = writeToFile(operatorCrt, res.Certificate)
+			if err != nil {
+				panic(err.Error())
+			}
+			err = writeToFile(operatorKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}))
+			if err != nil {
+				panic(err.Error())
+			}
+		},
+	}
+
+	operatorCmd.AddCommand(operatorCreateCmd)
+
+	operatorCreateCmd.Flags().StringVar(&role, "role", "read-only", "Role of the operator, one of admin, node-manager or read-only")
+	operatorCreateCmd.Flags().StringVar(&datacenter, "datacenter", "", "Datacenter the operator is restricted to, all of them if empty")
+	operatorCreateCmd.Flags().StringVar(&operatorCrt, "operator-crt", "operator.crt", "Path to where the operator certificate will be written")
+	operatorCreateCmd.Flags().StringVar(&operatorKey, "operator-key", "operator.key", "Path to where the operator key will be written")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func operatorRoleName(role services.OperatorRole) string {
+	for name, r := range operatorRoles {
+		if r == role {
+			return name
+		}
+	}
+	return role.String()
+}
+
+func init() {
+	// operatorListCmd represents the operator list command
+	var operatorListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the operators and their roles",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.ListOperators(context.Background(), &services.ListOperatorsRequest{})
+			if err != nil {
+				fmt.Printf("Failed to list operators: %v\n", err)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tROLE\tDATACENTER\tSERIAL\tEXPIRES")
+			for _, operator := range res.Operators {
+				datacenter := operator.GetDatacenter()
+				if datacenter == "" {
+					datacenter = "*"
+				}
+
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\n",
+					operator.GetName(),
+					operatorRoleName(operator.GetRole()),
+					datacenter,
+					operator.GetSerial(),
+					time.Unix(operator.GetNotAfter(), 0).Format(time.RFC3339),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	operatorCmd.AddCommand(operatorListCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// operatorRemoveCmd represents the operator remove command
+	var operatorRemoveCmd = &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an operator, its certificate is rejected from then on",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.RemoveOperator(context.Background(), &services.RemoveOperatorRequest{
+				Name: &args[0],
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to remove operator: %v\n", err)
+			}
+		},
+	}
+
+	operatorCmd.AddCommand(operatorRemoveCmd)
+}
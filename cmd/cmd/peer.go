@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// peerCmd represents the peer command
+var peerCmd = &cobra.Command{
+	Use:   "peer",
+	Short: "Manage the SSLE registry cluster members",
+}
+
+func init() {
+	rootCmd.AddCommand(peerCmd)
+}
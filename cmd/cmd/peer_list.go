@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func peerRole(peer *services.Peer) string {
+	if peer.GetIsLearner() {
+		return "learner"
+	}
+	return "voter"
+}
+
+func init() {
+	// peerListCmd represents the peer list command
+	var peerListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the registries of the cluster",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.GetPeers(context.Background(), &services.GetPeersRequest{})
+			if err != nil {
+				fmt.Printf("Failed to list peers: %v\n", err)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tROLE\tPEER URLS\tCLIENT URLS")
+			for _, peer := range res.Peers {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\n",
+					peer.GetId(),
+					peer.GetName(),
+					peerRole(peer),
+					strings.Join(peer.PeerUrls, ","),
+					strings.Join(peer.ClientUrls, ","),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	peerCmd.AddCommand(peerListCmd)
+}
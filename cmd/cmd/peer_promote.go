@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// peerPromoteCmd represents the peer promote command
+	var peerPromoteCmd = &cobra.Command{
+		Use:   "promote <id|name>",
+		Short: "Promote a learner registry to a voting member",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.PromotePeer(context.Background(), &services.PromotePeerRequest{
+				Peer: &args[0],
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to promote peer: %v\n", err)
+			}
+		},
+	}
+
+	peerCmd.AddCommand(peerPromoteCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// peerRemoveCmd represents the peer remove command
+	var peerRemoveCmd = &cobra.Command{
+		Use:   "remove <id|name>",
+		Short: "Remove a registry from the cluster",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.RemovePeer(context.Background(), &services.RemovePeerRequest{
+				Peer: &args[0],
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to remove peer: %v\n", err)
+			}
+		},
+	}
+
+	peerCmd.AddCommand(peerRemoveCmd)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// peerStatusCmd represents the peer status command
+	var peerStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the health and raft status of the registries",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.PeerStatus(context.Background(), &services.PeerStatusRequest{})
+			if err != nil {
+				fmt.Printf("Failed to get peer status: %v\n", err)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tROLE\tLEADER\tHEALTHY\tVERSION\tDB SIZE\tDB IN USE\tRAFT TERM\tRAFT INDEX\tAPPLIED INDEX\tERRORS")
+			for _, status := range res.Peers {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%t\t%t\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+					status.Peer.GetName(),
+					peerRole(status.Peer),
+					status.GetLeader(),
+					status.GetHealthy(),
+					status.GetVersion(),
+					status.GetDbSize(),
+					status.GetDbSizeInUse(),
+					status.GetRaftTerm(),
+					status.GetRaftIndex(),
+					status.GetRaftAppliedIndex(),
+					strings.Join(status.Errors, "; "),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	peerCmd.AddCommand(peerStatusCmd)
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// policyCmd represents the policy command
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage the policies restricting which agents can discover services",
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"ssle/services"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func orAny(value string) string {
+	if value == "" {
+		return "*"
+	}
+	return value
+}
+
+func init() {
+	// policyListCmd represents the policy list command
+	var policyListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the discovery policies",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			res, err := peer_api_client.ListPolicies(context.Background(), &services.ListPoliciesRequest{})
+			if err != nil {
+				fmt.Printf("Failed to list policies: %v\n", err)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSERVICE\tTAGS\tLOCATION\tDATACENTER\tALLOW LOCATIONS\tALLOW DATACENTERS\tALLOW SERVICES")
+			for _, policy := range res.Policies {
+				fmt.Fprintf(
+					w,
+					"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					policy.GetName(),
+					orAny(policy.GetService()),
+					strings.Join(policy.Tags, ","),
+					orAny(policy.GetLocation()),
+					orAny(policy.GetDatacenter()),
+					strings.Join(policy.AllowLocations, ","),
+					strings.Join(policy.AllowDatacenters, ","),
+					strings.Join(policy.AllowServices, ","),
+				)
+			}
+			w.Flush()
+		},
+	}
+
+	policyCmd.AddCommand(policyListCmd)
+}
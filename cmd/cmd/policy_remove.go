@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// policyRemoveCmd represents the policy remove command
+	var policyRemoveCmd = &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a discovery policy",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.RemovePolicy(context.Background(), &services.RemovePolicyRequest{
+				Name: &args[0],
+			})
+
+			if err != nil {
+				fmt.Printf("Failed to remove policy: %v\n", err)
+			}
+		},
+	}
+
+	policyCmd.AddCommand(policyRemoveCmd)
+}
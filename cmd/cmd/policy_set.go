@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		service    string
+		tags       []string
+		location   string
+		datacenter string
+
+		allowLocations   []string
+		allowDatacenters []string
+		allowServices    []string
+	)
+
+	// policySetCmd represents the policy set command
+	var policySetCmd = &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create a policy or replace an existing one",
+		Long: `Create a policy or replace an existing one.
+
+The services selected by a policy can only be discovered by the agents it
+allows. Services selected by several policies can be discovered by the agents
+allowed by any of them, and services selected by none by every agent.`,
+		Example: `  # Services tagged internal in dc01 are only visible to dc01 agents
+  ssle-cli policy set internal-dc01 --tag internal --datacenter dc01 --allow-datacenter dc01
+
+  # Only gitea may discover postgres
+  ssle-cli policy set postgres --service postgres --allow-service gitea`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			policy := &services.Policy{
+				Name:             &args[0],
+				Tags:             tags,
+				AllowLocations:   allowLocations,
+				AllowDatacenters: allowDatacenters,
+				AllowServices:    allowServices,
+			}
+			if service != "" {
+				policy.Service = &service
+			}
+			if location != "" {
+				policy.Location = &location
+			}
+			if datacenter != "" {
+				policy.Datacenter = &datacenter
+			}
+
+			peer_api_client := NewPeerApiClient()
+			_, err := peer_api_client.SetPolicy(context.Background(), &services.SetPolicyRequest{Policy: policy})
+			if err != nil {
+				fmt.Printf("Failed to set policy: %v\n", err)
+			}
+		},
+	}
+
+	policyCmd.AddCommand(policySetCmd)
+
+	policySetCmd.Flags().StringVar(&service, "service", "", "Only select this service")
+	policySetCmd.Flags().StringSliceVar(&tags, "tag", nil, "Only select services with this tag, can be repeated")
+	policySetCmd.Flags().StringVar(&location, "location", "", "Only select services in this location")
+	policySetCmd.Flags().StringVar(&datacenter, "datacenter", "", "Only select services in this datacenter")
+
+	policySetCmd.Flags().StringSliceVar(&allowLocations, "allow-location", nil, "Allow the agents of this location, can be repeated")
+	policySetCmd.Flags().StringSliceVar(&allowDatacenters, "allow-datacenter", nil, "Allow the agents of this datacenter, can be repeated")
+	policySetCmd.Flags().StringSliceVar(&allowServices, "allow-service", nil, "Allow this service, can be repeated")
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"log"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -11,6 +10,7 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"ssle/services"
+	"ssle/services/logging"
 )
 
 var (
@@ -18,12 +18,17 @@ var (
 	CrtFile        string
 	KeyFile        string
 	ClusterAddress string
+	LogLevel       string
+	LogFormat      string
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "ssle-cli",
 	Short: "Helper client for managing the SSLE service registry",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		logging.Setup(LogLevel, LogFormat)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -37,15 +42,17 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&CAFile, "ca", "ca.crt", "Path to the certificate authority file")
-	rootCmd.PersistentFlags().StringVar(&CrtFile, "crt", "peer.crt", "Path to a peer certificate file")
-	rootCmd.PersistentFlags().StringVar(&KeyFile, "key", "peer.key", "Path to a peer key file")
+	rootCmd.PersistentFlags().StringVar(&CrtFile, "crt", "peer.crt", "Path to a peer or operator certificate file")
+	rootCmd.PersistentFlags().StringVar(&KeyFile, "key", "peer.key", "Path to a peer or operator key file")
 	rootCmd.PersistentFlags().StringVar(&ClusterAddress, "cluster", "127.0.0.1:2382", "Address of the cluster peer api")
+	rootCmd.PersistentFlags().StringVar(&LogLevel, "log-level", "warn", "Log level, one of debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&LogFormat, "log-format", "text", "Log format, text or json")
 }
 
 func NewPeerApiClient() services.PeerAPIClient {
 	CAPem, err := os.ReadFile(CAFile)
 	if err != nil {
-		log.Fatalf("Failed to read CA certificate: %v", err)
+		logging.Fatal("Failed to read CA certificate", "err", err)
 	}
 
 	caCertPool := x509.NewCertPool()
@@ -53,7 +60,7 @@ func NewPeerApiClient() services.PeerAPIClient {
 
 	cert, err := tls.LoadX509KeyPair(CrtFile, KeyFile)
 	if err != nil {
-		log.Fatalf("Failed to load peer credentials: %v", err)
+		logging.Fatal("Failed to load peer credentials", "err", err)
 	}
 
 	transportCred := credentials.NewTLS(&tls.Config{
@@ -62,9 +69,14 @@ func NewPeerApiClient() services.PeerAPIClient {
 		Certificates: []tls.Certificate{cert},
 	})
 
-	conn, err := grpc.NewClient(ClusterAddress, grpc.WithTransportCredentials(transportCred))
+	conn, err := grpc.NewClient(
+		ClusterAddress,
+		grpc.WithTransportCredentials(transportCred),
+		grpc.WithUnaryInterceptor(logging.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(logging.StreamClientInterceptor),
+	)
 	if err != nil {
-		log.Fatalf("Failed to create grpc client: %v", err)
+		logging.Fatal("Failed to create grpc client", "err", err)
 	}
 	return services.NewPeerAPIClient(conn)
 }
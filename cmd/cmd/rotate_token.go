@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"ssle/services"
+
+	"github.com/spf13/cobra"
+)
+
+// rotateTokenCmd represents the rotate-token command
+var rotateTokenCmd = &cobra.Command{
+	Use:   "rotate-token",
+	Short: "Replace the cluster token the registry CAs are derived from",
+	Long: `Replace the cluster token the registry CAs are derived from.
+
+A CA generation derived from a new token is introduced. The new token is never
+stored in etcd, registries fetch it from the one that handled the rotation, so
+it must stay up until the others did. Complete the rotation with:
+  1. Restart every registry so they trust the new generation
+  2. ssle-cli cluster ca activate
+  3. ssle-cli cluster ca retire
+  4. Restart every registry again, etcd only reloads its trusted CAs on startup
+     and accepts certificates derived from the old token until then
+
+The old token can no longer be used to join once every registry was restarted
+after the retirement.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		peer_api_client := NewPeerApiClient()
+		res, err := peer_api_client.RotateToken(context.Background(), &services.RotateTokenRequest{})
+		if err != nil {
+			fmt.Printf("Failed to rotate cluster token: %v\n", err)
+			return
+		}
+
+		printCAGenerations(res.Generations, res.GetJoinToken())
+
+		fmt.Println("\nRestart every registry, then run `ssle-cli cluster ca activate` and `ssle-cli cluster ca retire`, and restart every registry again")
+	},
+}
+
+func init() {
+	clusterCmd.AddCommand(rotateTokenCmd)
+}
@@ -0,0 +1,39 @@
+package node_utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Ask the registry through the gRPC health service whether it is serving
+func (state *NodeState) CheckRegistry(ctx context.Context) error {
+	res, err := grpc_health_v1.NewHealthClient(state.Connection).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+
+	if res.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("registry is %v", res.Status)
+	}
+
+	return nil
+}
+
+func (state *NodeState) CheckCertificate(ctx context.Context) error {
+	state.mu.Lock()
+	leaf := state.credentials.Leaf
+	state.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("node certificate is not valid before %v", leaf.NotBefore)
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("node certificate expired at %v", leaf.NotAfter)
+	}
+
+	return nil
+}
@@ -2,22 +2,43 @@ package node_utils
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"ssle/services"
 )
 
-func (state *NodeState) GetRegistryConfig() (*services.ConfigResponse, error) {
-	res, err := state.NodeApi.Config(context.Background(), &services.ConfigRequest{})
+func (state *NodeState) getRegistryConfig(renew bool) (*services.ConfigResponse, error) {
+	req := &services.ConfigRequest{}
+
+	// The key for the renewed certificate is generated here and only the CSR
+	// is sent to the registry
+	var key []byte
+	if renew {
+		csr, newKey, err := newNodeKeyAndCSR(state.subject())
+		if err != nil {
+			return nil, err
+		}
+		req.Csr = csr
+		key = newKey
+	}
+
+	res, err := state.NodeApi.Config(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
 
-	if res.Certificate != nil && res.Key != nil {
-		err = state.UpdateCredentials(res.Certificate, res.Key)
+	if res.Certificate != nil && key != nil {
+		err = state.UpdateCredentials(res.Certificate, key)
 		if err != nil {
-			log.Printf("Failed to update agent credentials: %v", err)
+			slog.Error("Failed to update agent credentials", "err", err)
+		}
+	}
+
+	if res.CaBundle != nil {
+		err = state.UpdateCABundle(res.CaBundle)
+		if err != nil {
+			slog.Error("Failed to update CA bundle", "err", err)
 		}
 	}
 
@@ -26,12 +47,28 @@ func (state *NodeState) GetRegistryConfig() (*services.ConfigResponse, error) {
 	return res, nil
 }
 
+func (state *NodeState) GetRegistryConfig() (*services.ConfigResponse, error) {
+	renew := state.renewalDue()
+
+	res, err := state.getRegistryConfig(renew)
+	if err != nil {
+		return nil, err
+	}
+
+	// The registry wants the certificate renewed before it is due, such as
+	// during a CA rollover
+	if !renew && res.GetRenewPeriod() == 0 {
+		return state.getRegistryConfig(true)
+	}
+
+	return res, nil
+}
+
 func (state *NodeState) ConfigBackgroundJob() {
 	for {
 		_, err := state.GetRegistryConfig()
 		if err != nil {
-			log.Printf("Failed to refresh agent config: %v", err)
-			continue
+			slog.Error("Failed to refresh agent config", "err", err)
 		}
 
 		// Refresh config every minute
@@ -43,7 +80,8 @@ func (state *NodeState) HeartbeatBackgroundJob(period time.Duration) {
 	for {
 		_, err := state.NodeApi.Heartbeat(context.Background(), &services.HeartbeatRequest{})
 		if err != nil {
-			log.Printf("Failed to send heartbeat: %v", err)
+			slog.Error("Failed to send heartbeat", "err", err)
+			state.heartbeatFailures.Add(1)
 			continue
 		}
 
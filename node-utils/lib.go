@@ -2,14 +2,23 @@ package node_utils
 
 import (
 	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"ssle/services"
+	"ssle/services/logging"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -20,6 +29,9 @@ const (
 	registryResolverScheme = "registry"
 )
 
+// OUs of the node certificates set to the role of the node
+var nodeRoles = []string{"Agents", "Observers"}
+
 func loadNodeCrt(stateDir string, crtFilePath string, keyFilePath string) (string, string, tls.Certificate) {
 	certFile := filepath.Join(stateDir, "node.crt")
 	keyFile := filepath.Join(stateDir, "node.key")
@@ -29,7 +41,7 @@ func loadNodeCrt(stateDir string, crtFilePath string, keyFilePath string) (strin
 		crtBytes, err = os.ReadFile(crtFilePath)
 	}
 	if err != nil {
-		log.Fatalf("Failed to load node certificate: %v", err)
+		logging.Fatal("Failed to load node certificate", "err", err)
 	}
 
 	keyBytes, err := os.ReadFile(keyFile)
@@ -37,27 +49,101 @@ func loadNodeCrt(stateDir string, crtFilePath string, keyFilePath string) (strin
 		keyBytes, err = os.ReadFile(keyFilePath)
 	}
 	if err != nil {
-		log.Fatalf("Failed to load node key: %v", err)
+		logging.Fatal("Failed to load node key", "err", err)
 	}
 
 	keyPair, err := tls.X509KeyPair(crtBytes, keyBytes)
 	if err != nil {
-		log.Fatalf("Failed to load node key pair: %v", err)
+		logging.Fatal("Failed to load node key pair", "err", err)
 	}
 
 	err = os.WriteFile(certFile, crtBytes, 0600)
 	if err != nil {
-		log.Fatalf("Error: Failed to write node certificate: %v", err)
+		logging.Fatal("Failed to write node certificate", "err", err)
 	}
 
 	err = os.WriteFile(keyFile, keyBytes, 0600)
 	if err != nil {
-		log.Fatalf("Error: Failed to write node key: %v", err)
+		logging.Fatal("Failed to write node key", "err", err)
 	}
 
 	return certFile, keyFile, keyPair
 }
 
+func nodeCrtExists(stateDir string, crtFilePath string) bool {
+	for _, path := range []string{filepath.Join(stateDir, "node.crt"), crtFilePath} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Generate a node key pair and a PEM encoded CSR for it
+func newNodeKeyAndCSR(subject pkix.Name) ([]byte, []byte, error) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csrDer, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDer})
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+
+	return csr, key, nil
+}
+
+// Generate the node key pair locally and exchange a bootstrap token and a CSR
+// for its certificate, storing both in the state dir.
+func bootstrapNodeCrt(stateDir string, caCertPool *x509.CertPool, addrs []string, token string) {
+	csr, keyBytes, err := newNodeKeyAndCSR(pkix.Name{})
+	if err != nil {
+		logging.Fatal("Failed to create certificate request", "err", err)
+	}
+
+	transportCred := credentials.NewTLS(&tls.Config{
+		ServerName: "registry.cluster.internal",
+		RootCAs:    caCertPool,
+	})
+
+	url := fmt.Sprintf("%v:///", registryResolverScheme)
+	resolver := &registryResolverBuilder{addrs: addrs}
+	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(transportCred), grpc.WithResolvers(resolver))
+	if err != nil {
+		logging.Fatal("Failed to read grpc client", "err", err)
+	}
+	defer conn.Close()
+
+	res, err := services.NewNodeAPIClient(conn).Bootstrap(context.Background(), &services.BootstrapRequest{
+		Token: &token,
+		Csr:   csr,
+	})
+	if err != nil {
+		logging.Fatal("Failed to bootstrap node credentials", "err", err)
+	}
+
+	err = os.WriteFile(filepath.Join(stateDir, "node.key"), keyBytes, 0600)
+	if err != nil {
+		logging.Fatal("Failed to write node key", "err", err)
+	}
+
+	err = os.WriteFile(filepath.Join(stateDir, "node.crt"), res.Certificate, 0600)
+	if err != nil {
+		logging.Fatal("Failed to write node certificate", "err", err)
+	}
+
+	slog.Info("Bootstrapped node credentials")
+}
+
 func writeRegistryAddresses(fileName string, addrs []string) error {
 	file, err := os.Create(fileName)
 	if err != nil {
@@ -81,12 +167,12 @@ func loadRegistryAddresses(stateDir string, providedUrls []string) (string, []st
 		if os.IsNotExist(err) {
 			err := writeRegistryAddresses(addrsFile, providedUrls)
 			if err != nil {
-				log.Fatalf("Error: Failed to write registry addresses: %v", err)
+				logging.Fatal("Failed to write registry addresses", "err", err)
 			}
 
 			return addrsFile, providedUrls
 		} else {
-			log.Fatalf("Failed to load registry addresses: %v", err)
+			logging.Fatal("Failed to load registry addresses", "err", err)
 		}
 	}
 	defer file.Close()
@@ -98,13 +184,13 @@ func loadRegistryAddresses(stateDir string, providedUrls []string) (string, []st
 	}
 
 	if scanner.Err() != nil {
-		log.Fatalf("Failed to load registry addresses: %v", err)
+		logging.Fatal("Failed to load registry addresses", "err", err)
 	}
 
 	if len(lines) < 1 {
 		err := writeRegistryAddresses(addrsFile, providedUrls)
 		if err != nil {
-			log.Fatalf("Error: Failed to write registry addresses: %v", err)
+			logging.Fatal("Failed to write registry addresses", "err", err)
 		}
 		lines = providedUrls
 	}
@@ -116,10 +202,14 @@ type NodeState struct {
 	mu sync.Mutex
 
 	credentials       *tls.Certificate
+	caPool            *x509.CertPool
+	caFile            string
 	resolver          *registryResolverBuilder
 	addrsFile         string
 	certFile, keyFile string
 
+	heartbeatFailures atomic.Uint64
+
 	Connection *grpc.ClientConn
 	NodeApi    services.NodeAPIClient
 }
@@ -130,42 +220,64 @@ func LoadNodeState(
 	crtFile string,
 	keyFile string,
 	providedUrls []string,
+	bootstrapToken string,
 ) *NodeState {
 	err := os.Mkdir(stateDir, 0700)
 	if err != nil && !os.IsExist(err) {
-		log.Fatalf("Failed to create state dir: %v", err)
+		logging.Fatal("Failed to create state dir", "err", err)
 	}
 
-	CAPem, err := os.ReadFile(caFile)
+	// The trust bundle received from the registry replaces the provided CA
+	stateCAFile := filepath.Join(stateDir, "ca.crt")
+	CAPem, err := os.ReadFile(stateCAFile)
+	if err != nil && os.IsNotExist(err) {
+		CAPem, err = os.ReadFile(caFile)
+	}
 	if err != nil {
-		log.Fatalf("Failed to read CA certificate: %v", err)
+		logging.Fatal("Failed to read CA certificate", "err", err)
 	}
 
-	certFile, keyFile, creds := loadNodeCrt(stateDir, crtFile, keyFile)
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(CAPem)
+
 	addrsFile, addrs := loadRegistryAddresses(stateDir, providedUrls)
+
+	if bootstrapToken != "" && !nodeCrtExists(stateDir, crtFile) {
+		bootstrapNodeCrt(stateDir, caCertPool, addrs, bootstrapToken)
+	}
+
+	certFile, keyFile, creds := loadNodeCrt(stateDir, crtFile, keyFile)
 	resolver := &registryResolverBuilder{addrs: addrs}
 
 	state := &NodeState{
 		credentials: &creds,
+		caPool:      caCertPool,
+		caFile:      stateCAFile,
 		addrsFile:   addrsFile,
 		certFile:    certFile,
 		keyFile:     keyFile,
 		resolver:    resolver,
 	}
 
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(CAPem)
-
+	// The server certificate is verified against the current trust bundle in
+	// VerifyConnection, so CA rollovers apply without reconnecting
 	transportCred := credentials.NewTLS(&tls.Config{
 		ServerName:           "registry.cluster.internal",
 		GetClientCertificate: state.clientCertificateForTLS,
-		RootCAs:              caCertPool,
+		InsecureSkipVerify:   true,
+		VerifyConnection:     state.verifyConnection,
 	})
 
 	url := fmt.Sprintf("%v:///", registryResolverScheme)
-	conn, err := grpc.NewClient(url, grpc.WithTransportCredentials(transportCred), grpc.WithResolvers(resolver))
+	conn, err := grpc.NewClient(
+		url,
+		grpc.WithTransportCredentials(transportCred),
+		grpc.WithResolvers(resolver),
+		grpc.WithUnaryInterceptor(logging.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(logging.StreamClientInterceptor),
+	)
 	if err != nil {
-		log.Fatalf("Failed to read grpc client: %v", err)
+		logging.Fatal("Failed to read grpc client", "err", err)
 	}
 	state.Connection = conn
 	state.NodeApi = services.NewNodeAPIClient(conn)
@@ -186,17 +298,101 @@ func (state *NodeState) UpdateCredentials(crtBytes []byte, keyBytes []byte) erro
 
 	err = os.WriteFile(state.certFile, crtBytes, 0600)
 	if err != nil {
-		log.Printf("Error: Failed to write agent certificate: %v", err)
+		slog.Error("Failed to write agent certificate", "err", err)
 	}
 
 	err = os.WriteFile(state.keyFile, keyBytes, 0600)
 	if err != nil {
-		log.Printf("Error: Failed to write agent key: %v", err)
+		slog.Error("Failed to write agent key", "err", err)
 	}
 
 	return nil
 }
 
+// Whether the node certificate is past half its lifetime and should be
+// renewed
+func (state *NodeState) renewalDue() bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	leaf := state.credentials.Leaf
+	lifetime := leaf.NotAfter.Sub(leaf.NotBefore)
+	return time.Until(leaf.NotAfter) < lifetime/2
+}
+
+// Expiry time of the current node certificate
+func (state *NodeState) CertificateExpiry() time.Time {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	return state.credentials.Leaf.NotAfter
+}
+
+// Number of heartbeats the registry failed to receive
+func (state *NodeState) HeartbeatFailures() uint64 {
+	return state.heartbeatFailures.Load()
+}
+
+// Datacenter and name of the node from its certificate
+func (state *NodeState) Identity() (string, string) {
+	subject := state.subject()
+
+	// The role and datacenter OUs are encoded as a set, so their order isn't
+	// preserved
+	datacenter := ""
+	for _, ou := range subject.OrganizationalUnit {
+		if !slices.Contains(nodeRoles, ou) {
+			datacenter = ou
+		}
+	}
+
+	return datacenter, subject.CommonName
+}
+
+func (state *NodeState) subject() pkix.Name {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	return state.credentials.Leaf.Subject
+}
+
+func (state *NodeState) UpdateCABundle(bundle []byte) error {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return fmt.Errorf("no certificates in CA bundle")
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.caPool = pool
+
+	err := os.WriteFile(state.caFile, bundle, 0600)
+	if err != nil {
+		slog.Error("Failed to write CA bundle", "err", err)
+	}
+
+	return nil
+}
+
+func (state *NodeState) verifyConnection(cs tls.ConnectionState) error {
+	state.mu.Lock()
+	roots := state.caPool
+	state.mu.Unlock()
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
 func (state *NodeState) clientCertificateForTLS(req *tls.CertificateRequestInfo) (*tls.Certificate, error) {
 	return state.credentials, nil
 }
@@ -206,7 +402,7 @@ func (state *NodeState) UpdateAddrs(addrs []string) {
 
 	err := writeRegistryAddresses(state.addrsFile, addrs)
 	if err != nil {
-		log.Printf("Error: Failed to write registry addresses: %v", err)
+		slog.Error("Failed to write registry addresses", "err", err)
 	}
 }
 
@@ -1,9 +1,9 @@
 package config
 
 import (
-	"log"
-
 	"github.com/caarlos0/env/v11"
+
+	"ssle/services/logging"
 )
 
 type Config struct {
@@ -12,10 +12,19 @@ type Config struct {
 	CrtFile string `env:"CERTIFICATE" envDefault:"node.crt"`
 	KeyFile string `env:"KEY" envDefault:"node.key"`
 
+	BootstrapToken string `env:"BOOTSTRAP_TOKEN"`
+
 	JoinUrl string `env:"JOIN_URL,required"`
 	CAFile  string `env:"CA_FILE" envDefault:"ca.crt"`
 
 	TargetsFile string `env:"TARGETS_FILE" envDefault:"targets.json"`
+
+	// One of debug, info, warn or error, formatted as text or json
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
+	LogFormat string `env:"LOG_FORMAT" envDefault:"text"`
+
+	// Serves /healthz and /readyz
+	HTTPAddr string `env:"HTTP_ADDR" envDefault:"127.0.0.1:9102"`
 }
 
 func LoadConfig() Config {
@@ -24,7 +33,7 @@ func LoadConfig() Config {
 		Prefix: "OBSERVER_",
 	})
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+		logging.Fatal("Error loading configuration", "err", err)
 	}
 
 	return config
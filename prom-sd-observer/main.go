@@ -2,55 +2,126 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"ssle/prom-sd-observer/config"
 	"ssle/prom-sd-observer/state"
 	"ssle/services"
+	"ssle/services/health"
+	"ssle/services/logging"
+)
+
+const (
+	watchRetryPeriod = 5 * time.Second
 )
 
+// Watch the datacenter services starting after revision, returning the last
+// revision that was fully processed. A revision may change several services,
+// so it is only complete once an event of a later revision is received and the
+// watch resumes with the events of the revision it was interrupted in, which
+// are safe to apply again. Registries that don't send revisions are watched
+// from the current one, and 0 is returned so that they are fetched again.
+func watchServices(state *state.State, revision int64) (int64, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := &services.WatchDatacenterServicesRequest{}
+	if revision != 0 {
+		startRevision := revision + 1
+		req.StartRevision = &startRevision
+	}
+	stream, err := state.ObserverClient.WatchDatacenterServices(ctx, req)
+	if err != nil {
+		return revision, err
+	}
+
+	// Revision of the events being applied
+	pending := revision
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return revision, err
+		}
+
+		if event.GetRevision() > pending {
+			revision = pending
+			pending = event.GetRevision()
+		}
+
+		switch not := event.Notification.(type) {
+		case *services.WatchDatacenterServicesResponse_Update:
+			state.UpdateService(not.Update.Service)
+		case *services.WatchDatacenterServicesResponse_Delete:
+			state.DeleteService(*not.Delete.Node, *not.Delete.ServiceName, *not.Delete.Instance)
+		}
+	}
+}
+
+func serveHealth(addr string, state *state.State) {
+	mux := http.NewServeMux()
+	health.HandleHealth(mux, []health.ReadinessCheck{
+		{Name: "registry", Check: state.CheckRegistry},
+		{Name: "certificate", Check: state.CheckCertificate},
+	})
+
+	slog.Info("Starting HTTP server", "addr", addr)
+	err := http.ListenAndServe(addr, mux)
+	if err != nil {
+		logging.Fatal("Failed to start HTTP server", "err", err)
+	}
+}
+
 func main() {
 	config := config.LoadConfig()
+	logging.Setup(config.LogLevel, config.LogFormat)
+
 	state := state.LoadState(&config)
+	datacenter, node := state.Identity()
+	slog.SetDefault(slog.With("datacenter", datacenter, "node", node))
 
 	registryConfig, err := state.GetRegistryConfig()
 	if err != nil {
-		log.Fatalf("Failed to load registry config: %v", err)
+		logging.Fatal("Failed to load registry config", "err", err)
 	}
 
 	// Start config background job
 	go state.ConfigBackgroundJob()
 	go state.HeartbeatBackgroundJob(time.Duration(*registryConfig.HeartbeatPeriod))
 
-	stream, err := state.ObserverClient.WatchDatacenterServices(context.Background(), &services.WatchDatacenterServicesRequest{})
-	if err != nil {
-		log.Fatalf("Failed to start watching datacenter services: %v", err)
+	if config.HTTPAddr != "" {
+		go serveHealth(config.HTTPAddr, state)
 	}
 
-	// Fetch all existing services
-	go func() {
-		res, err := state.ObserverClient.GetDatacenterServices(context.Background(), &services.GetDatacenterServicesRequest{})
-		if err != nil {
-			log.Fatalf("Failed to fetch datacenter services: %v", err)
-		}
-
-		state.UpdateServiceBulk(res.Services)
-	}()
-
-	log.Print("Started prometheus service discovery observer")
+	slog.Info("Started prometheus service discovery observer")
 
+	// Revision of the last known state, 0 when a snapshot is needed
+	revision := int64(0)
 	for {
-		event, err := stream.Recv()
-		if err != nil {
-			log.Fatalf("Error watching datacenter services: %v", err)
+		if revision == 0 {
+			res, err := state.ObserverClient.GetDatacenterServices(context.Background(), &services.GetDatacenterServicesRequest{})
+			if err != nil {
+				slog.Error("Failed to fetch datacenter services", "err", err)
+				time.Sleep(watchRetryPeriod)
+				continue
+			}
+
+			state.ReplaceServices(res.Services)
+			revision = res.GetRevision()
 		}
 
-		switch not := event.Notification.(type) {
-		case *services.WatchDatacenterServicesResponse_Update:
-			state.UpdateService(not.Update.Service)
-		case *services.WatchDatacenterServicesResponse_Delete:
-			state.DeleteService(*not.Delete.Node, *not.Delete.ServiceName, *not.Delete.Instance)
+		revision, err = watchServices(state, revision)
+		if status.Code(err) == codes.OutOfRange {
+			slog.Info("Watch revision was compacted, fetching all services")
+			revision = 0
+			continue
 		}
+
+		slog.Error("Error watching datacenter services", "err", err)
+		time.Sleep(watchRetryPeriod)
 	}
 }
@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/netip"
 	"os"
 	"strings"
@@ -13,6 +13,7 @@ import (
 	"ssle/node-utils"
 	"ssle/prom-sd-observer/config"
 	"ssle/services"
+	"ssle/services/logging"
 )
 
 const (
@@ -47,11 +48,12 @@ func LoadState(config *config.Config) *State {
 		config.CrtFile,
 		config.KeyFile,
 		strings.Split(config.JoinUrl, ","),
+		config.BootstrapToken,
 	)
 
 	targetsFile, err := os.Create(config.TargetsFile)
 	if err != nil {
-		log.Fatalf("Failed to open events log: %v", err)
+		logging.Fatal("Failed to open events log", "err", err)
 	}
 
 	state := &State{
@@ -86,7 +88,7 @@ func (state *State) writeServices() {
 
 	err := state.targetsFile.Truncate(0)
 	if err != nil {
-		log.Fatal(err.Error())
+		logging.Fatal("Failed to truncate targets file", "err", err)
 	}
 
 	_, err = state.targetsFile.WriteAt(buf.Bytes(), 0)
@@ -94,7 +96,7 @@ func (state *State) writeServices() {
 
 func (state *State) updateService(svc *services.ServiceSpec) {
 	if svc.MetricsPort == nil || *svc.MetricsPort == 0 {
-		log.Printf("Skipping %s/%s, no metrics port", *svc.Node, *svc.Instance)
+		slog.Debug("Skipping service without metrics port", "node", *svc.Node, "instance", *svc.Instance)
 		return
 	}
 
@@ -142,10 +144,12 @@ func (state *State) UpdateService(svc *services.ServiceSpec) {
 	state.writeServices()
 }
 
-func (state *State) UpdateServiceBulk(svcs []*services.ServiceSpec) {
+// Replace all known services with a snapshot of the datacenter services
+func (state *State) ReplaceServices(svcs []*services.ServiceSpec) {
 	state.targetsFileMU.Lock()
 	defer state.targetsFileMU.Unlock()
 
+	clear(state.targets)
 	for _, svc := range svcs {
 		state.updateService(svc)
 	}
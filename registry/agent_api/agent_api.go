@@ -2,61 +2,60 @@ package agent_api
 
 import (
 	"crypto/tls"
-	"crypto/x509"
-	"log"
+	"log/slog"
 	"net"
 
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"ssle/registry/audit"
 	"ssle/registry/config"
+	"ssle/registry/metrics"
 	"ssle/registry/state"
 	pb "ssle/services"
+	"ssle/services/logging"
 )
 
 type AgentAPIServer struct {
 	pb.UnimplementedAgentAPIServer
 	State      *state.State
 	EtcdServer *etcdserver.EtcdServer
+	Auditor    *audit.Auditor
 }
 
-func StartApiServer(config *config.Config, state *state.State, etcdServer *etcdserver.EtcdServer) {
-	nodeApiServer := NodeAPIServer{State: state, EtcdServer: etcdServer}
-	agentApiServer := AgentAPIServer{State: state, EtcdServer: etcdServer}
+func StartApiServer(config *config.Config, state *state.State, etcdServer *etcdserver.EtcdServer, auditor *audit.Auditor, healthServer grpc_health_v1.HealthServer) {
+	nodeApiServer := NodeAPIServer{State: state, EtcdServer: etcdServer, Auditor: auditor}
+	agentApiServer := AgentAPIServer{State: state, EtcdServer: etcdServer, Auditor: auditor}
 	observerApiServer := ObserverAPIServer{State: state, EtcdServer: etcdServer}
 
-	cert, err := tls.LoadX509KeyPair(state.ServerCrtFile, state.ServerKeyFile)
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	listenAddr := config.AgentAPIListenHost()
 	lis, err := net.Listen("tcp", listenAddr)
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		logging.Fatal("Failed to listen", "err", err)
 	}
 
-	caCertPool := x509.NewCertPool()
-	caCertPool.AddCert(state.AgentCA.Leaf)
-
-	transportCred := credentials.NewTLS(&tls.Config{
-		Certificates: []tls.Certificate{cert},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    caCertPool,
-	})
+	// Nodes without credentials yet connect to bootstrap them, every other
+	// call authenticates the client certificate
+	transportCred := credentials.NewTLS(state.ServerTLSConfig(tls.VerifyClientCertIfGiven, state.AgentCertPool))
 
-	grpcServer := grpc.NewServer(grpc.Creds(transportCred))
+	grpcServer := grpc.NewServer(
+		grpc.Creds(transportCred),
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor, metrics.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor, metrics.StreamServerInterceptor),
+	)
 	pb.RegisterNodeAPIServer(grpcServer, &nodeApiServer)
 	pb.RegisterAgentAPIServer(grpcServer, &agentApiServer)
 	pb.RegisterObserverAPIServer(grpcServer, &observerApiServer)
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
 	go func() {
 		err = grpcServer.Serve(lis)
 		if err != nil {
-			log.Fatalf("Failed to start agent API: %v", err)
+			logging.Fatal("Failed to start agent API", "err", err)
 		}
 	}()
 
-	log.Printf("Started agent api at https://%v", listenAddr)
+	slog.Info("Started agent api", "addr", "https://"+listenAddr)
 }
@@ -2,13 +2,10 @@ package agent_api
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
-	"go.etcd.io/etcd/server/v3/lease"
 
 	"ssle/registry/utils"
 	pb "ssle/services"
@@ -61,12 +58,12 @@ func (server *AgentAPIServer) Deregister(ctx context.Context, req *pb.Deregister
 
 	res, err := server.EtcdServer.Txn(ctx, txn)
 	if err != nil {
-		log.Print(err.Error())
+		slog.ErrorContext(ctx, "Error deleting service", "err", err)
 		return nil, utils.ServerError
 	}
 
 	if !res.Succeeded {
-		log.Printf("Error: Failed to delete service: %v", res)
+		slog.ErrorContext(ctx, "Failed to delete service", "res", res)
 		return nil, utils.ServerError
 	}
 
@@ -79,26 +76,9 @@ func (server *AgentAPIServer) Reset(ctx context.Context, req *pb.ResetRequest) (
 		return nil, err
 	}
 
-	key := fmt.Appendf(nil, "%s/%s/%s", utils.NodesLeasesNamespace, node.Datacenter, node.Name)
-
-	// Get lease number
-	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{Key: key})
+	err = utils.RevokeNodeLease(ctx, server.EtcdServer, node.Datacenter, node.Name)
 	if err != nil {
-		return nil, utils.ServerError
-	}
-
-	if len(res.Kvs) != 0 {
-		var leaseId int64
-		err := json.Unmarshal(res.Kvs[0].Value, &leaseId)
-		// If we have an error in decoding the lease id, something must have
-		// gone wrong when storing it, so ignore it, worst case the lease
-		// will expire.
-		if err == nil {
-			_, err := server.EtcdServer.LeaseRevoke(ctx, &etcdserverpb.LeaseRevokeRequest{ID: leaseId})
-			if err != nil && !errors.Is(err, lease.ErrLeaseNotFound) {
-				return nil, utils.ServerError
-			}
-		}
+		return nil, err
 	}
 
 	return &pb.ResetResponse{}, nil
@@ -4,12 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"maps"
 	"slices"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
 
+	"ssle/registry/schemas"
 	"ssle/registry/utils"
 	pb "ssle/services"
 )
@@ -18,27 +19,83 @@ const (
 	MaxGetServiceLimit = 3
 )
 
+// Criteria services must match to be discovered
+type serviceFilter struct {
+	includeUnhealthy bool
+	tags             []string
+
+	policies []schemas.PolicySchema
+	consumer *consumer
+}
+
+func filterFromRequest(req *pb.DiscoverRequest, policies []schemas.PolicySchema, consumer *consumer) serviceFilter {
+	return serviceFilter{
+		includeUnhealthy: req.GetIncludeUnhealthy(),
+		tags:             req.Tags,
+		policies:         policies,
+		consumer:         consumer,
+	}
+}
+
+// Whether every service matches the filter
+func (filter serviceFilter) isEmpty() bool {
+	return filter.includeUnhealthy && len(filter.tags) == 0 && len(filter.policies) == 0
+}
+
+func (filter serviceFilter) matches(spec *pb.ServiceSpec) bool {
+	if !filter.includeUnhealthy && spec.GetHealth() != pb.HealthStatus_HEALTHY {
+		return false
+	}
+
+	if !visible(filter.policies, spec, filter.consumer) {
+		return false
+	}
+
+	for _, tag := range filter.tags {
+		if !slices.Contains(spec.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (server *AgentAPIServer) getServiceInternal(
 	ctx context.Context,
 	prefix []byte,
 	limit int,
+	filter serviceFilter,
 ) (map[string]*pb.ServiceSpec, error) {
-	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+	rangeReq := &etcdserverpb.RangeRequest{
 		Key:      prefix,
 		RangeEnd: utils.PrefixEnd(prefix),
-		Limit:    int64(limit),
-	})
+	}
+	// Services are filtered after the fact, so unless all services match the
+	// whole range must be fetched to find enough of them
+	if filter.isEmpty() {
+		rangeReq.Limit = int64(limit)
+	}
+
+	res, err := server.EtcdServer.Range(ctx, rangeReq)
 	if err != nil {
 		return nil, err
 	}
 
-	svcs := make(map[string]*pb.ServiceSpec, len(res.Kvs))
+	svcs := make(map[string]*pb.ServiceSpec, min(len(res.Kvs), limit))
 	for _, kv := range res.Kvs {
+		if len(svcs) >= limit {
+			break
+		}
+
 		var tmp pb.ServiceSpec
 		err = json.Unmarshal(kv.Value, &tmp)
 		if err != nil {
 			return nil, err
 		}
+
+		if !filter.matches(&tmp) {
+			continue
+		}
 		svcs[string(kv.Key)] = &tmp
 	}
 
@@ -49,8 +106,9 @@ func (server *AgentAPIServer) fillServices(
 	ctx context.Context,
 	prefix []byte,
 	svcs map[string]*pb.ServiceSpec,
+	filter serviceFilter,
 ) (map[string]*pb.ServiceSpec, error) {
-	extra, err := server.getServiceInternal(ctx, prefix, MaxGetServiceLimit)
+	extra, err := server.getServiceInternal(ctx, prefix, MaxGetServiceLimit, filter)
 	if err != nil {
 		return svcs, nil
 	}
@@ -71,7 +129,27 @@ func (server *AgentAPIServer) Discover(ctx context.Context, req *pb.DiscoverRequ
 		return nil, err
 	}
 
+	policies, err := utils.GetPolicies(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := nodeConsumer(node)
+	if req.SourceService != nil {
+		found, err := server.nodeHasService(ctx, node, *req.SourceService)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			consumer.services = []string{*req.SourceService}
+		} else {
+			slog.WarnContext(ctx, "Ignoring source service not registered by the node", "service", *req.SourceService)
+		}
+	}
+
 	svc := *req.Service
+	filter := filterFromRequest(req, policies, consumer)
 	name := node.Name
 	dc := node.Datacenter
 	location := node.Location
@@ -94,25 +172,25 @@ func (server *AgentAPIServer) Discover(ctx context.Context, req *pb.DiscoverRequ
 	var svcs map[string]*pb.ServiceSpec
 
 	if req.Instance != nil {
-		key := fmt.Appendf(namePrefix, "%v", req.Instance)
-		svcs, err = server.getServiceInternal(ctx, key, 1)
+		key := fmt.Appendf(namePrefix, "%v", *req.Instance)
+		svcs, err = server.getServiceInternal(ctx, key, 1, filter)
 	} else {
-		svcs, err = server.getServiceInternal(ctx, namePrefix, MaxGetServiceLimit)
+		svcs, err = server.getServiceInternal(ctx, namePrefix, MaxGetServiceLimit, filter)
 	}
 
 	if err == nil && len(svcs) < MaxGetServiceLimit && req.Node == nil {
-		log.Print("Querying datacenter services")
-		svcs, err = server.fillServices(ctx, dcPrefix, svcs)
+		slog.DebugContext(ctx, "Querying datacenter services")
+		svcs, err = server.fillServices(ctx, dcPrefix, svcs, filter)
 	}
 
 	if err == nil && len(svcs) < MaxGetServiceLimit && req.Datacenter == nil {
-		log.Print("Querying location services")
-		svcs, err = server.fillServices(ctx, locPrefix, svcs)
+		slog.DebugContext(ctx, "Querying location services")
+		svcs, err = server.fillServices(ctx, locPrefix, svcs, filter)
 	}
 
 	if err == nil && len(svcs) < MaxGetServiceLimit && req.Location == nil {
-		log.Print("Querying global services")
-		svcs, err = server.fillServices(ctx, svcPrefix, svcs)
+		slog.DebugContext(ctx, "Querying global services")
+		svcs, err = server.fillServices(ctx, svcPrefix, svcs, filter)
 	}
 
 	if err != nil {
@@ -0,0 +1,117 @@
+package agent_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+var (
+	ServiceNotFoundError = status.Errorf(codes.NotFound, "Service not found")
+)
+
+func (server *AgentAPIServer) UpdateHealth(ctx context.Context, req *pb.UpdateHealthRequest) (*pb.UpdateHealthResponse, error) {
+	node, err := utils.AuthenticateAgent(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	svcKey := fmt.Appendf(
+		nil,
+		"%s/%s/%s/%s/%s/%s",
+		utils.ServiceNamespace,
+		*req.Service,
+		node.Location,
+		node.Datacenter,
+		node.Name,
+		*req.Instance,
+	)
+	dsSvcKey := fmt.Appendf(
+		nil,
+		"%s/%s/%s/%s/%s",
+		utils.DCServicesNamespace,
+		node.Datacenter,
+		node.Name,
+		*req.Service,
+		*req.Instance,
+	)
+
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{Key: svcKey})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching service", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if len(res.Kvs) < 1 {
+		return nil, ServiceNotFoundError
+	}
+
+	var spec pb.ServiceSpec
+	err = json.Unmarshal(res.Kvs[0].Value, &spec)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error decoding service", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if spec.GetHealth() == req.GetHealth() && spec.Health != nil {
+		return &pb.UpdateHealthResponse{}, nil
+	}
+
+	spec.Health = req.Health
+
+	serializedSpec, err := json.Marshal(&spec)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding service", "err", err)
+		return nil, utils.ServerError
+	}
+
+	txnRes, err := server.EtcdServer.Txn(ctx, &etcdserverpb.TxnRequest{
+		// Ensure the service wasn't re-registered or removed in the meantime
+		Compare: []*etcdserverpb.Compare{{
+			Result: etcdserverpb.Compare_EQUAL,
+			Target: etcdserverpb.Compare_MOD,
+			Key:    svcKey,
+			TargetUnion: &etcdserverpb.Compare_ModRevision{
+				ModRevision: res.Kvs[0].ModRevision,
+			},
+		}},
+		Success: []*etcdserverpb.RequestOp{
+			{
+				Request: &etcdserverpb.RequestOp_RequestPut{
+					RequestPut: &etcdserverpb.PutRequest{
+						Key:   svcKey,
+						Value: serializedSpec,
+						Lease: res.Kvs[0].Lease,
+					},
+				},
+			},
+			{
+				Request: &etcdserverpb.RequestOp_RequestPut{
+					RequestPut: &etcdserverpb.PutRequest{
+						Key:   dsSvcKey,
+						Value: serializedSpec,
+						Lease: res.Kvs[0].Lease,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing service health", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if !txnRes.Succeeded {
+		return nil, status.Errorf(codes.Aborted, "Service changed while updating health")
+	}
+
+	return &pb.UpdateHealthResponse{}, nil
+}
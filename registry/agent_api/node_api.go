@@ -2,21 +2,31 @@ package agent_api
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"ssle/registry/audit"
+	"ssle/registry/schemas"
 	"ssle/registry/state"
 	"ssle/registry/utils"
 	"ssle/services"
 )
 
+var (
+	InvalidBootstrapTokenError = status.Errorf(codes.Unauthenticated, "Invalid or expired bootstrap token")
+	InvalidCSRError            = status.Errorf(codes.InvalidArgument, "Invalid certificate signing request")
+)
+
 type NodeAPIServer struct {
 	services.UnimplementedNodeAPIServer
 	State      *state.State
 	EtcdServer *etcdserver.EtcdServer
+	Auditor    *audit.Auditor
 }
 
 func (server *NodeAPIServer) Config(ctx context.Context, req *services.ConfigRequest) (*services.ConfigResponse, error) {
@@ -41,20 +51,29 @@ func (server *NodeAPIServer) Config(ctx context.Context, req *services.ConfigReq
 
 	res := &services.ConfigResponse{
 		HeartbeatPeriod: &heartbeatPeriod,
+		CaBundle:        server.State.CABundle(),
 	}
 
-	// If the certificate should already be renewed, do it
-	if renewAt < 0 {
-		log.Printf("Renewing certificate for %v/%v", node.Datacenter, node.Name)
+	// Certificates from a CA generation that is being rolled over are renewed
+	// right away
+	if !server.State.IssuedByActiveAgentCA(cert) {
+		renewAt = -1
+	}
 
-		cert, key := utils.CreateNodeCrt(server.State, node.Datacenter, node.Name, role)
+	// If the certificate should already be renewed, sign the key the node
+	// generated for it
+	if renewAt < 0 && req.Csr != nil {
+		cert, err := server.renewCertificate(ctx, role, node, req.Csr)
+		server.Auditor.Record(ctx, "RenewCertificate", node.Datacenter, node.Name, err)
+		if err != nil {
+			return nil, err
+		}
 
 		res.Certificate = cert
-		res.Key = key
 		renewPeriod := uint64(renewPeriod.Seconds())
 		res.RenewPeriod = &renewPeriod
 	} else {
-		renewAt := uint64(renewAt.Seconds())
+		renewAt := uint64(max(renewAt, 0).Seconds())
 		res.RenewPeriod = &renewAt
 	}
 
@@ -75,6 +94,30 @@ func (server *NodeAPIServer) Config(ctx context.Context, req *services.ConfigReq
 	return res, nil
 }
 
+func (server *NodeAPIServer) renewCertificate(ctx context.Context, role string, node *schemas.NodeSchema, csrPem []byte) ([]byte, error) {
+	csr, err := utils.ParseCSR(csrPem)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error parsing renewal CSR", "err", err)
+		return nil, InvalidCSRError
+	}
+
+	csrRole, csrDatacenter, err := utils.NodeRoleDatacenter(csr.Subject)
+	if err != nil || csr.Subject.CommonName != node.Name || csrRole != role || csrDatacenter != node.Datacenter {
+		slog.ErrorContext(ctx, "Error renewing certificate: CSR subject does not match", "datacenter", node.Datacenter, "node", node.Name, "subject", csr.Subject.String())
+		return nil, InvalidCSRError
+	}
+
+	slog.InfoContext(ctx, "Renewing certificate", "datacenter", node.Datacenter, "node", node.Name)
+
+	cert, err := utils.SignNodeCrt(ctx, server.State, server.EtcdServer, node.Datacenter, node.Name, role, csr.PublicKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error signing node certificate", "err", err)
+		return nil, utils.ServerError
+	}
+
+	return cert, nil
+}
+
 func (server *NodeAPIServer) Heartbeat(ctx context.Context, req *services.HeartbeatRequest) (*services.HeartbeatResponse, error) {
 	cert, err := utils.ExtractPeerCertificate(ctx)
 	if err != nil {
@@ -92,3 +135,63 @@ func (server *NodeAPIServer) Heartbeat(ctx context.Context, req *services.Heartb
 
 	return &services.HeartbeatResponse{}, nil
 }
+
+// Exchange a bootstrap token and a CSR for the node certificate, the node
+// private key is generated locally and never leaves it.
+func (server *NodeAPIServer) Bootstrap(ctx context.Context, req *services.BootstrapRequest) (*services.BootstrapResponse, error) {
+	res, token, err := server.bootstrap(ctx, req)
+
+	datacenter, name := "", ""
+	if token != nil {
+		datacenter, name = token.Datacenter, token.Name
+	}
+	server.Auditor.Record(ctx, "Bootstrap", datacenter, name, err)
+
+	return res, err
+}
+
+// Also returns the consumed token, if any, to audit which node it was for
+func (server *NodeAPIServer) bootstrap(ctx context.Context, req *services.BootstrapRequest) (*services.BootstrapResponse, *schemas.BootstrapTokenSchema, error) {
+	csr, err := utils.ParseCSR(req.Csr)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error parsing bootstrap CSR", "err", err)
+		return nil, nil, InvalidCSRError
+	}
+
+	bootstrap, err := utils.ConsumeBootstrapToken(ctx, server.EtcdServer, *req.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bootstrap == nil {
+		return nil, nil, InvalidBootstrapTokenError
+	}
+
+	node, err := utils.GetNodeSchema(ctx, server.EtcdServer, bootstrap.Datacenter, bootstrap.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error getting node schema", "err", err)
+		return nil, bootstrap, utils.ServerError
+	}
+
+	if node == nil || node.Disabled {
+		return nil, bootstrap, InvalidBootstrapTokenError
+	}
+
+	cert, err := utils.SignNodeCrt(
+		ctx,
+		server.State,
+		server.EtcdServer,
+		node.Datacenter,
+		node.Name,
+		node.Type,
+		csr.PublicKey,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error signing node certificate", "err", err)
+		return nil, bootstrap, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Bootstrapped node", "datacenter", node.Datacenter, "node", node.Name)
+
+	return &services.BootstrapResponse{Certificate: cert}, bootstrap, nil
+}
@@ -5,13 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"google.golang.org/grpc"
 
+	"ssle/registry/metrics"
 	"ssle/registry/state"
 	"ssle/registry/utils"
 	pb "ssle/services"
@@ -36,7 +37,7 @@ func (server *ObserverAPIServer) GetDatacenterServices(ctx context.Context, req
 		RangeEnd: utils.PrefixEnd(prefix),
 	})
 	if err != nil {
-		log.Printf("Error fetching datacenter services: %v", err)
+		slog.ErrorContext(ctx, "Error fetching datacenter services", "err", err)
 		return nil, utils.ServerError
 	}
 
@@ -44,12 +45,12 @@ func (server *ObserverAPIServer) GetDatacenterServices(ctx context.Context, req
 	for i, kv := range res.Kvs {
 		err = json.Unmarshal(kv.Value, &svcs[i])
 		if err != nil {
-			log.Printf("Error decoding datacenter service: %v", err)
+			slog.ErrorContext(ctx, "Error decoding datacenter service", "err", err)
 			return nil, utils.ServerError
 		}
 	}
 
-	return &pb.GetDatacenterServicesResponse{Services: svcs}, nil
+	return &pb.GetDatacenterServicesResponse{Services: svcs, Revision: &res.Header.Revision}, nil
 }
 
 func (server *ObserverAPIServer) WatchDatacenterServices(req *pb.WatchDatacenterServicesRequest, stream grpc.ServerStreamingServer[pb.WatchDatacenterServicesResponse]) error {
@@ -58,65 +59,57 @@ func (server *ObserverAPIServer) WatchDatacenterServices(req *pb.WatchDatacenter
 		return err
 	}
 
+	metrics.ObserverWatchStreams.Inc()
+	defer metrics.ObserverWatchStreams.Dec()
+
 	prefix := fmt.Appendf(nil, "%s/%s/", utils.DCServicesNamespace, node.Datacenter)
 
-	kv := server.EtcdServer.Watchable()
-	watchStream := kv.NewWatchStream()
-	defer watchStream.Close()
-
-	watchStream.Watch(0, prefix, utils.PrefixEnd(prefix), 0)
-
-	for {
-		select {
-		case msg := <-watchStream.Chan():
-			for _, event := range msg.Events {
-				var msg pb.WatchDatacenterServicesResponse
-				switch event.Type {
-				case mvccpb.PUT:
-					var svc pb.ServiceSpec
-					err = json.Unmarshal(event.Kv.Value, &svc)
-					if err != nil {
-						log.Printf("Error decoding datacenter service: %v", err)
-						return utils.ServerError
-					}
-
-					msg = pb.WatchDatacenterServicesResponse{
-						Notification: &pb.WatchDatacenterServicesResponse_Update{
-							Update: &pb.WatchServiceUpdate{
-								Service: &svc,
-							},
-						},
-					}
-				case mvccpb.DELETE:
-					parts := bytes.Split(event.Kv.Key, []byte("/"))
-
-					if len(parts) < 3 {
-						log.Printf("Malformed datacenter service key: %s", event.Kv.Key)
-						return utils.ServerError
-					}
-
-					node := string(parts[len(parts)-3])
-					service_name := string(parts[len(parts)-2])
-					instance := string(parts[len(parts)-1])
-
-					msg = pb.WatchDatacenterServicesResponse{
-						Notification: &pb.WatchDatacenterServicesResponse_Delete{
-							Delete: &pb.WatchServiceDelete{
-								Node:        &node,
-								ServiceName: &service_name,
-								Instance:    &instance,
-							},
-						},
-					}
-				}
-
-				if err := stream.Send(&msg); err != nil {
-					log.Printf("Error streaming service changes: %v", err)
-					return utils.ServerError
-				}
+	// The observer already knows the revision from the services snapshot
+	created := func(rev int64) error { return nil }
+
+	handle := func(event *mvccpb.Event) error {
+		msg := pb.WatchDatacenterServicesResponse{Revision: &event.Kv.ModRevision}
+		switch event.Type {
+		case mvccpb.PUT:
+			var svc pb.ServiceSpec
+			err = json.Unmarshal(event.Kv.Value, &svc)
+			if err != nil {
+				slog.ErrorContext(stream.Context(), "Error decoding datacenter service", "err", err)
+				return utils.ServerError
+			}
+
+			msg.Notification = &pb.WatchDatacenterServicesResponse_Update{
+				Update: &pb.WatchServiceUpdate{
+					Service: &svc,
+				},
+			}
+		case mvccpb.DELETE:
+			parts := bytes.Split(event.Kv.Key, []byte("/"))
+
+			if len(parts) < 3 {
+				slog.ErrorContext(stream.Context(), "Malformed datacenter service key", "key", string(event.Kv.Key))
+				return utils.ServerError
+			}
+
+			node := string(parts[len(parts)-3])
+			service_name := string(parts[len(parts)-2])
+			instance := string(parts[len(parts)-1])
+
+			msg.Notification = &pb.WatchDatacenterServicesResponse_Delete{
+				Delete: &pb.WatchServiceDelete{
+					Node:        &node,
+					ServiceName: &service_name,
+					Instance:    &instance,
+				},
 			}
-		case <-stream.Context().Done():
-			return nil
 		}
+
+		if err := stream.Send(&msg); err != nil {
+			slog.ErrorContext(stream.Context(), "Error streaming service changes", "err", err)
+			return utils.ServerError
+		}
+		return nil
 	}
+
+	return watchPrefix(stream.Context(), server.EtcdServer, prefix, req.GetStartRevision(), created, handle)
 }
@@ -0,0 +1,189 @@
+package agent_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+// Agent discovering services, and the services it discovers for
+type consumer struct {
+	location   string
+	datacenter string
+	services   []string
+}
+
+func policySelects(policy *schemas.PolicySchema, spec *pb.ServiceSpec) bool {
+	if policy.Service != "" && policy.Service != spec.GetServiceName() ||
+		policy.Location != "" && policy.Location != spec.GetLocation() ||
+		policy.Datacenter != "" && policy.Datacenter != spec.GetDatacenter() {
+		return false
+	}
+
+	for _, tag := range policy.Tags {
+		if !slices.Contains(spec.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func policyAllows(policy *schemas.PolicySchema, consumer *consumer) bool {
+	if slices.Contains(policy.AllowLocations, consumer.location) ||
+		slices.Contains(policy.AllowDatacenters, consumer.datacenter) {
+		return true
+	}
+
+	for _, svc := range consumer.services {
+		if slices.Contains(policy.AllowServices, svc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func visible(policies []schemas.PolicySchema, spec *pb.ServiceSpec, consumer *consumer) bool {
+	selected := false
+	for i := range policies {
+		if !policySelects(&policies[i], spec) {
+			continue
+		}
+		if policyAllows(&policies[i], consumer) {
+			return true
+		}
+		selected = true
+	}
+
+	return !selected
+}
+
+func nodeConsumer(node *schemas.NodeSchema) *consumer {
+	return &consumer{location: node.Location, datacenter: node.Datacenter}
+}
+
+// Whether an instance of the service is registered by the node, agents can
+// only discover on behalf of their own services
+func (server *AgentAPIServer) nodeHasService(ctx context.Context, node *schemas.NodeSchema, service string) (bool, error) {
+	prefix := fmt.Appendf(nil, "%s/%s/%s/%s/", utils.DCServicesNamespace, node.Datacenter, node.Name, service)
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:       prefix,
+		RangeEnd:  utils.PrefixEnd(prefix),
+		CountOnly: true,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching node services", "err", err)
+		return false, utils.ServerError
+	}
+
+	return res.Count > 0, nil
+}
+
+// dcsvc/<datacenter>/<node>/
+func nodeServicesPrefix(node *schemas.NodeSchema) []byte {
+	return fmt.Appendf(nil, "%s/%s/%s/", utils.DCServicesNamespace, node.Datacenter, node.Name)
+}
+
+// Policies and consumer of a watching node. Watches only notify agents that
+// their cached results changed, so the events visible to any of the node
+// services are sent.
+type watchFilter struct {
+	node     *schemas.NodeSchema
+	policies []schemas.PolicySchema
+	consumer *consumer
+}
+
+func (filter *watchFilter) load(ctx context.Context, etcd *etcdserver.EtcdServer, rev int64) error {
+	err := filter.updatePolicies(ctx, etcd, rev)
+	if err != nil {
+		return err
+	}
+
+	_, err = filter.updateServices(ctx, etcd, rev)
+	return err
+}
+
+func (filter *watchFilter) updatePolicies(ctx context.Context, etcd *etcdserver.EtcdServer, rev int64) error {
+	prefix := fmt.Appendf(nil, "%s/", utils.PoliciesNamespace)
+	kvs, err := rangeAt(ctx, etcd, prefix, utils.PrefixEnd(prefix), rev)
+	if err != nil {
+		return err
+	}
+
+	policies := make([]schemas.PolicySchema, len(kvs))
+	for i, kv := range kvs {
+		err = json.Unmarshal(kv.Value, &policies[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding policy", "err", err)
+			return utils.ServerError
+		}
+	}
+
+	filter.policies = policies
+	return nil
+}
+
+// Reload the names of the services registered by the node, returns whether
+// they changed
+func (filter *watchFilter) updateServices(ctx context.Context, etcd *etcdserver.EtcdServer, rev int64) (bool, error) {
+	// dcsvc/<datacenter>/<node>/<service>/<instance>
+	prefix := nodeServicesPrefix(filter.node)
+	kvs, err := rangeAt(ctx, etcd, prefix, utils.PrefixEnd(prefix), rev)
+	if err != nil {
+		return false, err
+	}
+
+	services := []string{}
+	for _, kv := range kvs {
+		svc, _, _ := bytes.Cut(bytes.TrimPrefix(kv.Key, prefix), []byte("/"))
+		if !slices.Contains(services, string(svc)) {
+			services = append(services, string(svc))
+		}
+	}
+
+	if filter.consumer != nil && slices.Equal(filter.consumer.services, services) {
+		return false, nil
+	}
+
+	filter.consumer = nodeConsumer(filter.node)
+	filter.consumer.services = services
+	return true, nil
+}
+
+// Whether the service changed by the event was visible to the consumer
+// before it. Values that were compacted count as visible, so that agents are
+// not left with a stale entry.
+func (filter *watchFilter) previouslyVisible(ctx context.Context, etcd *etcdserver.EtcdServer, event *mvccpb.Event) (bool, error) {
+	kvs, err := rangeAt(ctx, etcd, event.Kv.Key, nil, event.Kv.ModRevision-1)
+	if err == RevisionCompactedError {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(kvs) < 1 {
+		return false, nil
+	}
+
+	var spec pb.ServiceSpec
+	err = json.Unmarshal(kvs[0].Value, &spec)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error decoding service", "err", err)
+		return false, utils.ServerError
+	}
+
+	return visible(filter.policies, &spec, filter.consumer), nil
+}
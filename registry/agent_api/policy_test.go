@@ -0,0 +1,112 @@
+package agent_api
+
+import (
+	"testing"
+
+	"ssle/registry/schemas"
+	pb "ssle/services"
+)
+
+func testSpec(service string, location string, datacenter string, tags ...string) *pb.ServiceSpec {
+	return &pb.ServiceSpec{
+		ServiceName: &service,
+		Location:    &location,
+		Datacenter:  &datacenter,
+		Tags:        tags,
+	}
+}
+
+func TestPolicySelects(t *testing.T) {
+	spec := testSpec("web", "l1", "dc1", "http", "public")
+
+	tests := []struct {
+		name   string
+		policy schemas.PolicySchema
+		want   bool
+	}{
+		{name: "empty", policy: schemas.PolicySchema{}, want: true},
+		{name: "service", policy: schemas.PolicySchema{Service: "web"}, want: true},
+		{name: "other service", policy: schemas.PolicySchema{Service: "db"}, want: false},
+		{name: "location", policy: schemas.PolicySchema{Location: "l1"}, want: true},
+		{name: "other location", policy: schemas.PolicySchema{Location: "l2"}, want: false},
+		{name: "datacenter", policy: schemas.PolicySchema{Datacenter: "dc1"}, want: true},
+		{name: "other datacenter", policy: schemas.PolicySchema{Datacenter: "dc2"}, want: false},
+		{name: "tags", policy: schemas.PolicySchema{Tags: []string{"http", "public"}}, want: true},
+		{name: "missing tag", policy: schemas.PolicySchema{Tags: []string{"http", "internal"}}, want: false},
+		{name: "all fields", policy: schemas.PolicySchema{Service: "web", Location: "l1", Datacenter: "dc1", Tags: []string{"http"}}, want: true},
+		{name: "one field differs", policy: schemas.PolicySchema{Service: "web", Location: "l1", Datacenter: "dc2"}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := policySelects(&test.policy, spec); got != test.want {
+				t.Fatalf("expected %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPolicyAllows(t *testing.T) {
+	consumer := &consumer{location: "l1", datacenter: "dc1", services: []string{"api", "worker"}}
+
+	tests := []struct {
+		name   string
+		policy schemas.PolicySchema
+		want   bool
+	}{
+		{name: "nothing allowed", policy: schemas.PolicySchema{}, want: false},
+		{name: "location", policy: schemas.PolicySchema{AllowLocations: []string{"l2", "l1"}}, want: true},
+		{name: "other location", policy: schemas.PolicySchema{AllowLocations: []string{"l2"}}, want: false},
+		{name: "datacenter", policy: schemas.PolicySchema{AllowDatacenters: []string{"dc1"}}, want: true},
+		{name: "other datacenter", policy: schemas.PolicySchema{AllowDatacenters: []string{"dc2"}}, want: false},
+		{name: "service", policy: schemas.PolicySchema{AllowServices: []string{"worker"}}, want: true},
+		{name: "other service", policy: schemas.PolicySchema{AllowServices: []string{"db"}}, want: false},
+		{name: "any of the fields", policy: schemas.PolicySchema{AllowLocations: []string{"l2"}, AllowServices: []string{"api"}}, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := policyAllows(&test.policy, consumer); got != test.want {
+				t.Fatalf("expected %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestVisible(t *testing.T) {
+	spec := testSpec("web", "l1", "dc1", "http")
+	consumer := &consumer{location: "l2", datacenter: "dc1", services: []string{"api"}}
+
+	tests := []struct {
+		name     string
+		policies []schemas.PolicySchema
+		want     bool
+	}{
+		{name: "no policies", policies: nil, want: true},
+		{name: "no policy selects", policies: []schemas.PolicySchema{
+			{Service: "db"},
+		}, want: true},
+		{name: "selected and denied", policies: []schemas.PolicySchema{
+			{Service: "web", AllowLocations: []string{"l1"}},
+		}, want: false},
+		{name: "selected and allowed", policies: []schemas.PolicySchema{
+			{Service: "web", AllowServices: []string{"api"}},
+		}, want: true},
+		{name: "one of the selecting policies allows", policies: []schemas.PolicySchema{
+			{Service: "web", AllowLocations: []string{"l1"}},
+			{Tags: []string{"http"}, AllowDatacenters: []string{"dc1"}},
+		}, want: true},
+		{name: "allowing policy doesn't select", policies: []schemas.PolicySchema{
+			{Service: "web", AllowLocations: []string{"l1"}},
+			{Service: "db", AllowDatacenters: []string{"dc1"}},
+		}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := visible(test.policies, spec, consumer); got != test.want {
+				t.Fatalf("expected %t, got %t", test.want, got)
+			}
+		})
+	}
+}
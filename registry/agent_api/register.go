@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -36,12 +36,15 @@ func (server *AgentAPIServer) Register(ctx context.Context, req *pb.RegisterServ
 		Addresses:   req.Addresses,
 		Ports:       req.Ports,
 		MetricsPort: req.MetricsPort,
+		Health:      req.Health,
+		Tags:        req.Tags,
+		Metadata:    req.Metadata,
 	}
 
 	if len(spec.Addresses) == 0 {
 		ip, _, err := net.SplitHostPort(p.Addr.String())
 		if err != nil {
-			log.Print(err.Error())
+			slog.ErrorContext(ctx, "Error parsing peer address", "err", err)
 			return nil, utils.ServerError
 		}
 
@@ -70,7 +73,7 @@ func (server *AgentAPIServer) Register(ctx context.Context, req *pb.RegisterServ
 
 	serializedSpec, err := json.Marshal(&spec)
 	if err != nil {
-		log.Print(err.Error())
+		slog.ErrorContext(ctx, "Error encoding service", "err", err)
 		return nil, utils.ServerError
 	}
 
@@ -102,12 +105,12 @@ func (server *AgentAPIServer) Register(ctx context.Context, req *pb.RegisterServ
 		},
 	})
 	if err != nil {
-		log.Print(err.Error())
+		slog.ErrorContext(ctx, "Error storing service", "err", err)
 		return nil, utils.ServerError
 	}
 
 	if !res.Succeeded {
-		log.Printf("Error: Failed to register service: %v", res)
+		slog.ErrorContext(ctx, "Failed to register service", "res", res)
 		return nil, utils.ServerError
 	}
 
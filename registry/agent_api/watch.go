@@ -0,0 +1,264 @@
+package agent_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+var (
+	RevisionCompactedError = status.Errorf(codes.OutOfRange, "Requested revision has been compacted")
+)
+
+// Watch of the keys under a prefix, handle is called for each of their events
+type prefixWatch struct {
+	prefix []byte
+	handle func(event *mvccpb.Event) error
+}
+
+// Watch all changes to the keys under each prefix starting at startRev, or the
+// current revision if it is 0. created is called with the revision the watches
+// start from, before any event is handled.
+func watchPrefixes(
+	ctx context.Context,
+	etcd *etcdserver.EtcdServer,
+	startRev int64,
+	created func(rev int64) error,
+	watches ...prefixWatch,
+) error {
+	kv := etcd.Watchable()
+	watchStream := kv.NewWatchStream()
+	defer watchStream.Close()
+
+	if startRev <= 0 {
+		startRev = kv.Rev() + 1
+	}
+
+	handlers := map[mvcc.WatchID]func(event *mvccpb.Event) error{}
+	for _, watch := range watches {
+		id, err := watchStream.Watch(0, watch.prefix, utils.PrefixEnd(watch.prefix), startRev)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error creating watch", "err", err)
+			return utils.ServerError
+		}
+		handlers[id] = watch.handle
+	}
+
+	if err := created(startRev - 1); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case msg := <-watchStream.Chan():
+			if msg.CompactRevision != 0 {
+				return RevisionCompactedError
+			}
+
+			for _, event := range msg.Events {
+				if err := handlers[msg.WatchID](&event); err != nil {
+					return err
+				}
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Watch all changes to the keys under prefix, see watchPrefixes
+func watchPrefix(
+	ctx context.Context,
+	etcd *etcdserver.EtcdServer,
+	prefix []byte,
+	startRev int64,
+	created func(rev int64) error,
+	handle func(event *mvccpb.Event) error,
+) error {
+	return watchPrefixes(ctx, etcd, startRev, created, prefixWatch{prefix: prefix, handle: handle})
+}
+
+// Read the keys from key to end at rev from the local store, like etcd does
+// for the previous values of watch events. Watches are already behind the
+// cluster, they don't need linearizable reads.
+func rangeAt(ctx context.Context, etcd *etcdserver.EtcdServer, key []byte, end []byte, rev int64) ([]mvccpb.KeyValue, error) {
+	kv := etcd.Watchable()
+	// Watches may start at a future revision
+	if rev > kv.Rev() {
+		rev = 0
+	}
+
+	res, err := kv.Range(ctx, key, end, mvcc.RangeOptions{Rev: rev})
+	if errors.Is(err, mvcc.ErrCompacted) {
+		return nil, RevisionCompactedError
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading watched keys", "err", err)
+		return nil, utils.ServerError
+	}
+
+	return res.KVs, nil
+}
+
+func (server *AgentAPIServer) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.WatchResponse]) error {
+	// Watches are filtered by the policies of agents, observers have their
+	// own API
+	watcher, err := utils.AuthenticateAgent(stream.Context(), server.EtcdServer)
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Appendf(nil, "%s/%s/", utils.ServiceNamespace, *req.Service)
+
+	// Resuming from a compacted revision starts over from the current one
+	startRev := req.GetStartRevision()
+	if startRev > 0 {
+		_, err := rangeAt(stream.Context(), server.EtcdServer, prefix, nil, startRev-1)
+		if err == RevisionCompactedError {
+			startRev = 0
+		} else if err != nil {
+			return err
+		}
+	}
+
+	send := func(msg *pb.WatchResponse) error {
+		if err := stream.Send(msg); err != nil {
+			slog.ErrorContext(stream.Context(), "Error streaming service changes", "err", err)
+			return utils.ServerError
+		}
+		return nil
+	}
+
+	// Policies and services of the node are loaded once and kept up to date
+	// by the watch, agents are told to drop their cached results whenever
+	// they change and when the watch doesn't resume from a revision
+	filter := &watchFilter{node: watcher}
+	lastRev := int64(0)
+
+	reset := func() error {
+		return send(&pb.WatchResponse{Revision: &lastRev})
+	}
+
+	created := func(rev int64) error {
+		err := filter.load(stream.Context(), server.EtcdServer, rev)
+		if err != nil {
+			return err
+		}
+
+		lastRev = rev
+		if startRev > 0 {
+			return nil
+		}
+		return reset()
+	}
+
+	handlePolicy := func(event *mvccpb.Event) error {
+		err := filter.updatePolicies(stream.Context(), server.EtcdServer, event.Kv.ModRevision)
+		if err != nil {
+			return err
+		}
+
+		return reset()
+	}
+
+	handleNodeService := func(event *mvccpb.Event) error {
+		// Only adding or removing instances may change the node services
+		if event.Type == mvccpb.PUT && event.Kv.Version != 1 {
+			return nil
+		}
+
+		changed, err := filter.updateServices(stream.Context(), server.EtcdServer, event.Kv.ModRevision)
+		if err != nil || !changed {
+			return err
+		}
+
+		return reset()
+	}
+
+	handle := func(event *mvccpb.Event) error {
+		// svc/<service>/<location>/<datacenter>/<node>/<instance>
+		parts := bytes.Split(event.Kv.Key, []byte("/"))
+		if len(parts) != 6 {
+			slog.ErrorContext(stream.Context(), "Malformed service key", "key", string(event.Kv.Key))
+			return utils.ServerError
+		}
+
+		location := string(parts[2])
+		datacenter := string(parts[3])
+		node := string(parts[4])
+		instance := string(parts[5])
+
+		if req.Location != nil && *req.Location != location ||
+			req.Datacenter != nil && *req.Datacenter != datacenter ||
+			req.Node != nil && *req.Node != node {
+			return nil
+		}
+
+		lastRev = event.Kv.ModRevision
+		msg := &pb.WatchResponse{Revision: &event.Kv.ModRevision}
+		if event.Type == mvccpb.PUT {
+			var svc pb.ServiceSpec
+			err := json.Unmarshal(event.Kv.Value, &svc)
+			if err != nil {
+				slog.ErrorContext(stream.Context(), "Error decoding service", "err", err)
+				return utils.ServerError
+			}
+
+			if visible(filter.policies, &svc, filter.consumer) {
+				msg.Notification = &pb.WatchResponse_Update{
+					Update: &pb.WatchServiceUpdate{
+						Service: &svc,
+					},
+				}
+				return send(msg)
+			}
+		}
+
+		// Services hidden from the node are only sent as deleted when they
+		// were visible before the event
+		if len(filter.policies) > 0 {
+			wasVisible, err := filter.previouslyVisible(stream.Context(), server.EtcdServer, event)
+			if err != nil {
+				return err
+			}
+			if !wasVisible {
+				return nil
+			}
+		}
+
+		msg.Notification = &pb.WatchResponse_Delete{
+			Delete: &pb.WatchServiceDelete{
+				ServiceName: req.Service,
+				Location:    &location,
+				Datacenter:  &datacenter,
+				Node:        &node,
+				Instance:    &instance,
+			},
+		}
+
+		return send(msg)
+	}
+
+	return watchPrefixes(
+		stream.Context(),
+		server.EtcdServer,
+		startRev,
+		created,
+		prefixWatch{prefix: prefix, handle: handle},
+		prefixWatch{prefix: fmt.Appendf(nil, "%s/", utils.PoliciesNamespace), handle: handlePolicy},
+		prefixWatch{prefix: nodeServicesPrefix(watcher), handle: handleNodeService},
+	)
+}
@@ -0,0 +1,105 @@
+package agent_api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+	"ssle/services/spiffe"
+)
+
+var InstanceNotFoundError = status.Errorf(codes.NotFound, "Service instance not registered by this node")
+
+// Issue a certificate to an instance registered by the agent, its workload id
+// is derived from the service and the datacenter of the node
+func (server *AgentAPIServer) SignWorkloadCertificate(ctx context.Context, req *pb.SignWorkloadCertificateRequest) (*pb.SignWorkloadCertificateResponse, error) {
+	node, err := utils.AuthenticateAgent(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := server.signWorkloadCertificate(ctx, node, req)
+	server.Auditor.Record(ctx, "SignWorkloadCertificate", node.Datacenter, fmt.Sprintf("%s/%s", *req.Service, *req.Instance), err)
+	return res, err
+}
+
+func (server *AgentAPIServer) signWorkloadCertificate(ctx context.Context, node *schemas.NodeSchema, req *pb.SignWorkloadCertificateRequest) (*pb.SignWorkloadCertificateResponse, error) {
+	// dcsvc/<datacenter>/<node>/<service>/<instance>
+	instanceRes, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:       fmt.Appendf(nil, "%s/%s/%s/%s/%s", utils.DCServicesNamespace, node.Datacenter, node.Name, *req.Service, *req.Instance),
+		CountOnly: true,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching service instance", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if instanceRes.Count == 0 {
+		return nil, InstanceNotFoundError
+	}
+
+	csr, err := utils.ParseCSR(req.Csr)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error parsing workload CSR", "err", err)
+		return nil, InvalidCSRError
+	}
+
+	cert, err := utils.SignWorkloadCrt(server.State, node.Datacenter, *req.Service, *req.Instance, csr.PublicKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error signing workload certificate", "err", err)
+		return nil, utils.ServerError
+	}
+
+	renewPeriod := uint64((utils.WorkloadCertificateExpiry / 2).Seconds())
+	return &pb.SignWorkloadCertificateResponse{
+		Certificate: cert,
+		CaBundle:    server.State.AgentCABundle(),
+		RenewPeriod: &renewPeriod,
+	}, nil
+}
+
+// Check the intentions for a connection from the workload id presented by a
+// client to a service registered by the agent. Unknown ids and connections
+// matching no intention are denied, a * -> * intention changes the default.
+func (server *AgentAPIServer) AuthorizeConnection(ctx context.Context, req *pb.AuthorizeConnectionRequest) (*pb.AuthorizeConnectionResponse, error) {
+	node, err := utils.AuthenticateAgent(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := server.nodeHasService(ctx, node, *req.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, InstanceNotFoundError
+	}
+
+	allowed := false
+	_, source, err := spiffe.Parse(*req.Source)
+	if err != nil {
+		slog.WarnContext(ctx, "Denied connection from invalid workload id", "source", *req.Source, "err", err)
+		return &pb.AuthorizeConnectionResponse{Allowed: &allowed}, nil
+	}
+
+	intentions, err := utils.GetIntentions(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	intention := utils.MatchIntention(intentions, source, *req.Destination)
+	if intention == nil {
+		return &pb.AuthorizeConnectionResponse{Allowed: &allowed}, nil
+	}
+
+	allowed = intention.Action == utils.IntentionAllow
+	name := fmt.Sprintf("%s -> %s", intention.Source, intention.Destination)
+	return &pb.AuthorizeConnectionResponse{Allowed: &allowed, Intention: &name}, nil
+}
@@ -0,0 +1,258 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/config"
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+	"ssle/services/logging"
+)
+
+const (
+	retentionCheckPeriod = time.Hour
+	recordTimeout        = 5 * time.Second
+)
+
+// Peer API methods only recorded when they are denied
+var readMethods = []string{
+	pb.PeerAPI_GetPeers_FullMethodName,
+	pb.PeerAPI_PeerStatus_FullMethodName,
+	pb.PeerAPI_ListNodes_FullMethodName,
+	pb.PeerAPI_GetNode_FullMethodName,
+	pb.PeerAPI_GetCAState_FullMethodName,
+	pb.PeerAPI_ListOperators_FullMethodName,
+	pb.PeerAPI_ListAuditRecords_FullMethodName,
+	pb.PeerAPI_ListPolicies_FullMethodName,
+	pb.PeerAPI_ListIntentions_FullMethodName,
+}
+
+var actorTypes = map[string]string{
+	utils.ServerCertificateOU:   "registry",
+	utils.OperatorCertificateOU: "operator",
+	utils.AgentCertificateOU:    "agent",
+	utils.ObserverCertificateOU: "observer",
+}
+
+// Records the actions handled by this registry in etcd, where they are
+// shared with the cluster, and in a local JSONL file. Failures of anonymous
+// clients are only written to the file.
+type Auditor struct {
+	config *config.Config
+	etcd   *etcdserver.EtcdServer
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func New(config *config.Config, etcd *etcdserver.EtcdServer) *Auditor {
+	file, err := os.OpenFile(config.AuditLogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		logging.Fatal("Failed to open audit log", "err", err)
+	}
+
+	return &Auditor{
+		config: config,
+		etcd:   etcd,
+		file:   file,
+	}
+}
+
+func recordKey(record *schemas.AuditRecordSchema) []byte {
+	return fmt.Appendf(nil, "%s/%020d/%s", utils.AuditNamespace, record.Time.UnixNano(), record.Registry)
+}
+
+// Key of the first record at or after the given time
+func TimeKey(t time.Time) []byte {
+	return fmt.Appendf(nil, "%s/%020d/", utils.AuditNamespace, t.UnixNano())
+}
+
+// Identify the client from its certificate, bootstrapping nodes don't have
+// one yet
+func actor(ctx context.Context, record *schemas.AuditRecordSchema) {
+	record.Actor = "anonymous"
+	record.ActorType = "anonymous"
+
+	if p, ok := peer.FromContext(ctx); ok {
+		record.Address = p.Addr.String()
+	}
+
+	cert, err := utils.ExtractPeerCertificate(ctx)
+	if err != nil {
+		return
+	}
+
+	record.Actor = cert.Subject.CommonName
+	record.ActorType = "unknown"
+	// Node certificates also have their datacenter as OU, in any order
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		if actorType, ok := actorTypes[ou]; ok {
+			record.ActorType = actorType
+		}
+	}
+}
+
+// Record an action on a target, err is the error returned to the client
+func (auditor *Auditor) Record(ctx context.Context, action string, datacenter string, target string, err error) {
+	record := schemas.AuditRecordSchema{
+		Time:       time.Now(),
+		Registry:   auditor.config.Name,
+		Action:     action,
+		Datacenter: datacenter,
+		Target:     target,
+		Outcome:    status.Code(err).String(),
+		RequestID:  logging.RequestID(ctx),
+	}
+	if err != nil {
+		record.Error = status.Convert(err).Message()
+	}
+	actor(ctx, &record)
+
+	serializedRecord, err := json.Marshal(record)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding audit record", "err", err)
+		return
+	}
+
+	auditor.mu.Lock()
+	_, err = auditor.file.Write(append(serializedRecord, '\n'))
+	auditor.mu.Unlock()
+	if err != nil {
+		slog.ErrorContext(ctx, "Error writing audit record", "err", err)
+	}
+
+	// Anyone can fail a request without a certificate, those records stay in
+	// the local file so that they can't fill the etcd quota
+	if record.ActorType == "anonymous" && record.Error != "" {
+		return
+	}
+
+	// The record is kept even if the client gave up on the request
+	putCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+
+	_, err = auditor.etcd.Put(putCtx, &etcdserverpb.PutRequest{
+		Key:   recordKey(&record),
+		Value: serializedRecord,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing audit record", "err", err)
+	}
+}
+
+type datacenterRequest interface {
+	GetDatacenter() string
+}
+
+type nameRequest interface {
+	GetName() string
+}
+
+type peerRequest interface {
+	GetPeer() string
+}
+
+type policyRequest interface {
+	GetPolicy() *pb.Policy
+}
+
+type intentionRequest interface {
+	GetIntention() *pb.Intention
+}
+
+type connectionRequest interface {
+	GetSource() string
+	GetDestination() string
+}
+
+func requestTarget(req any) (string, string) {
+	datacenter := ""
+	if r, ok := req.(datacenterRequest); ok {
+		datacenter = r.GetDatacenter()
+	}
+
+	target := ""
+	switch r := req.(type) {
+	case nameRequest:
+		target = r.GetName()
+	case peerRequest:
+		target = r.GetPeer()
+	case policyRequest:
+		target = r.GetPolicy().GetName()
+	case intentionRequest:
+		target = fmt.Sprintf("%s -> %s", r.GetIntention().GetSource(), r.GetIntention().GetDestination())
+	case connectionRequest:
+		target = fmt.Sprintf("%s -> %s", r.GetSource(), r.GetDestination())
+	}
+
+	return datacenter, target
+}
+
+func audited(method string, err error) bool {
+	if !strings.HasPrefix(method, "/PeerAPI/") {
+		return false
+	}
+
+	if slices.Contains(readMethods, method) {
+		code := status.Code(err)
+		return code == codes.PermissionDenied || code == codes.Unauthenticated
+	}
+
+	return true
+}
+
+func actionName(method string) string {
+	return method[strings.LastIndex(method, "/")+1:]
+}
+
+// Record the peer API calls changing the cluster, and the denied ones
+func (auditor *Auditor) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	res, err := handler(ctx, req)
+	if audited(info.FullMethod, err) {
+		datacenter, target := requestTarget(req)
+		auditor.Record(ctx, actionName(info.FullMethod), datacenter, target, err)
+	}
+	return res, err
+}
+
+func (auditor *Auditor) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, stream)
+	if audited(info.FullMethod, err) {
+		auditor.Record(stream.Context(), actionName(info.FullMethod), "", "", err)
+	}
+	return err
+}
+
+// Periodically delete the records older than the retention period. Every
+// registry runs it, deleting the same range is harmless.
+func (auditor *Auditor) StartRetentionJob() {
+	go func() {
+		for {
+			_, err := auditor.etcd.DeleteRange(context.Background(), &etcdserverpb.DeleteRangeRequest{
+				Key:      []byte(utils.AuditNamespace + "/"),
+				RangeEnd: TimeKey(time.Now().Add(-auditor.config.AuditRetention)),
+			})
+			if err != nil {
+				slog.Error("Failed to delete expired audit records", "err", err)
+			}
+
+			time.Sleep(retentionCheckPeriod)
+		}
+	}()
+}
@@ -2,18 +2,21 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/netip"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 
 	"ssle/registry/schemas"
+	"ssle/services/logging"
 )
 
 type Config struct {
@@ -24,6 +27,10 @@ type Config struct {
 	InitialTokenFile string `env:"TOKEN_FILE"`
 	JoinUrl          string `env:"JOIN_URL"`
 
+	// etcd snapshot to restore a new single member cluster from on the first
+	// start
+	RestoreSnapshot string `env:"RESTORE_SNAPSHOT"`
+
 	PeerListenAddr        netip.Addr       `env:"PEER_LISTEN_ADDR" envDefault:"0.0.0.0"`
 	PeerAdvertiseHostname schemas.Hostname `env:"PEER_ADVERTISE_HOSTNAME"`
 	EtcdListenPort        uint16           `env:"ETCD_LISTEN_PORT" envDefault:"2380"`
@@ -33,6 +40,31 @@ type Config struct {
 	AgentAPIListenAddr        netip.Addr       `env:"AGENT_LISTEN_ADDR" envDefault:"0.0.0.0"`
 	AgentAPIAdvertiseHostname schemas.Hostname `env:"AGENT_ADVERTISE_HOSTNAME"`
 	AgentAPIListenPort        uint16           `env:"AGENT_API_LISTEN_PORT"`
+
+	MetricsListenAddr netip.Addr `env:"METRICS_LISTEN_ADDR" envDefault:"0.0.0.0"`
+	MetricsListenPort uint16     `env:"METRICS_LISTEN_PORT"`
+
+	// One of debug, info, warn or error, formatted as text or json
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
+	LogFormat string `env:"LOG_FORMAT" envDefault:"text"`
+
+	// Serves /healthz and /readyz
+	HealthListenAddr netip.Addr `env:"HEALTH_LISTEN_ADDR" envDefault:"0.0.0.0"`
+	HealthListenPort uint16     `env:"HEALTH_LISTEN_PORT"`
+
+	// Either periodic, with a duration retention, or revision, with a number
+	// of revisions to keep
+	AutoCompactionMode      string `env:"AUTO_COMPACTION_MODE" envDefault:"periodic"`
+	AutoCompactionRetention string `env:"AUTO_COMPACTION_RETENTION" envDefault:"1h"`
+	// etcd default quota of 2GB if 0
+	QuotaBackendBytes int64 `env:"QUOTA_BACKEND_BYTES"`
+	// Registries defragment their database one at a time, never if 0
+	DefragInterval time.Duration `env:"DEFRAG_INTERVAL" envDefault:"24h"`
+
+	// Audit records are kept in etcd for the retention period and appended to
+	// a JSONL file, audit.jsonl in the state directory if unset
+	AuditLogFile   string        `env:"AUDIT_LOG_FILE"`
+	AuditRetention time.Duration `env:"AUDIT_RETENTION" envDefault:"720h"`
 }
 
 func (config *Config) PeerAPIListenHost() string {
@@ -67,10 +99,25 @@ func (config *Config) AgentAPIAdvertiseHost() string {
 	return config.AgentAPIAdvertiseHostname.HostWithPort(config.AgentAPIListenPort)
 }
 
+func (config *Config) MetricsListenHost() string {
+	return schemas.HostnameFromAddr(config.MetricsListenAddr).HostWithPort(config.MetricsListenPort)
+}
+
+func (config *Config) HealthListenHost() string {
+	return schemas.HostnameFromAddr(config.HealthListenAddr).HostWithPort(config.HealthListenPort)
+}
+
+func (config *Config) AuditLogPath() string {
+	if config.AuditLogFile != "" {
+		return config.AuditLogFile
+	}
+	return filepath.Join(config.Dir, "audit.jsonl")
+}
+
 func (config *Config) EtcdAdvertiseURLs() []url.URL {
 	advertiseUrl, err := url.Parse(fmt.Sprintf("https://%v", config.EtcdAdvertiseHost()))
 	if err != nil {
-		log.Fatalf("Invalid peer advertise URL: %v", err)
+		logging.Fatal("Invalid peer advertise URL", "err", err)
 	}
 	return []url.URL{*advertiseUrl}
 }
@@ -78,7 +125,7 @@ func (config *Config) EtcdAdvertiseURLs() []url.URL {
 func (config *Config) EtcdClientAdvertiseURLs() []url.URL {
 	advertiseUrl, err := url.Parse(fmt.Sprintf("https://%v", config.EtcdClientAdvertiseHost()))
 	if err != nil {
-		log.Fatalf("Invalid peer client advertise URL: %v", err)
+		logging.Fatal("Invalid peer client advertise URL", "err", err)
 	}
 	return []url.URL{*advertiseUrl}
 }
@@ -86,7 +133,7 @@ func (config *Config) EtcdClientAdvertiseURLs() []url.URL {
 func (config *Config) EtcdListenURLs() []url.URL {
 	listenUrl, err := url.Parse(fmt.Sprintf("https://%v", config.EtcdListenHost()))
 	if err != nil {
-		log.Fatalf("Invalid peer listen URL: %v", err)
+		logging.Fatal("Invalid peer listen URL", "err", err)
 	}
 	return []url.URL{*listenUrl}
 }
@@ -94,7 +141,15 @@ func (config *Config) EtcdListenURLs() []url.URL {
 func (config *Config) EtcdClientListenURLs() []url.URL {
 	listenUrl, err := url.Parse(fmt.Sprintf("https://%v", config.EtcdClientListenHost()))
 	if err != nil {
-		log.Fatalf("Invalid peer client listen URL: %v", err)
+		logging.Fatal("Invalid peer client listen URL", "err", err)
+	}
+	return []url.URL{*listenUrl}
+}
+
+func (config *Config) MetricsListenURLs() []url.URL {
+	listenUrl, err := url.Parse(fmt.Sprintf("http://%v", config.MetricsListenHost()))
+	if err != nil {
+		logging.Fatal("Invalid metrics listen URL", "err", err)
 	}
 	return []url.URL{*listenUrl}
 }
@@ -123,7 +178,7 @@ func findBestAddr(host string) netip.Addr {
 	if host != "" {
 		joinAddrs, err = net.LookupIP(host)
 		if err != nil {
-			log.Fatalf("Failed to resolve join url: %v", err)
+			logging.Fatal("Failed to resolve join url", "err", err)
 		}
 
 		for _, addr := range joinAddrs {
@@ -188,19 +243,23 @@ func LoadConfig() Config {
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+		logging.Fatal("Error loading configuration", "err", err)
 	}
 
 	if config.InitialTokenFile != "" {
 		token, err := os.ReadFile(config.InitialTokenFile)
 		if err != nil {
-			log.Fatalf("Error loading token file: %v", err)
+			logging.Fatal("Error loading token file", "err", err)
 		}
 		config.InitialToken = strings.TrimSpace(string(token))
 	}
 
 	if config.JoinUrl != "" && config.InitialToken == "" {
-		log.Fatal("Token or token file must be set when joining an existing cluster")
+		logging.Fatal("Token or token file must be set when joining an existing cluster")
+	}
+
+	if config.JoinUrl != "" && config.RestoreSnapshot != "" {
+		logging.Fatal("A registry restored from a snapshot starts a new cluster, it cannot join an existing one")
 	}
 
 	if !config.PeerAdvertiseHostname.IsValid() {
@@ -208,12 +267,12 @@ func LoadConfig() Config {
 		if config.JoinUrl != "" {
 			host, _, err = net.SplitHostPort(config.JoinUrl)
 			if err != nil {
-				log.Fatalf("Invalid join url: %v", err)
+				logging.Fatal("Invalid join url", "err", err)
 			}
 		}
 
 		config.PeerAdvertiseHostname = schemas.HostnameFromAddr(findBestAddr(host))
-		log.Printf("Etcd advertise addr: %v", config.EtcdAdvertiseHost())
+		slog.Info("Etcd advertise addr", "addr", config.EtcdAdvertiseHost())
 	}
 
 	if !config.AgentAPIAdvertiseHostname.IsValid() {
@@ -222,17 +281,29 @@ func LoadConfig() Config {
 
 	if config.EtcdClientListenPort == 0 {
 		config.EtcdClientListenPort = config.EtcdListenPort + 1
-		log.Printf("Etcd client advertise addr: %v", config.EtcdClientAdvertiseHost())
+		slog.Info("Etcd client advertise addr", "addr", config.EtcdClientAdvertiseHost())
 	}
 
 	if config.PeerAPIListenPort == 0 {
 		config.PeerAPIListenPort = config.EtcdClientListenPort + 1
-		log.Printf("Peer api advertise host: %v", config.PeerAPIAdvertiseHost())
+		slog.Info("Peer api advertise host", "host", config.PeerAPIAdvertiseHost())
 	}
 
 	if config.AgentAPIListenPort == 0 {
 		config.AgentAPIListenPort = config.PeerAPIListenPort + 1
 	}
 
+	if config.MetricsListenPort == 0 {
+		config.MetricsListenPort = config.AgentAPIListenPort + 1
+	}
+
+	if config.HealthListenPort == 0 {
+		config.HealthListenPort = config.MetricsListenPort + 1
+	}
+
+	if config.AutoCompactionMode != "periodic" && config.AutoCompactionMode != "revision" {
+		logging.Fatal("Invalid auto compaction mode, must be periodic or revision", "mode", config.AutoCompactionMode)
+	}
+
 	return config
 }
@@ -0,0 +1,171 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/config"
+	"ssle/registry/schemas"
+	"ssle/registry/state"
+	"ssle/registry/utils"
+)
+
+const (
+	caSyncRetryPeriod = 5 * time.Second
+)
+
+// Publish the CA generations etcd trusts on this registry, CA rollovers wait
+// for every member to trust a generation before activating it.
+func publishCATrust(config *config.Config, state *state.State, etcd *etcdserver.EtcdServer) error {
+	serialized, err := json.Marshal(state.StartupTrust)
+	if err != nil {
+		return err
+	}
+
+	_, err = etcd.Put(context.Background(), &etcdserverpb.PutRequest{
+		Key:   fmt.Appendf(nil, "%s/%s", utils.CATrustNamespace, config.Name),
+		Value: serialized,
+	})
+	return err
+}
+
+// Fetches the token of a CA generation from another registry
+type CATokenFetcher func(generation int) ([]byte, error)
+
+// Adopt a CA state, fetching the tokens of the generations derived from a
+// rotated cluster token first so they can be activated. Missing tokens are
+// reported after the state was applied, so the caller retries later.
+func applyCAState(state *state.State, value []byte, fetchToken CATokenFetcher) error {
+	var caState schemas.CAStateSchema
+	err := json.Unmarshal(value, &caState)
+	if err != nil {
+		return fmt.Errorf("decoding CA state: %w", err)
+	}
+
+	var fetchErr error
+	for _, generation := range state.MissingCATokens(caState) {
+		token, err := fetchToken(generation.Generation)
+		if err == nil {
+			err = state.AddCAToken(generation, token)
+		}
+
+		if err != nil {
+			fetchErr = fmt.Errorf("fetching the token of CA generation %d: %w", generation.Generation, err)
+			continue
+		}
+
+		slog.Info("Fetched the token of a CA generation", "generation", generation.Generation)
+	}
+
+	err = state.UpdateCAState(caState)
+	if err != nil {
+		return fmt.Errorf("updating CAs: %w", err)
+	}
+
+	return fetchErr
+}
+
+// Store the local CA state if the cluster has none yet, otherwise adopt the
+// cluster one. Returns the revision to watch for changes from.
+func initCAState(state *state.State, etcd *etcdserver.EtcdServer, fetchToken CATokenFetcher) (int64, error) {
+	serialized, err := json.Marshal(state.CAState())
+	if err != nil {
+		return 0, err
+	}
+
+	key := []byte(utils.CAStateKey)
+	res, err := etcd.Txn(context.Background(), &etcdserverpb.TxnRequest{
+		Compare: []*etcdserverpb.Compare{{
+			Result: etcdserverpb.Compare_EQUAL,
+			Target: etcdserverpb.Compare_CREATE,
+			Key:    key,
+			TargetUnion: &etcdserverpb.Compare_CreateRevision{
+				CreateRevision: int64(0),
+			},
+		}},
+		Success: []*etcdserverpb.RequestOp{{
+			Request: &etcdserverpb.RequestOp_RequestPut{
+				RequestPut: &etcdserverpb.PutRequest{Key: key, Value: serialized},
+			},
+		}},
+		Failure: []*etcdserverpb.RequestOp{{
+			Request: &etcdserverpb.RequestOp_RequestRange{
+				RequestRange: &etcdserverpb.RangeRequest{Key: key},
+			},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !res.Succeeded {
+		kvs := res.Responses[0].GetResponseRange().Kvs
+		if len(kvs) > 0 {
+			err = applyCAState(state, kvs[0].Value, fetchToken)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return res.Header.Revision, nil
+}
+
+func watchCAState(state *state.State, etcd *etcdserver.EtcdServer, startRev int64, fetchToken CATokenFetcher) error {
+	watchStream := etcd.Watchable().NewWatchStream()
+	defer watchStream.Close()
+
+	_, err := watchStream.Watch(0, []byte(utils.CAStateKey), nil, startRev)
+	if err != nil {
+		return err
+	}
+
+	for msg := range watchStream.Chan() {
+		if msg.CompactRevision != 0 {
+			return fmt.Errorf("watch revision was compacted")
+		}
+
+		for _, event := range msg.Events {
+			if event.Type == mvccpb.PUT {
+				err = applyCAState(state, event.Kv.Value, fetchToken)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Keep the registry CAs in sync with the cluster CA state, the state is read
+// again after failures
+func StartCASync(config *config.Config, state *state.State, etcd *etcdserver.EtcdServer, fetchToken CATokenFetcher) {
+	err := publishCATrust(config, state, etcd)
+	if err != nil {
+		slog.Error("Failed to publish trusted CAs", "err", err)
+	}
+
+	go func() {
+		for {
+			rev, err := initCAState(state, etcd, fetchToken)
+			if err == nil {
+				err = watchCAState(state, etcd, rev+1, fetchToken)
+			}
+			if err != nil {
+				slog.Error("Error syncing CA state", "err", err)
+			}
+
+			time.Sleep(caSyncRetryPeriod)
+		}
+	}()
+
+	go state.ServerCrtRenewalJob()
+}
@@ -0,0 +1,202 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/storage"
+
+	"ssle/registry/config"
+	"ssle/registry/utils"
+)
+
+const (
+	defragCheckPeriod = 10 * time.Minute
+	// Long enough for the defragmentation to finish, the lock is released if
+	// the registry dies while holding it
+	defragLockTTL = 600
+	// Share of the quota the database can use before warning about it
+	quotaWarningRatio = 0.8
+	// Registries take turns of this length to defragment once the quota is
+	// exceeded
+	noSpaceDefragSlot = 2 * time.Minute
+)
+
+// Time of the last defragmentation of this registry
+func lastDefrag(ctx context.Context, config *config.Config, etcd *embed.Etcd) (time.Time, error) {
+	res, err := etcd.Server.Range(ctx, &etcdserverpb.RangeRequest{
+		Key: fmt.Appendf(nil, "%s/%s", utils.DefragNamespace, config.Name),
+	})
+	if err != nil || len(res.Kvs) == 0 {
+		return time.Time{}, err
+	}
+
+	return time.Parse(time.RFC3339, string(res.Kvs[0].Value))
+}
+
+// Take the cluster wide defragmentation lock, so a single registry at a time
+// stops answering while it defragments. The lock is released by revoking the
+// returned lease.
+func acquireDefragLock(ctx context.Context, config *config.Config, etcd *embed.Etcd) (int64, bool, error) {
+	lease, err := etcd.Server.LeaseGrant(ctx, &etcdserverpb.LeaseGrantRequest{TTL: defragLockTTL})
+	if err != nil {
+		return 0, false, err
+	}
+
+	key := []byte(utils.DefragLockKey)
+	res, err := etcd.Server.Txn(ctx, &etcdserverpb.TxnRequest{
+		Compare: []*etcdserverpb.Compare{{
+			Result:      etcdserverpb.Compare_EQUAL,
+			Target:      etcdserverpb.Compare_CREATE,
+			Key:         key,
+			TargetUnion: &etcdserverpb.Compare_CreateRevision{CreateRevision: 0},
+		}},
+		Success: []*etcdserverpb.RequestOp{{
+			Request: &etcdserverpb.RequestOp_RequestPut{
+				RequestPut: &etcdserverpb.PutRequest{Key: key, Value: []byte(config.Name), Lease: lease.ID},
+			},
+		}},
+	})
+	if err != nil || !res.Succeeded {
+		etcd.Server.LeaseRevoke(ctx, &etcdserverpb.LeaseRevokeRequest{ID: lease.ID})
+		return 0, false, err
+	}
+
+	return lease.ID, true, nil
+}
+
+func recordDefrag(ctx context.Context, config *config.Config, etcd *embed.Etcd) error {
+	_, err := etcd.Server.Put(ctx, &etcdserverpb.PutRequest{
+		Key:   fmt.Appendf(nil, "%s/%s", utils.DefragNamespace, config.Name),
+		Value: []byte(time.Now().Format(time.RFC3339)),
+	})
+	return err
+}
+
+func noSpaceAlarm(etcd *embed.Etcd) bool {
+	for _, alarm := range etcd.Server.Alarms() {
+		if alarm.MemberID == uint64(etcd.Server.MemberID()) && alarm.Alarm == etcdserverpb.AlarmType_NOSPACE {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Wait for the turn of this registry to defragment without the lock. Turns
+// follow the order of the member IDs and repeat every slot times the number of
+// members, every registry computes them from the time alone.
+func waitDefragTurn(etcd *embed.Etcd) {
+	ids := []uint64{}
+	for _, member := range etcd.Server.Cluster().Members() {
+		ids = append(ids, uint64(member.ID))
+	}
+	slices.Sort(ids)
+
+	index := max(slices.Index(ids, uint64(etcd.Server.MemberID())), 0)
+	cycle := noSpaceDefragSlot * time.Duration(len(ids))
+
+	now := time.Now()
+	turn := now.Truncate(cycle).Add(noSpaceDefragSlot * time.Duration(index))
+	if turn.Before(now) {
+		turn = turn.Add(cycle)
+	}
+
+	slog.Warn("Etcd database quota exceeded, waiting for the turn to defragment", "at", turn)
+	time.Sleep(time.Until(turn))
+}
+
+func defrag(ctx context.Context, config *config.Config, etcd *embed.Etcd) error {
+	backend := etcd.Server.Backend()
+
+	// Once the quota is exceeded the lock can't be taken anymore, so the
+	// registries defragment in turns to accept writes again
+	if noSpaceAlarm(etcd) {
+		waitDefragTurn(etcd)
+
+		if !noSpaceAlarm(etcd) {
+			return nil
+		}
+		slog.Warn("Etcd database quota exceeded, defragmenting")
+	} else {
+		last, err := lastDefrag(ctx, config, etcd)
+		if err != nil {
+			return err
+		}
+
+		// New registries start with a compact database
+		if last.IsZero() {
+			return recordDefrag(ctx, config, etcd)
+		}
+
+		if time.Since(last) < config.DefragInterval {
+			return nil
+		}
+
+		lease, acquired, err := acquireDefragLock(ctx, config, etcd)
+		if err != nil || !acquired {
+			return err
+		}
+		defer etcd.Server.LeaseRevoke(ctx, &etcdserverpb.LeaseRevokeRequest{ID: lease})
+	}
+
+	sizeBefore := backend.Size()
+
+	err := backend.Defrag()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Defragmented etcd database", "size_before", sizeBefore, "size", backend.Size())
+
+	// Writes are refused until the alarm is cleared, etcd raises it again if
+	// the database is still too large
+	if noSpaceAlarm(etcd) {
+		_, err = etcd.Server.Alarm(ctx, &etcdserverpb.AlarmRequest{
+			Action:   etcdserverpb.AlarmRequest_DEACTIVATE,
+			MemberID: uint64(etcd.Server.MemberID()),
+			Alarm:    etcdserverpb.AlarmType_NOSPACE,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return recordDefrag(ctx, config, etcd)
+}
+
+func checkQuota(config *config.Config, etcd *embed.Etcd) {
+	quota := config.QuotaBackendBytes
+	if quota == 0 {
+		quota = storage.DefaultQuotaBytes
+	}
+
+	size := etcd.Server.Backend().Size()
+	if float64(size) > float64(quota)*quotaWarningRatio {
+		slog.Warn("Etcd database is close to its quota", "size", size, "quota", quota)
+	}
+}
+
+// Periodically defragment the etcd database, compaction only frees space
+// inside of it
+func StartMaintenanceJob(config *config.Config, etcd *embed.Etcd) {
+	go func() {
+		for {
+			checkQuota(config, etcd)
+
+			if config.DefragInterval > 0 {
+				err := defrag(context.Background(), config, etcd)
+				if err != nil {
+					slog.Error("Failed to defragment etcd database", "err", err)
+				}
+			}
+
+			time.Sleep(defragCheckPeriod)
+		}
+	}()
+}
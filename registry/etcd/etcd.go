@@ -6,8 +6,10 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/url"
+	"strings"
+	"time"
 
 	"ssle/registry/config"
 	"ssle/registry/state"
@@ -19,6 +21,8 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 )
 
+const learnerPromotionInterval = 5 * time.Second
+
 func EtcdPostStartUpdate(config *config.Config, etcd *embed.Etcd) {
 	PeerURLs := make([]string, len(etcd.Config().AdvertisePeerUrls))
 	for i, url := range etcd.Config().AdvertisePeerUrls {
@@ -36,7 +40,7 @@ func EtcdPostStartUpdate(config *config.Config, etcd *embed.Etcd) {
 			ID: etcd.Server.MemberID(),
 			RaftAttributes: membership.RaftAttributes{
 				PeerURLs:  PeerURLs,
-				IsLearner: false,
+				IsLearner: etcd.Server.IsLearner(),
 			},
 			Attributes: membership.Attributes{
 				ClientURLs: ClientUrls,
@@ -44,7 +48,7 @@ func EtcdPostStartUpdate(config *config.Config, etcd *embed.Etcd) {
 		},
 	)
 	if err != nil {
-		log.Printf("Failed to update member URLs: %v", err)
+		slog.Error("Failed to update member URLs", "err", err)
 	}
 
 	key := fmt.Appendf(nil, "%v/%v", utils.PeerAgentApiNamespace, etcd.Config().Name)
@@ -53,20 +57,51 @@ func EtcdPostStartUpdate(config *config.Config, etcd *embed.Etcd) {
 		Value: []byte(config.AgentAPIAdvertiseHost()),
 	})
 	if err != nil {
-		log.Printf("Failed to update member agent api address: %v", err)
+		slog.Error("Failed to update member agent api address", "err", err)
+	}
+
+	// Registries reach each other on the peer API to share rotated tokens
+	key = fmt.Appendf(nil, "%v/%v", utils.PeerApiNamespace, etcd.Config().Name)
+	_, err = etcd.Server.Put(context.Background(), &etcdserverpb.PutRequest{
+		Key:   key,
+		Value: []byte(config.PeerAPIAdvertiseHost()),
+	})
+	if err != nil {
+		slog.Error("Failed to update member peer api address", "err", err)
 	}
 }
 
-func CreateEtcdConfig(members []membership.Member, state *state.State, config *config.Config) *embed.Config {
+// Registries join as learners, ask for a promotion until the leader considers
+// them caught up
+func StartSelfPromotion(etcd *embed.Etcd) {
+	go func() {
+		for etcd.Server.IsLearner() {
+			_, err := etcd.Server.PromoteMember(context.Background(), uint64(etcd.Server.MemberID()))
+			if err == nil {
+				slog.Info("Promoted to a voting member")
+				return
+			}
+
+			slog.Debug("Not promoted to a voting member yet", "err", err)
+			time.Sleep(learnerPromotionInterval)
+		}
+	}()
+}
+
+func clusterToken(state *state.State) string {
 	etcdToken, err := hkdf.Expand(sha256.New, state.Token, "etcd", 32)
 	if err != nil {
 		panic(err.Error())
 	}
 
+	return base64.StdEncoding.EncodeToString(etcdToken)
+}
+
+func CreateEtcdConfig(members []membership.Member, state *state.State, config *config.Config) *embed.Config {
 	etcdCfg := embed.NewConfig()
 	etcdCfg.Name = config.Name
 	etcdCfg.Dir = state.EtcdDir
-	etcdCfg.InitialClusterToken = base64.StdEncoding.EncodeToString(etcdToken)
+	etcdCfg.InitialClusterToken = clusterToken(state)
 
 	etcdCfg.PeerTLSInfo = transport.TLSInfo{
 		ServerName:     "registry.cluster.internal",
@@ -84,6 +119,18 @@ func CreateEtcdConfig(members []membership.Member, state *state.State, config *c
 	etcdCfg.ListenClientHttpUrls = []url.URL{}
 	etcdCfg.AdvertiseClientUrls = config.EtcdClientAdvertiseURLs()
 
+	etcdCfg.ListenMetricsUrls = config.MetricsListenURLs()
+
+	etcdCfg.LogLevel = strings.ToLower(config.LogLevel)
+	etcdCfg.LogFormat = "console"
+	if config.LogFormat == "json" {
+		etcdCfg.LogFormat = "json"
+	}
+
+	etcdCfg.AutoCompactionMode = config.AutoCompactionMode
+	etcdCfg.AutoCompactionRetention = config.AutoCompactionRetention
+	etcdCfg.QuotaBackendBytes = config.QuotaBackendBytes
+
 	etcdCfg.InitialCluster = etcdCfg.InitialClusterFromName(config.Name)
 	for _, member := range members {
 		if member.Name == config.Name {
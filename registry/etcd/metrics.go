@@ -0,0 +1,104 @@
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/utils"
+)
+
+const metricsCollectTimeout = 5 * time.Second
+
+// Reads the registry contents from etcd on every scrape
+type etcdCollector struct {
+	etcd *etcdserver.EtcdServer
+
+	nodes    *prometheus.Desc
+	services *prometheus.Desc
+	leases   *prometheus.Desc
+}
+
+func (collector *etcdCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.nodes
+	ch <- collector.services
+	ch <- collector.leases
+}
+
+// Count the keys under a namespace by the datacenter following it
+func (collector *etcdCollector) countByDatacenter(ctx context.Context, namespace string) (map[string]int, error) {
+	prefix := fmt.Appendf(nil, "%s/", namespace)
+	res, err := collector.etcd.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+		KeysOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, kv := range res.Kvs {
+		dc, _, _ := bytes.Cut(bytes.TrimPrefix(kv.Key, prefix), []byte("/"))
+		counts[string(dc)] += 1
+	}
+
+	return counts, nil
+}
+
+func (collector *etcdCollector) collectByDatacenter(ctx context.Context, ch chan<- prometheus.Metric, desc *prometheus.Desc, namespace string) {
+	counts, err := collector.countByDatacenter(ctx, namespace)
+	if err != nil {
+		slog.Error("Error collecting metrics", "namespace", namespace, "err", err)
+		return
+	}
+
+	for dc, count := range counts {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), dc)
+	}
+}
+
+func (collector *etcdCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsCollectTimeout)
+	defer cancel()
+
+	collector.collectByDatacenter(ctx, ch, collector.nodes, utils.NodesNamespace)
+	collector.collectByDatacenter(ctx, ch, collector.services, utils.DCServicesNamespace)
+
+	leases, err := collector.etcd.LeaseLeases(ctx, &etcdserverpb.LeaseLeasesRequest{})
+	if err != nil {
+		slog.Error("Error collecting lease metrics", "err", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(collector.leases, prometheus.GaugeValue, float64(len(leases.Leases)))
+}
+
+func RegisterMetricsCollector(etcd *etcdserver.EtcdServer) {
+	prometheus.MustRegister(&etcdCollector{
+		etcd: etcd,
+		nodes: prometheus.NewDesc(
+			"ssle_registry_nodes",
+			"Nodes registered in the registry",
+			[]string{"datacenter"},
+			nil,
+		),
+		services: prometheus.NewDesc(
+			"ssle_registry_services",
+			"Service instances registered by the nodes",
+			[]string{"datacenter"},
+			nil,
+		),
+		leases: prometheus.NewDesc(
+			"ssle_registry_leases",
+			"Active etcd leases, for node keepalives, certificates and tokens",
+			nil,
+			nil,
+		),
+	})
+}
@@ -0,0 +1,84 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.uber.org/zap"
+
+	"ssle/registry/config"
+	"ssle/registry/state"
+	"ssle/registry/utils"
+	"ssle/services/logging"
+)
+
+// Restore the etcd data directory of a new single member cluster from a
+// snapshot, unless the registry already has one. Returns whether it did.
+func RestoreSnapshot(config *config.Config, state *state.State) bool {
+	if _, err := os.Stat(state.EtcdDir); err == nil {
+		slog.Warn("Etcd data already exists, not restoring", "snapshot", config.RestoreSnapshot)
+		return false
+	}
+
+	lg, err := zap.NewProduction()
+	if err != nil {
+		logging.Fatal("Failed to create restore logger", "err", err)
+	}
+
+	peerUrls := make([]string, len(config.EtcdAdvertiseURLs()))
+	initialCluster := make([]string, len(config.EtcdAdvertiseURLs()))
+	for i, u := range config.EtcdAdvertiseURLs() {
+		peerUrls[i] = u.String()
+		initialCluster[i] = fmt.Sprintf("%s=%s", config.Name, u.String())
+	}
+
+	err = snapshot.NewV3(lg).Restore(snapshot.RestoreConfig{
+		SnapshotPath:        config.RestoreSnapshot,
+		Name:                config.Name,
+		OutputDataDir:       state.EtcdDir,
+		PeerURLs:            peerUrls,
+		InitialCluster:      strings.Join(initialCluster, ","),
+		InitialClusterToken: clusterToken(state),
+	})
+	if err != nil {
+		logging.Fatal("Failed to restore snapshot", "err", err)
+	}
+
+	slog.Info("Restored a new cluster", "snapshot", config.RestoreSnapshot)
+
+	return true
+}
+
+// Remove the API addresses, CA trust and defragmentation times of the
+// registries of the cluster the snapshot was taken from.
+func PruneRestoredPeers(config *config.Config, etcd *embed.Etcd) {
+	for _, namespace := range []string{utils.PeerAgentApiNamespace, utils.PeerApiNamespace, utils.CATrustNamespace, utils.DefragNamespace} {
+		prefix := fmt.Appendf(nil, "%s/", namespace)
+		res, err := etcd.Server.Range(context.Background(), &etcdserverpb.RangeRequest{
+			Key:      prefix,
+			RangeEnd: utils.PrefixEnd(prefix),
+			KeysOnly: true,
+		})
+		if err != nil {
+			slog.Error("Failed to list restored peers", "err", err)
+			return
+		}
+
+		for _, kv := range res.Kvs {
+			if string(kv.Key) == fmt.Sprintf("%s/%s", namespace, config.Name) {
+				continue
+			}
+
+			_, err = etcd.Server.DeleteRange(context.Background(), &etcdserverpb.DeleteRangeRequest{Key: kv.Key})
+			if err != nil {
+				slog.Error("Failed to remove restored peer", "key", string(kv.Key), "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/embed"
+	grpc_health "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"ssle/registry/config"
+	"ssle/registry/state"
+	"ssle/services/health"
+	"ssle/services/logging"
+)
+
+const healthUpdatePeriod = 5 * time.Second
+
+// Readiness of the registry, served over the gRPC health service of both
+// APIs and over HTTP
+type Checker struct {
+	state *state.State
+	etcd  *embed.Etcd
+
+	agentAPIStarted atomic.Bool
+
+	Server *grpc_health.Server
+}
+
+func NewChecker(state *state.State, etcd *embed.Etcd) *Checker {
+	server := grpc_health.NewServer()
+	server.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	return &Checker{
+		state:  state,
+		etcd:   etcd,
+		Server: server,
+	}
+}
+
+func (checker *Checker) AgentAPIStarted() {
+	checker.agentAPIStarted.Store(true)
+}
+
+func (checker *Checker) checkEtcd(ctx context.Context) error {
+	select {
+	case <-checker.etcd.Server.ReadyNotify():
+	default:
+		return fmt.Errorf("etcd is not ready")
+	}
+
+	if checker.etcd.Server.Leader() == 0 {
+		return fmt.Errorf("etcd has no leader")
+	}
+
+	for _, alarm := range checker.etcd.Server.Alarms() {
+		if alarm.MemberID == uint64(checker.etcd.Server.MemberID()) {
+			return fmt.Errorf("etcd alarm %v", alarm.Alarm)
+		}
+	}
+
+	return nil
+}
+
+func (checker *Checker) checkAgentAPI(ctx context.Context) error {
+	if !checker.agentAPIStarted.Load() {
+		return fmt.Errorf("agent API is not started")
+	}
+
+	return nil
+}
+
+func (checker *Checker) checkCertificate(ctx context.Context) error {
+	leaf := checker.state.ServerCertificate().Leaf
+
+	now := time.Now()
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("server certificate is not valid before %v", leaf.NotBefore)
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("server certificate expired at %v", leaf.NotAfter)
+	}
+
+	return nil
+}
+
+func (checker *Checker) checks() []health.ReadinessCheck {
+	return []health.ReadinessCheck{
+		{Name: "etcd", Check: checker.checkEtcd},
+		{Name: "agent_api", Check: checker.checkAgentAPI},
+		{Name: "certificate", Check: checker.checkCertificate},
+	}
+}
+
+func (checker *Checker) ready() bool {
+	for _, check := range checker.checks() {
+		if check.Check(context.Background()) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Keep the gRPC serving status up to date and serve /healthz, which answers
+// as long as the process is running, and /readyz
+func (checker *Checker) Start(config *config.Config) {
+	go func() {
+		for {
+			status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			if checker.ready() {
+				status = grpc_health_v1.HealthCheckResponse_SERVING
+			}
+			checker.Server.SetServingStatus("", status)
+
+			time.Sleep(healthUpdatePeriod)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	health.HandleHealth(mux, checker.checks())
+
+	listenAddr := config.HealthListenHost()
+	go func() {
+		err := http.ListenAndServe(listenAddr, mux)
+		if err != nil {
+			logging.Fatal("Failed to start health server", "err", err)
+		}
+	}()
+
+	slog.Info("Started health server", "addr", "http://"+listenAddr)
+}
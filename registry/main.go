@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"strconv"
 	"time"
 
@@ -11,19 +11,29 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 
 	"ssle/registry/agent_api"
+	"ssle/registry/audit"
 	"ssle/registry/config"
 	"ssle/registry/etcd"
+	"ssle/registry/health"
 	"ssle/registry/peer_api"
 	"ssle/registry/state"
 	"ssle/services"
+	"ssle/services/logging"
 )
 
 func main() {
 	var err error
 
 	config := config.LoadConfig()
+	logging.Setup(config.LogLevel, config.LogFormat, "registry", config.Name)
+
 	state := state.LoadState(config)
-	peer_api_client := peer_api.NewPeerApiClient(config.JoinUrl, &state)
+	peer_api_client := peer_api.NewPeerApiClient(config.JoinUrl, state)
+
+	restored := false
+	if config.RestoreSnapshot != "" {
+		restored = etcd.RestoreSnapshot(&config, state)
+	}
 
 	if config.JoinUrl != "" {
 		urls := make([]string, len(config.EtcdAdvertiseURLs()))
@@ -34,30 +44,29 @@ func main() {
 			AdvertisedUrls: urls,
 		})
 		if err != nil {
-			log.Printf("Failed to join cluster: %v", err)
-			log.Print("Continuing with registry startup, server may crash")
+			slog.Error("Failed to join cluster", "err", err)
+			slog.Warn("Continuing with registry startup, server may crash")
 		}
 	}
 
 	members := []membership.Member{}
 	if config.JoinUrl != "" {
 		res, err := peer_api_client.GetPeers(context.Background(), &services.GetPeersRequest{})
-		log.Printf("Existing cluster members: %v", members)
 		if err != nil {
-			log.Fatalf("Failed to get cluster members: %v", err)
+			logging.Fatal("Failed to get cluster members", "err", err)
 		}
 
 		members = make([]membership.Member, len(res.Peers))
 		for i, peer := range res.Peers {
 			id, err := strconv.ParseUint(*peer.Id, 10, 64)
 			if err != nil {
-				log.Fatal(err.Error())
+				logging.Fatal("Invalid peer id", "id", *peer.Id, "err", err)
 			}
 			members[i] = membership.Member{
 				ID: types.ID(id),
 				RaftAttributes: membership.RaftAttributes{
 					PeerURLs:  peer.PeerUrls,
-					IsLearner: false,
+					IsLearner: peer.GetIsLearner(),
 				},
 				Attributes: membership.Attributes{
 					ClientURLs: peer.ClientUrls,
@@ -65,29 +74,46 @@ func main() {
 				},
 			}
 		}
+		slog.Info("Existing cluster members", "members", members)
 	}
 
-	etcdConfig := etcd.CreateEtcdConfig(members, &state, &config)
+	etcdConfig := etcd.CreateEtcdConfig(members, state, &config)
 
 	// Register our extensions into etcd client listener
 	e, err := embed.StartEtcd(etcdConfig)
 	if err != nil {
-		log.Fatalf("Failed to start etcd server: %v", err)
+		logging.Fatal("Failed to start etcd server", "err", err)
 	}
 	defer e.Close()
 
-	peer_api.StartApiServer(&config, &state, e.Server)
+	checker := health.NewChecker(state, e)
+	checker.Start(&config)
+
+	auditor := audit.New(&config, e.Server)
+
+	peer_api.StartApiServer(&config, state, e.Server, auditor, checker.Server)
 
 	select {
 	case <-e.Server.ReadyNotify():
-		log.Print("Server is ready!")
+		slog.Info("Server is ready!")
 		etcd.EtcdPostStartUpdate(&config, e)
+		if restored {
+			etcd.PruneRestoredPeers(&config, e)
+		}
+		etcd.StartCASync(&config, state, e.Server, func(generation int) ([]byte, error) {
+			return peer_api.FetchCAToken(&config, state, e.Server, generation)
+		})
+		etcd.StartSelfPromotion(e)
+		etcd.StartMaintenanceJob(&config, e)
+		etcd.RegisterMetricsCollector(e.Server)
+		auditor.StartRetentionJob()
 
-		agent_api.StartApiServer(&config, &state, e.Server)
+		agent_api.StartApiServer(&config, state, e.Server, auditor, checker.Server)
+		checker.AgentAPIStarted()
 	case <-time.After(60 * time.Second):
 		e.Server.Stop() // trigger a shutdown
-		log.Print("Server took too long to start!")
+		slog.Error("Server took too long to start!")
 	}
 
-	log.Fatal(<-e.Err())
+	logging.Fatal("Etcd server stopped", "err", <-e.Err())
 }
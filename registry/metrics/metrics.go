@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The metrics are served with the embedded etcd ones on the metrics listener
+var (
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_registry_grpc_requests_total",
+		Help: "gRPC requests handled by the registry APIs",
+	}, []string{"service", "method", "code"})
+
+	grpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ssle_registry_grpc_request_duration_seconds",
+		Help:    "Duration of the gRPC requests handled by the registry APIs",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	authFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_registry_auth_failures_total",
+		Help: "gRPC requests rejected because the client failed to authenticate",
+	}, []string{"service", "method"})
+
+	ObserverWatchStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssle_registry_observer_watch_streams",
+		Help: "Observers watching the services of their datacenter",
+	})
+
+	CertificatesIssued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssle_registry_certificates_issued_total",
+		Help: "Node certificates signed by the registry",
+	}, []string{"datacenter", "role"})
+)
+
+// Split "/Service/Method" into its parts
+func splitMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+func observe(fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	code := status.Code(err)
+
+	grpcRequests.WithLabelValues(service, method, code.String()).Inc()
+	grpcRequestDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+
+	if code == codes.Unauthenticated {
+		authFailures.WithLabelValues(service, method).Inc()
+	}
+}
+
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	observe(info.FullMethod, start, err)
+	return res, err
+}
+
+func StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observe(info.FullMethod, start, err)
+	return err
+}
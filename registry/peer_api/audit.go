@@ -0,0 +1,70 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/protobuf/proto"
+
+	"ssle/registry/audit"
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+func auditRecordToPb(record *schemas.AuditRecordSchema) *pb.AuditRecord {
+	res := &pb.AuditRecord{
+		TimestampMs: proto.Int64(record.Time.UnixMilli()),
+		Registry:    &record.Registry,
+		Actor:       &record.Actor,
+		ActorType:   &record.ActorType,
+		Action:      &record.Action,
+		Outcome:     &record.Outcome,
+	}
+	if record.Address != "" {
+		res.Address = &record.Address
+	}
+	if record.Datacenter != "" {
+		res.Datacenter = &record.Datacenter
+	}
+	if record.Target != "" {
+		res.Target = &record.Target
+	}
+	if record.Error != "" {
+		res.Error = &record.Error
+	}
+	if record.RequestID != "" {
+		res.RequestId = &record.RequestID
+	}
+	return res
+}
+
+// List the audit records of the whole cluster, oldest first
+func (server *PeerAPIServer) ListAuditRecords(ctx context.Context, req *pb.ListAuditRecordsRequest) (*pb.ListAuditRecordsResponse, error) {
+	prefix := []byte(utils.AuditNamespace + "/")
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      audit.TimeKey(time.UnixMilli(req.GetSinceMs())),
+		RangeEnd: utils.PrefixEnd(prefix),
+		Limit:    int64(req.GetLimit()),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching audit records", "err", err)
+		return nil, utils.ServerError
+	}
+
+	records := make([]*pb.AuditRecord, len(res.Kvs))
+	for i, kv := range res.Kvs {
+		var record schemas.AuditRecordSchema
+		err = json.Unmarshal(kv.Value, &record)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding audit record", "err", err)
+			return nil, utils.ServerError
+		}
+		records[i] = auditRecordToPb(&record)
+	}
+
+	return &pb.ListAuditRecordsResponse{Records: records}, nil
+}
@@ -0,0 +1,237 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+	"ssle/services/logging"
+)
+
+var PermissionDeniedError = status.Errorf(codes.PermissionDenied, "Permission denied")
+
+const (
+	RoleAdmin       = "admin"
+	RoleNodeManager = "node-manager"
+	RoleReadOnly    = "read-only"
+)
+
+// Roles allowed to call each method, methods of the peer API missing from
+// this map can only be called by registries
+var methodRoles = map[string][]string{
+	pb.PeerAPI_GetPeers_FullMethodName:       {RoleAdmin, RoleNodeManager, RoleReadOnly},
+	pb.PeerAPI_PeerStatus_FullMethodName:     {RoleAdmin, RoleNodeManager, RoleReadOnly},
+	pb.PeerAPI_ListNodes_FullMethodName:      {RoleAdmin, RoleNodeManager, RoleReadOnly},
+	pb.PeerAPI_GetNode_FullMethodName:        {RoleAdmin, RoleNodeManager, RoleReadOnly},
+	pb.PeerAPI_ListPolicies_FullMethodName:   {RoleAdmin, RoleNodeManager, RoleReadOnly},
+	pb.PeerAPI_ListIntentions_FullMethodName: {RoleAdmin, RoleNodeManager, RoleReadOnly},
+
+	pb.PeerAPI_AddNode_FullMethodName:                {RoleAdmin, RoleNodeManager},
+	pb.PeerAPI_GetNodeCredentials_FullMethodName:     {RoleAdmin, RoleNodeManager},
+	pb.PeerAPI_RemoveNode_FullMethodName:             {RoleAdmin, RoleNodeManager},
+	pb.PeerAPI_SetNodeDisabled_FullMethodName:        {RoleAdmin, RoleNodeManager},
+	pb.PeerAPI_RevokeNodeCertificates_FullMethodName: {RoleAdmin, RoleNodeManager},
+
+	pb.PeerAPI_CreateOperator_FullMethodName: {RoleAdmin},
+	pb.PeerAPI_ListOperators_FullMethodName:  {RoleAdmin},
+	pb.PeerAPI_RemoveOperator_FullMethodName: {RoleAdmin},
+}
+
+// Methods acting on the whole cluster, denied to operators restricted to a
+// datacenter
+var clusterMethods = []string{
+	pb.PeerAPI_RemovePeer_FullMethodName,
+	pb.PeerAPI_PromotePeer_FullMethodName,
+	pb.PeerAPI_Snapshot_FullMethodName,
+	pb.PeerAPI_GetCAState_FullMethodName,
+	pb.PeerAPI_RotateCA_FullMethodName,
+	pb.PeerAPI_RotateToken_FullMethodName,
+	pb.PeerAPI_ListAuditRecords_FullMethodName,
+	pb.PeerAPI_SetPolicy_FullMethodName,
+	pb.PeerAPI_RemovePolicy_FullMethodName,
+	pb.PeerAPI_SetIntention_FullMethodName,
+	pb.PeerAPI_RemoveIntention_FullMethodName,
+}
+
+func init() {
+	for _, method := range clusterMethods {
+		methodRoles[method] = []string{RoleAdmin}
+	}
+}
+
+func roleFromPb(role pb.OperatorRole) (string, bool) {
+	switch role {
+	case pb.OperatorRole_ADMIN:
+		return RoleAdmin, true
+	case pb.OperatorRole_NODE_MANAGER:
+		return RoleNodeManager, true
+	case pb.OperatorRole_READ_ONLY:
+		return RoleReadOnly, true
+	}
+	return "", false
+}
+
+func roleToPb(role string) pb.OperatorRole {
+	switch role {
+	case RoleAdmin:
+		return pb.OperatorRole_ADMIN
+	case RoleNodeManager:
+		return pb.OperatorRole_NODE_MANAGER
+	}
+	return pb.OperatorRole_READ_ONLY
+}
+
+// Caller of the peer API, either another registry or an operator
+type identity struct {
+	Registry bool
+	Operator *schemas.OperatorSchema
+}
+
+type identityKey struct{}
+
+func identityFromContext(ctx context.Context) *identity {
+	id, _ := ctx.Value(identityKey{}).(*identity)
+	return id
+}
+
+// Datacenter the caller is restricted to, empty if it isn't
+func scopedDatacenter(ctx context.Context) string {
+	id := identityFromContext(ctx)
+	if id == nil || id.Operator == nil {
+		return ""
+	}
+	return id.Operator.Datacenter
+}
+
+func (server *PeerAPIServer) getOperator(ctx context.Context, name string) (*schemas.OperatorSchema, error) {
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key: operatorKey(name),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching operator", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if len(res.Kvs) < 1 {
+		return nil, nil
+	}
+
+	var operator schemas.OperatorSchema
+	err = json.Unmarshal(res.Kvs[0].Value, &operator)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error decoding operator", "err", err)
+		return nil, utils.ServerError
+	}
+
+	return &operator, nil
+}
+
+// Registries present their peer certificate, operators the last certificate
+// issued to them
+func (server *PeerAPIServer) authenticate(ctx context.Context) (*identity, error) {
+	cert, err := utils.ExtractPeerCertificate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case slices.Equal(cert.Subject.OrganizationalUnit, []string{utils.ServerCertificateOU}):
+		return &identity{Registry: true}, nil
+	case slices.Equal(cert.Subject.OrganizationalUnit, []string{utils.OperatorCertificateOU}):
+		if !server.State.IssuedByOperatorCA(cert) {
+			slog.WarnContext(ctx, "Rejected operator certificate not issued by the operator CA", "operator", cert.Subject.CommonName)
+			return nil, utils.AuthFailure
+		}
+
+		operator, err := server.getOperator(ctx, cert.Subject.CommonName)
+		if err != nil {
+			return nil, err
+		}
+
+		if operator == nil || operator.Serial != utils.CertificateSerial(cert.SerialNumber) {
+			slog.WarnContext(ctx, "Rejected unknown operator certificate", "operator", cert.Subject.CommonName, "serial", utils.CertificateSerial(cert.SerialNumber))
+			return nil, utils.AuthFailure
+		}
+
+		return &identity{Operator: operator}, nil
+	}
+
+	return nil, utils.AuthFailure
+}
+
+func (server *PeerAPIServer) authorize(ctx context.Context, method string, req any) (context.Context, error) {
+	// Only the peer API is restricted, the health service is open to any
+	// authenticated client
+	if !strings.HasPrefix(method, "/PeerAPI/") {
+		return ctx, nil
+	}
+
+	id, err := server.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if id.Operator != nil {
+		ctx = logging.WithAttrs(ctx, "operator", id.Operator.Name)
+
+		err := authorizeOperator(ctx, id.Operator, method, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return context.WithValue(ctx, identityKey{}, id), nil
+}
+
+// Check the role of an operator allows the method, and that operators
+// restricted to a datacenter only act on it
+func authorizeOperator(ctx context.Context, operator *schemas.OperatorSchema, method string, req any) error {
+	if !slices.Contains(methodRoles[method], operator.Role) ||
+		(operator.Datacenter != "" && slices.Contains(clusterMethods, method)) {
+		slog.WarnContext(ctx, "Denied operator request", "role", operator.Role)
+		return PermissionDeniedError
+	}
+
+	r, ok := req.(interface{ GetDatacenter() string })
+	if ok && operator.Datacenter != "" && r.GetDatacenter() != operator.Datacenter {
+		slog.WarnContext(ctx, "Denied operator request outside of its datacenter", "datacenter", r.GetDatacenter())
+		return PermissionDeniedError
+	}
+
+	return nil
+}
+
+func (server *PeerAPIServer) UnaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := server.authorize(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (server *PeerAPIServer) StreamAuthInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := server.authorize(stream.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: stream, ctx: ctx})
+}
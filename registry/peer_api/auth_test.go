@@ -0,0 +1,82 @@
+package peer_api
+
+import (
+	"context"
+	"testing"
+
+	"ssle/registry/schemas"
+	pb "ssle/services"
+)
+
+func TestMethodRoles(t *testing.T) {
+	tests := []struct {
+		method string
+		role   string
+		want   bool
+	}{
+		{method: pb.PeerAPI_ListNodes_FullMethodName, role: RoleReadOnly, want: true},
+		{method: pb.PeerAPI_ListNodes_FullMethodName, role: RoleNodeManager, want: true},
+		{method: pb.PeerAPI_AddNode_FullMethodName, role: RoleReadOnly, want: false},
+		{method: pb.PeerAPI_AddNode_FullMethodName, role: RoleNodeManager, want: true},
+		{method: pb.PeerAPI_CreateOperator_FullMethodName, role: RoleNodeManager, want: false},
+		{method: pb.PeerAPI_CreateOperator_FullMethodName, role: RoleAdmin, want: true},
+		{method: pb.PeerAPI_RotateCA_FullMethodName, role: RoleNodeManager, want: false},
+		{method: pb.PeerAPI_RotateCA_FullMethodName, role: RoleAdmin, want: true},
+		// Methods only meant for registries
+		{method: pb.PeerAPI_GetCAToken_FullMethodName, role: RoleAdmin, want: false},
+		{method: "/PeerAPI/Unknown", role: RoleAdmin, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method+"/"+test.role, func(t *testing.T) {
+			operator := &schemas.OperatorSchema{Name: "op", Role: test.role}
+
+			err := authorizeOperator(context.Background(), operator, test.method, nil)
+			if (err == nil) != test.want {
+				t.Fatalf("expected allowed %t, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestDatacenterScope(t *testing.T) {
+	dc1 := "dc1"
+	dc2 := "dc2"
+
+	tests := []struct {
+		name       string
+		datacenter string
+		method     string
+		req        any
+		want       bool
+	}{
+		{name: "unscoped other datacenter", datacenter: "", method: pb.PeerAPI_AddNode_FullMethodName, req: &pb.AddNodeRequest{Datacenter: &dc2}, want: true},
+		{name: "scoped own datacenter", datacenter: dc1, method: pb.PeerAPI_AddNode_FullMethodName, req: &pb.AddNodeRequest{Datacenter: &dc1}, want: true},
+		{name: "scoped other datacenter", datacenter: dc1, method: pb.PeerAPI_AddNode_FullMethodName, req: &pb.AddNodeRequest{Datacenter: &dc2}, want: false},
+		{name: "scoped missing datacenter", datacenter: dc1, method: pb.PeerAPI_ListNodes_FullMethodName, req: &pb.ListNodesRequest{}, want: false},
+		{name: "scoped without datacenter field", datacenter: dc1, method: pb.PeerAPI_GetPeers_FullMethodName, req: &pb.GetPeersRequest{}, want: true},
+		{name: "unscoped cluster method", datacenter: "", method: pb.PeerAPI_RotateCA_FullMethodName, req: nil, want: true},
+		{name: "scoped cluster method", datacenter: dc1, method: pb.PeerAPI_RotateCA_FullMethodName, req: nil, want: false},
+		{name: "scoped snapshot", datacenter: dc1, method: pb.PeerAPI_Snapshot_FullMethodName, req: nil, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operator := &schemas.OperatorSchema{Name: "op", Role: RoleAdmin, Datacenter: test.datacenter}
+
+			err := authorizeOperator(context.Background(), operator, test.method, test.req)
+			if (err == nil) != test.want {
+				t.Fatalf("expected allowed %t, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestClusterMethodsAdminOnly(t *testing.T) {
+	for _, method := range clusterMethods {
+		roles := methodRoles[method]
+		if len(roles) != 1 || roles[0] != RoleAdmin {
+			t.Errorf("%s: expected only admins, got %v", method, roles)
+		}
+	}
+}
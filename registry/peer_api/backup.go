@@ -0,0 +1,84 @@
+package peer_api
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+const snapshotChunkSize = 32 * 1024
+
+// Stream a consistent snapshot of the etcd database, preceded by the tokens
+// and CA state needed to derive the registry CAs again. The active generation
+// may be derived from a rotated token this registry didn't switch to yet. The sha256 of the
+// database is appended like etcd does so it can be verified on restore.
+func (server *PeerAPIServer) Snapshot(req *pb.SnapshotRequest, stream grpc.ServerStreamingServer[pb.SnapshotResponse]) error {
+	caState, err := json.Marshal(server.State.CAState())
+	if err != nil {
+		slog.ErrorContext(stream.Context(), "Error encoding CA state", "err", err)
+		return utils.ServerError
+	}
+
+	caTokens, err := server.State.EncodedCATokens()
+	if err != nil {
+		slog.ErrorContext(stream.Context(), "Error encoding CA generation tokens", "err", err)
+		return utils.ServerError
+	}
+
+	snapshot := server.EtcdServer.Backend().Snapshot()
+	defer snapshot.Close()
+
+	token := server.State.EncodedToken()
+	size := uint64(snapshot.Size()) + sha256.Size
+
+	err = stream.Send(&pb.SnapshotResponse{
+		Metadata: &pb.SnapshotMetadata{
+			Token:    &token,
+			CaState:  caState,
+			Size:     &size,
+			CaTokens: caTokens,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		_, err := snapshot.WriteTo(pw)
+		pw.CloseWithError(err)
+	}()
+
+	hash := sha256.New()
+	for {
+		buf := make([]byte, snapshotChunkSize)
+		n, err := io.ReadFull(pr, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			slog.ErrorContext(stream.Context(), "Error reading etcd snapshot", "err", err)
+			return utils.ServerError
+		}
+
+		if n == 0 {
+			break
+		}
+
+		hash.Write(buf[:n])
+		err = stream.Send(&pb.SnapshotResponse{Data: buf[:n]})
+		if err != nil {
+			return err
+		}
+	}
+
+	slog.InfoContext(stream.Context(), "Sent etcd snapshot", "size", size)
+
+	return stream.Send(&pb.SnapshotResponse{Data: hash.Sum(nil)})
+}
@@ -0,0 +1,380 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"ssle/registry/config"
+	"ssle/registry/schemas"
+	"ssle/registry/state"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+// Generations trusted by the etcd of each registry, by registry name
+func (server *PeerAPIServer) getCATrust(ctx context.Context) (map[string][]int, error) {
+	prefix := fmt.Appendf(nil, "%s/", utils.CATrustNamespace)
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching CA trust", "err", err)
+		return nil, utils.ServerError
+	}
+
+	trust := map[string][]int{}
+	for _, kv := range res.Kvs {
+		var generations []int
+		err = json.Unmarshal(kv.Value, &generations)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding CA trust", "err", err)
+			return nil, utils.ServerError
+		}
+		trust[strings.TrimPrefix(string(kv.Key), string(prefix))] = generations
+	}
+
+	return trust, nil
+}
+
+func (server *PeerAPIServer) caGenerationsToPb(ctx context.Context, caState *schemas.CAStateSchema) ([]*pb.CAGeneration, error) {
+	trust, err := server.getCATrust(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	generations := make([]*pb.CAGeneration, len(caState.Generations))
+	for i, generation := range caState.Generations {
+		trustedBy := []string{}
+		for name, trusted := range trust {
+			if slices.Contains(trusted, generation.Generation) {
+				trustedBy = append(trustedBy, name)
+			}
+		}
+		slices.Sort(trustedBy)
+
+		generations[i] = &pb.CAGeneration{
+			Generation: proto.Uint32(uint32(generation.Generation)),
+			Start:      proto.Int64(generation.Start.Unix()),
+			Active:     proto.Bool(generation.Generation == caState.Active),
+			TrustedBy:  trustedBy,
+		}
+	}
+
+	return generations, nil
+}
+
+// The CA state stored in the cluster and its revision, or the local one if
+// it was not stored yet
+func (server *PeerAPIServer) getCAState(ctx context.Context) (*schemas.CAStateSchema, int64, error) {
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{Key: []byte(utils.CAStateKey)})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching CA state", "err", err)
+		return nil, 0, utils.ServerError
+	}
+
+	if len(res.Kvs) < 1 {
+		caState := server.State.CAState()
+		return &caState, 0, nil
+	}
+
+	var caState schemas.CAStateSchema
+	err = json.Unmarshal(res.Kvs[0].Value, &caState)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error decoding CA state", "err", err)
+		return nil, 0, utils.ServerError
+	}
+
+	return &caState, res.Kvs[0].ModRevision, nil
+}
+
+func (server *PeerAPIServer) GetCAState(ctx context.Context, req *pb.GetCAStateRequest) (*pb.GetCAStateResponse, error) {
+	caState, _, err := server.getCAState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	generations, err := server.caGenerationsToPb(ctx, caState)
+	if err != nil {
+		return nil, err
+	}
+
+	joinToken := server.State.JoinToken(*caState)
+
+	return &pb.GetCAStateResponse{Generations: generations, JoinToken: &joinToken}, nil
+}
+
+// Number of unexpired and unrevoked node certificates not issued by the
+// active CA generation
+func (server *PeerAPIServer) countInactiveCANodeCertificates(ctx context.Context, active int) (int, error) {
+	prefix := fmt.Appendf(nil, "%s/", utils.NodeCertificatesNamespace)
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching node certificates", "err", err)
+		return 0, utils.ServerError
+	}
+
+	count := 0
+	for _, kv := range res.Kvs {
+		var record schemas.CertificateSchema
+		err = json.Unmarshal(kv.Value, &record)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding node certificate", "err", err)
+			return 0, utils.ServerError
+		}
+
+		if !record.Revoked && record.CAGeneration != active && time.Now().Before(record.NotAfter) {
+			count += 1
+		}
+	}
+
+	return count, nil
+}
+
+// Store the CA state unless it was changed since it was read at modRevision
+func (server *PeerAPIServer) putCAState(ctx context.Context, caState *schemas.CAStateSchema, modRevision int64) error {
+	serialized, err := json.Marshal(caState)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding CA state", "err", err)
+		return utils.ServerError
+	}
+
+	key := []byte(utils.CAStateKey)
+	compare := &etcdserverpb.Compare{
+		Result:      etcdserverpb.Compare_EQUAL,
+		Target:      etcdserverpb.Compare_MOD,
+		Key:         key,
+		TargetUnion: &etcdserverpb.Compare_ModRevision{ModRevision: modRevision},
+	}
+
+	res, err := server.EtcdServer.Txn(ctx, &etcdserverpb.TxnRequest{
+		Compare: []*etcdserverpb.Compare{compare},
+		Success: []*etcdserverpb.RequestOp{{
+			Request: &etcdserverpb.RequestOp_RequestPut{
+				RequestPut: &etcdserverpb.PutRequest{Key: key, Value: serialized},
+			},
+		}},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing CA state", "err", err)
+		return utils.ServerError
+	}
+
+	if !res.Succeeded {
+		return status.Errorf(codes.Aborted, "CA state changed while updating it")
+	}
+
+	return nil
+}
+
+// Move the CA rollover one phase forward:
+//   - INTRODUCE: add a new CA generation, trusted but not used for signing
+//   - ACTIVATE: sign new certificates with it once every registry trusts it
+//   - RETIRE: stop trusting the previous generations
+func (server *PeerAPIServer) RotateCA(ctx context.Context, req *pb.RotateCARequest) (*pb.RotateCAResponse, error) {
+	caState, modRevision, err := server.getCAState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newest := caState.Generations[len(caState.Generations)-1]
+
+	switch *req.Phase {
+	case pb.CARotationPhase_INTRODUCE:
+		if len(caState.Generations) > 1 {
+			return nil, CARotationInProgressError
+		}
+
+		caState.Generations = append(caState.Generations, schemas.CAGenerationSchema{
+			Generation: newest.Generation + 1,
+			Start:      time.Now(),
+		})
+	case pb.CARotationPhase_ACTIVATE:
+		if newest.Generation == caState.Active {
+			return nil, status.Errorf(codes.FailedPrecondition, "No CA generation was introduced")
+		}
+
+		// etcd only loads the trusted CAs on startup, so peers that haven't
+		// been restarted since the introduction would reject new certificates
+		trust, err := server.getCATrust(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		untrusting := []string{}
+		for _, member := range server.EtcdServer.Cluster().Members() {
+			if !slices.Contains(trust[member.Name], newest.Generation) {
+				untrusting = append(untrusting, member.Name)
+			}
+		}
+
+		if len(untrusting) > 0 {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"Registries %s must be restarted to trust CA generation %d",
+				strings.Join(untrusting, ", "),
+				newest.Generation,
+			)
+		}
+
+		caState.Active = newest.Generation
+	case pb.CARotationPhase_RETIRE:
+		if len(caState.Generations) == 1 {
+			return nil, status.Errorf(codes.FailedPrecondition, "No CA generation to retire")
+		}
+
+		if newest.Generation != caState.Active {
+			return nil, status.Errorf(codes.FailedPrecondition, "CA generation %d must be activated first", newest.Generation)
+		}
+
+		if !req.GetForce() {
+			count, err := server.countInactiveCANodeCertificates(ctx, caState.Active)
+			if err != nil {
+				return nil, err
+			}
+
+			if count > 0 {
+				return nil, status.Errorf(
+					codes.FailedPrecondition,
+					"%d node certificates were not renewed from CA generation %d yet",
+					count,
+					caState.Active,
+				)
+			}
+		}
+
+		caState.Generations = []schemas.CAGenerationSchema{newest}
+	}
+
+	err = server.putCAState(ctx, caState, modRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "CA rollover", "phase", req.Phase.String(), "active", caState.Active, "generations", caState.Generations)
+
+	generations, err := server.caGenerationsToPb(ctx, caState)
+	if err != nil {
+		return nil, err
+	}
+
+	joinToken := server.State.JoinToken(*caState)
+
+	return &pb.RotateCAResponse{Generations: generations, JoinToken: &joinToken}, nil
+}
+
+// Introduce a CA generation derived from a new cluster token, once it is
+// activated registries switch to the new token. Retiring the previous
+// generations, and restarting the registries so etcd reloads its trusted CAs,
+// prevents the old one from being used to join.
+func (server *PeerAPIServer) RotateToken(ctx context.Context, req *pb.RotateTokenRequest) (*pb.RotateTokenResponse, error) {
+	caState, modRevision, err := server.getCAState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(caState.Generations) > 1 {
+		return nil, CARotationInProgressError
+	}
+
+	// Only the certificates of the generations are shared through etcd, the
+	// new token is fetched by the other registries over the peer API
+	err = server.State.PinCAGenerations(caState)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error recording CA certificates", "err", err)
+		return nil, utils.ServerError
+	}
+
+	newest := caState.Generations[len(caState.Generations)-1]
+	generation, err := server.State.NewTokenGeneration(newest.Generation+1, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating CA generation", "err", err)
+		return nil, utils.ServerError
+	}
+	caState.Generations = append(caState.Generations, generation)
+
+	err = server.putCAState(ctx, caState, modRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Cluster token rotation introduced a new CA generation", "generation", newest.Generation+1)
+
+	generations, err := server.caGenerationsToPb(ctx, caState)
+	if err != nil {
+		return nil, err
+	}
+
+	joinToken := server.State.JoinToken(*caState)
+
+	return &pb.RotateTokenResponse{Generations: generations, JoinToken: &joinToken}, nil
+}
+
+// Share the token of a CA generation with another registry, only registries
+// may call it
+func (server *PeerAPIServer) GetCAToken(ctx context.Context, req *pb.GetCATokenRequest) (*pb.GetCATokenResponse, error) {
+	token, found := server.State.CAToken(int(*req.Generation))
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "Token of CA generation %d is not known", *req.Generation)
+	}
+
+	return &pb.GetCATokenResponse{Token: token}, nil
+}
+
+// Ask the other registries for the token of a CA generation derived from a
+// rotated cluster token
+func FetchCAToken(config *config.Config, state *state.State, etcd *etcdserver.EtcdServer, generation int) ([]byte, error) {
+	prefix := fmt.Appendf(nil, "%s/", utils.PeerApiNamespace)
+	res, err := etcd.Range(context.Background(), &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transportCred := credentials.NewTLS(clientTLSConfig(state))
+
+	err = fmt.Errorf("no other registry to fetch it from")
+	for _, kv := range res.Kvs {
+		if strings.TrimPrefix(string(kv.Key), string(prefix)) == config.Name {
+			continue
+		}
+
+		var conn *grpc.ClientConn
+		conn, err = grpc.NewClient(string(kv.Value), grpc.WithTransportCredentials(transportCred))
+		if err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), peerStatusTimeout)
+		var tokenRes *pb.GetCATokenResponse
+		tokenRes, err = pb.NewPeerAPIClient(conn).GetCAToken(ctx, &pb.GetCATokenRequest{
+			Generation: proto.Uint32(uint32(generation)),
+		})
+		cancel()
+		conn.Close()
+
+		if err == nil {
+			return tokenRes.Token, nil
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,112 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+var (
+	InvalidIntentionServiceError = status.Errorf(codes.InvalidArgument, "Intention source and destination must not be empty or contain a slash")
+	IntentionNotFoundError       = status.Errorf(codes.NotFound, "Intention not found")
+)
+
+func validIntentionService(service string) bool {
+	return service != "" && !strings.Contains(service, "/")
+}
+
+func intentionToPb(intention *schemas.IntentionSchema) *pb.Intention {
+	action := pb.IntentionAction_ALLOW
+	if intention.Action == utils.IntentionDeny {
+		action = pb.IntentionAction_DENY
+	}
+
+	res := &pb.Intention{
+		Source:      &intention.Source,
+		Destination: &intention.Destination,
+		Action:      &action,
+	}
+	if intention.Description != "" {
+		res.Description = &intention.Description
+	}
+	return res
+}
+
+// Create an intention or replace the one between the same services
+func (server *PeerAPIServer) SetIntention(ctx context.Context, req *pb.SetIntentionRequest) (*pb.SetIntentionResponse, error) {
+	if !validIntentionService(*req.Intention.Source) || !validIntentionService(*req.Intention.Destination) {
+		return nil, InvalidIntentionServiceError
+	}
+
+	intention := schemas.IntentionSchema{
+		Source:      *req.Intention.Source,
+		Destination: *req.Intention.Destination,
+		Action:      utils.IntentionAllow,
+		Description: req.Intention.GetDescription(),
+	}
+	if *req.Intention.Action == pb.IntentionAction_DENY {
+		intention.Action = utils.IntentionDeny
+	}
+
+	serializedIntention, err := json.Marshal(intention)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding intention", "err", err)
+		return nil, utils.ServerError
+	}
+
+	_, err = server.EtcdServer.Put(ctx, &etcdserverpb.PutRequest{
+		Key:   utils.IntentionKey(intention.Source, intention.Destination),
+		Value: serializedIntention,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing intention", "err", err)
+		return nil, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Set intention", "source", intention.Source, "destination", intention.Destination, "action", intention.Action)
+
+	return &pb.SetIntentionResponse{}, nil
+}
+
+func (server *PeerAPIServer) ListIntentions(ctx context.Context, req *pb.ListIntentionsRequest) (*pb.ListIntentionsResponse, error) {
+	intentions, err := utils.GetIntentions(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.Intention, len(intentions))
+	for i := range intentions {
+		res[i] = intentionToPb(&intentions[i])
+	}
+
+	return &pb.ListIntentionsResponse{Intentions: res}, nil
+}
+
+func (server *PeerAPIServer) RemoveIntention(ctx context.Context, req *pb.RemoveIntentionRequest) (*pb.RemoveIntentionResponse, error) {
+	if !validIntentionService(*req.Source) || !validIntentionService(*req.Destination) {
+		return nil, InvalidIntentionServiceError
+	}
+
+	res, err := server.EtcdServer.DeleteRange(ctx, &etcdserverpb.DeleteRangeRequest{Key: utils.IntentionKey(*req.Source, *req.Destination)})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error deleting intention", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if res.Deleted == 0 {
+		return nil, IntentionNotFoundError
+	}
+
+	slog.InfoContext(ctx, "Removed intention", "source", *req.Source, "destination", *req.Destination)
+
+	return &pb.RemoveIntentionResponse{}, nil
+}
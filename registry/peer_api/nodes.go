@@ -0,0 +1,273 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+func nodeToPb(node *schemas.NodeSchema) *pb.Node {
+	nodeType := pb.NodeType_AGENT
+	if node.Type == utils.ObserverCertificateOU {
+		nodeType = pb.NodeType_OBSERVER
+	}
+
+	return &pb.Node{
+		Name:       &node.Name,
+		Datacenter: &node.Datacenter,
+		Location:   &node.Location,
+		NodeType:   &nodeType,
+		Disabled:   &node.Disabled,
+	}
+}
+
+func (server *PeerAPIServer) getNode(ctx context.Context, datacenter string, name string) (*schemas.NodeSchema, error) {
+	node, err := utils.GetNodeSchema(ctx, server.EtcdServer, datacenter, name)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error getting node schema", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if node == nil {
+		return nil, NodeNotFoundError
+	}
+
+	return node, nil
+}
+
+func (server *PeerAPIServer) ListNodes(ctx context.Context, req *pb.ListNodesRequest) (*pb.ListNodesResponse, error) {
+	prefix := fmt.Appendf(nil, "%s/", utils.NodesNamespace)
+	if req.Datacenter != nil {
+		prefix = fmt.Appendf(prefix, "%s/", *req.Datacenter)
+	}
+
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching nodes", "err", err)
+		return nil, utils.ServerError
+	}
+
+	nodes := make([]*pb.Node, len(res.Kvs))
+	for i, kv := range res.Kvs {
+		var node schemas.NodeSchema
+		err = json.Unmarshal(kv.Value, &node)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding node", "err", err)
+			return nil, utils.ServerError
+		}
+		nodes[i] = nodeToPb(&node)
+	}
+
+	return &pb.ListNodesResponse{Nodes: nodes}, nil
+}
+
+func (server *PeerAPIServer) GetNode(ctx context.Context, req *pb.GetNodeRequest) (*pb.GetNodeResponse, error) {
+	node, err := server.getNode(ctx, *req.Datacenter, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Appendf(nil, "%s/%s/%s/", utils.DCServicesNamespace, node.Datacenter, node.Name)
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching node services", "err", err)
+		return nil, utils.ServerError
+	}
+
+	svcs := make([]*pb.ServiceSpec, len(res.Kvs))
+	for i, kv := range res.Kvs {
+		err = json.Unmarshal(kv.Value, &svcs[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding node service", "err", err)
+			return nil, utils.ServerError
+		}
+	}
+
+	records, err := utils.GetNodeCertificates(ctx, server.EtcdServer, node.Datacenter, node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	certs := make([]*pb.NodeCertificate, len(records))
+	for i, record := range records {
+		certs[i] = &pb.NodeCertificate{
+			Serial:    &record.Serial,
+			NotBefore: proto.Int64(record.NotBefore.Unix()),
+			NotAfter:  proto.Int64(record.NotAfter.Unix()),
+			Revoked:   &record.Revoked,
+		}
+	}
+
+	return &pb.GetNodeResponse{Node: nodeToPb(node), Services: svcs, Certificates: certs}, nil
+}
+
+func (server *PeerAPIServer) RemoveNode(ctx context.Context, req *pb.RemoveNodeRequest) (*pb.RemoveNodeResponse, error) {
+	node, err := server.getNode(ctx, *req.Datacenter, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lease key is deleted with the node, so find the lease beforehand
+	leaseId, err := utils.FindNodeLease(ctx, server.EtcdServer, node.Datacenter, node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := utils.DeleteNodeServicesOps(ctx, server.EtcdServer, node)
+	if err != nil {
+		return nil, err
+	}
+
+	// A node added again with the same name must not accept the old
+	// credentials
+	_, revokeOps, err := utils.RevokeNodeCertificatesOps(ctx, server.EtcdServer, node.Datacenter, node.Name, "")
+	if err != nil {
+		return nil, err
+	}
+	ops = append(ops, revokeOps...)
+
+	nodeKey := fmt.Appendf(nil, "%s/%s/%s", utils.NodesNamespace, node.Datacenter, node.Name)
+	ops = append(ops, &etcdserverpb.RequestOp{
+		Request: &etcdserverpb.RequestOp_RequestDeleteRange{
+			RequestDeleteRange: &etcdserverpb.DeleteRangeRequest{Key: nodeKey},
+		},
+	})
+
+	// Remove everything at once so the node can't be left half removed, as
+	// long as it wasn't removed concurrently
+	txnRes, err := server.EtcdServer.Txn(ctx, &etcdserverpb.TxnRequest{
+		Compare: []*etcdserverpb.Compare{{
+			Result: etcdserverpb.Compare_GREATER,
+			Target: etcdserverpb.Compare_VERSION,
+			Key:    nodeKey,
+			TargetUnion: &etcdserverpb.Compare_Version{
+				Version: 0,
+			},
+		}},
+		Success: ops,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error removing node", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if !txnRes.Succeeded {
+		return nil, NodeNotFoundError
+	}
+
+	// Services registered since are attached to the lease, which can only be
+	// revoked outside of the transaction
+	err = utils.RevokeLease(ctx, server.EtcdServer, leaseId)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Removed node", "datacenter", node.Datacenter, "node", node.Name)
+
+	return &pb.RemoveNodeResponse{}, nil
+}
+
+func (server *PeerAPIServer) SetNodeDisabled(ctx context.Context, req *pb.SetNodeDisabledRequest) (*pb.SetNodeDisabledResponse, error) {
+	nodeKey := fmt.Appendf(nil, "%s/%s/%s", utils.NodesNamespace, *req.Datacenter, *req.Name)
+
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{Key: nodeKey})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching node", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if len(res.Kvs) < 1 {
+		return nil, NodeNotFoundError
+	}
+
+	var node schemas.NodeSchema
+	err = json.Unmarshal(res.Kvs[0].Value, &node)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error decoding node", "err", err)
+		return nil, utils.ServerError
+	}
+
+	node.Disabled = *req.Disabled
+
+	serializedNode, err := json.Marshal(node)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding node", "err", err)
+		return nil, utils.ServerError
+	}
+
+	txnRes, err := server.EtcdServer.Txn(ctx, &etcdserverpb.TxnRequest{
+		Compare: []*etcdserverpb.Compare{{
+			Result: etcdserverpb.Compare_EQUAL,
+			Target: etcdserverpb.Compare_MOD,
+			Key:    nodeKey,
+			TargetUnion: &etcdserverpb.Compare_ModRevision{
+				ModRevision: res.Kvs[0].ModRevision,
+			},
+		}},
+		Success: []*etcdserverpb.RequestOp{{
+			Request: &etcdserverpb.RequestOp_RequestPut{
+				RequestPut: &etcdserverpb.PutRequest{
+					Key:   nodeKey,
+					Value: serializedNode,
+				},
+			},
+		}},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing node", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if !txnRes.Succeeded {
+		return nil, status.Errorf(codes.Aborted, "Node changed while updating it")
+	}
+
+	// Disabled nodes can no longer heartbeat, so drop their services now
+	// instead of waiting for the lease to expire
+	if node.Disabled {
+		err = utils.DeleteNodeServices(ctx, server.EtcdServer, &node)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pb.SetNodeDisabledResponse{}, nil
+}
+
+func (server *PeerAPIServer) RevokeNodeCertificates(ctx context.Context, req *pb.RevokeNodeCertificatesRequest) (*pb.RevokeNodeCertificatesResponse, error) {
+	node, err := server.getNode(ctx, *req.Datacenter, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	serials, err := utils.RevokeNodeCertificates(ctx, server.EtcdServer, node.Datacenter, node.Name, req.GetSerial())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Serial != nil && len(serials) == 0 {
+		return nil, CertificateNotFoundError
+	}
+
+	for _, serial := range serials {
+		slog.InfoContext(ctx, "Revoked node certificate", "serial", serial, "datacenter", node.Datacenter, "node", node.Name)
+	}
+
+	return &pb.RevokeNodeCertificatesResponse{Serials: serials}, nil
+}
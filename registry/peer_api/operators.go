@@ -0,0 +1,165 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+var (
+	InvalidOperatorNameError = status.Errorf(codes.InvalidArgument, "Operator name must not be empty or contain a slash")
+	InvalidOperatorRoleError = status.Errorf(codes.InvalidArgument, "Invalid operator role")
+	InvalidCSRError          = status.Errorf(codes.InvalidArgument, "Invalid certificate signing request")
+	OperatorNotFoundError    = status.Errorf(codes.NotFound, "Operator not found")
+)
+
+func operatorKey(name string) []byte {
+	return fmt.Appendf(nil, "%s/%s", utils.OperatorsNamespace, name)
+}
+
+func operatorToPb(operator *schemas.OperatorSchema) *pb.Operator {
+	res := &pb.Operator{
+		Name:     &operator.Name,
+		Role:     roleToPb(operator.Role).Enum(),
+		Serial:   &operator.Serial,
+		NotAfter: proto.Int64(operator.NotAfter.Unix()),
+	}
+	if operator.Datacenter != "" {
+		res.Datacenter = &operator.Datacenter
+	}
+	return res
+}
+
+// Issue a certificate to an operator. Creating an existing operator again
+// replaces its role and makes its previous certificate unusable.
+func (server *PeerAPIServer) CreateOperator(ctx context.Context, req *pb.CreateOperatorRequest) (*pb.CreateOperatorResponse, error) {
+	if *req.Name == "" || strings.Contains(*req.Name, "/") {
+		return nil, InvalidOperatorNameError
+	}
+
+	role, ok := roleFromPb(*req.Role)
+	if !ok {
+		return nil, InvalidOperatorRoleError
+	}
+
+	// Operators restricted to a datacenter can only manage the operators of
+	// the same datacenter
+	scope := scopedDatacenter(ctx)
+	if scope != "" && req.GetDatacenter() != scope {
+		return nil, PermissionDeniedError
+	}
+
+	existing, err := server.getOperator(ctx, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil && scope != "" && existing.Datacenter != scope {
+		return nil, PermissionDeniedError
+	}
+
+	csr, err := utils.ParseCSR(req.Csr)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error parsing operator CSR", "err", err)
+		return nil, InvalidCSRError
+	}
+
+	crt, cert, err := utils.SignOperatorCrt(server.State, *req.Name, csr.PublicKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error signing operator certificate", "err", err)
+		return nil, utils.ServerError
+	}
+
+	operator := schemas.OperatorSchema{
+		Name:       *req.Name,
+		Role:       role,
+		Datacenter: req.GetDatacenter(),
+		Serial:     utils.CertificateSerial(cert.SerialNumber),
+		NotAfter:   cert.NotAfter,
+	}
+
+	serializedOperator, err := json.Marshal(operator)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding operator", "err", err)
+		return nil, utils.ServerError
+	}
+
+	_, err = server.EtcdServer.Put(ctx, &etcdserverpb.PutRequest{
+		Key:   operatorKey(operator.Name),
+		Value: serializedOperator,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing operator", "err", err)
+		return nil, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Issued operator certificate", "name", operator.Name, "role", operator.Role, "datacenter", operator.Datacenter, "serial", operator.Serial)
+
+	return &pb.CreateOperatorResponse{Certificate: crt}, nil
+}
+
+func (server *PeerAPIServer) ListOperators(ctx context.Context, req *pb.ListOperatorsRequest) (*pb.ListOperatorsResponse, error) {
+	prefix := fmt.Appendf(nil, "%s/", utils.OperatorsNamespace)
+	res, err := server.EtcdServer.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: utils.PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching operators", "err", err)
+		return nil, utils.ServerError
+	}
+
+	scope := scopedDatacenter(ctx)
+	operators := []*pb.Operator{}
+	for _, kv := range res.Kvs {
+		var operator schemas.OperatorSchema
+		err = json.Unmarshal(kv.Value, &operator)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding operator", "err", err)
+			return nil, utils.ServerError
+		}
+
+		if scope != "" && operator.Datacenter != scope {
+			continue
+		}
+		operators = append(operators, operatorToPb(&operator))
+	}
+
+	return &pb.ListOperatorsResponse{Operators: operators}, nil
+}
+
+func (server *PeerAPIServer) RemoveOperator(ctx context.Context, req *pb.RemoveOperatorRequest) (*pb.RemoveOperatorResponse, error) {
+	operator, err := server.getOperator(ctx, *req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator == nil {
+		return nil, OperatorNotFoundError
+	}
+
+	if scope := scopedDatacenter(ctx); scope != "" && operator.Datacenter != scope {
+		return nil, PermissionDeniedError
+	}
+
+	_, err = server.EtcdServer.DeleteRange(ctx, &etcdserverpb.DeleteRangeRequest{Key: operatorKey(operator.Name)})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error deleting operator", "err", err)
+		return nil, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Removed operator", "name", operator.Name)
+
+	return &pb.RemoveOperatorResponse{}, nil
+}
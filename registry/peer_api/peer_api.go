@@ -3,13 +3,11 @@ package peer_api
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/url"
-	"strconv"
 	"time"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -18,20 +16,32 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 
+	"ssle/registry/audit"
 	"ssle/registry/config"
+	"ssle/registry/metrics"
 	"ssle/registry/schemas"
 	"ssle/registry/state"
 	"ssle/registry/utils"
 	pb "ssle/services"
+	"ssle/services/logging"
 )
 
 var (
 	InvalidAdvertiseUrlError = status.Errorf(codes.InvalidArgument, "Advertise url is not a valid url")
 	MissingAdvertiseUrlError = status.Errorf(codes.InvalidArgument, "At least one advertised URL must be set")
 
+	PeerNotFoundError = status.Errorf(codes.NotFound, "Peer not found")
+
 	AgentAlreadyExistsError = status.Errorf(codes.AlreadyExists, "Agent already exists")
+	NodeNotFoundError       = status.Errorf(codes.NotFound, "Node not found")
+	NodeDisabledError       = status.Errorf(codes.FailedPrecondition, "Node is disabled")
+
+	CertificateNotFoundError = status.Errorf(codes.NotFound, "Certificate not found or already revoked")
+
+	CARotationInProgressError = status.Errorf(codes.FailedPrecondition, "A CA rollover is already in progress")
 )
 
 type PeerAPIServer struct {
@@ -44,13 +54,7 @@ func (server *PeerAPIServer) GetPeers(ctx context.Context, req *pb.GetPeersReque
 	members := server.EtcdServer.Cluster().Members()
 	peers := make([]*pb.Peer, len(members))
 	for i, m := range members {
-		id := strconv.FormatUint(uint64(m.ID), 10)
-		peers[i] = &pb.Peer{
-			Id:         &id,
-			Name:       &m.Name,
-			PeerUrls:   m.RaftAttributes.PeerURLs,
-			ClientUrls: m.Attributes.ClientURLs,
-		}
+		peers[i] = memberToPb(m)
 	}
 	return &pb.GetPeersResponse{Peers: peers}, nil
 }
@@ -77,12 +81,14 @@ func (server *PeerAPIServer) AddSelfPeer(ctx context.Context, req *pb.AddSelfPee
 		urls[i] = *u
 	}
 
+	// New registries join as learners, they promote themselves once they
+	// caught up with the leader
 	_, err = server.EtcdServer.AddMember(
 		ctx,
-		*membership.NewMember(peerName, urls, "", &now),
+		*membership.NewMemberAsLearner(peerName, urls, "", &now),
 	)
 	if err != nil {
-		log.Printf("Error: Failed to add peer: %v", err)
+		slog.ErrorContext(ctx, "Failed to add peer", "err", err)
 		return nil, utils.ServerError
 	}
 
@@ -109,7 +115,7 @@ func (server *PeerAPIServer) AddNode(ctx context.Context, req *pb.AddNodeRequest
 
 	serializedNode, err := json.Marshal(node)
 	if err != nil {
-		log.Print(err.Error())
+		slog.ErrorContext(ctx, "Error encoding node", "err", err)
 		return nil, utils.ServerError
 	}
 
@@ -132,7 +138,7 @@ func (server *PeerAPIServer) AddNode(ctx context.Context, req *pb.AddNodeRequest
 		}},
 	})
 	if err != nil {
-		log.Print(err.Error())
+		slog.ErrorContext(ctx, "Error storing node", "err", err)
 		return nil, utils.ServerError
 	}
 
@@ -140,12 +146,31 @@ func (server *PeerAPIServer) AddNode(ctx context.Context, req *pb.AddNodeRequest
 		return nil, AgentAlreadyExistsError
 	}
 
-	crt, key := utils.CreateNodeCrt(
+	if req.GetBootstrap() {
+		ttl := utils.BootstrapTokenTTL
+		if req.TokenTtl != nil {
+			ttl = time.Duration(*req.TokenTtl) * time.Second
+		}
+
+		token, err := utils.CreateBootstrapToken(ctx, server.EtcdServer, &node, ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.AddNodeResponse{Token: &token}, nil
+	}
+
+	crt, key, err := utils.CreateNodeCrt(
+		ctx,
 		server.State,
+		server.EtcdServer,
 		*req.Datacenter,
 		*req.Name,
 		implicit,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	return &pb.AddNodeResponse{
 		Certificate: crt,
@@ -154,18 +179,26 @@ func (server *PeerAPIServer) AddNode(ctx context.Context, req *pb.AddNodeRequest
 }
 
 func (server *PeerAPIServer) GetNodeCredentials(ctx context.Context, req *pb.GetNodeCredentialsRequest) (*pb.GetNodeCredentialsResponse, error) {
-	node, err := utils.GetNodeSchema(ctx, server.EtcdServer, *req.Datacenter, *req.Name)
+	node, err := server.getNode(ctx, *req.Datacenter, *req.Name)
 	if err != nil {
-		log.Print(err.Error())
-		return nil, utils.ServerError
+		return nil, err
+	}
+
+	if node.Disabled {
+		return nil, NodeDisabledError
 	}
 
-	crt, key := utils.CreateNodeCrt(
+	crt, key, err := utils.CreateNodeCrt(
+		ctx,
 		server.State,
+		server.EtcdServer,
 		node.Datacenter,
 		node.Name,
 		node.Type,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	return &pb.GetNodeCredentialsResponse{
 		Certificate: crt,
@@ -173,55 +206,62 @@ func (server *PeerAPIServer) GetNodeCredentials(ctx context.Context, req *pb.Get
 	}, nil
 }
 
-func NewPeerApiClient(clusterUrl string, state *state.State) pb.PeerAPIClient {
-	caCertPool := x509.NewCertPool()
-	caCertPool.AddCert(state.CA.Leaf)
+// TLS configuration to reach other registries with the peer certificate
+func clientTLSConfig(state *state.State) *tls.Config {
+	return &tls.Config{
+		ServerName: "registry.cluster.internal",
+		RootCAs:    state.CertPool(),
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return state.ServerCertificate(), nil
+		},
+	}
+}
 
-	transportCred := credentials.NewTLS(&tls.Config{
-		ServerName:   "registry.cluster.internal",
-		RootCAs:      caCertPool,
-		Certificates: []tls.Certificate{state.ServerKeyPair},
-	})
+func NewPeerApiClient(clusterUrl string, state *state.State) pb.PeerAPIClient {
+	transportCred := credentials.NewTLS(clientTLSConfig(state))
 
 	conn, err := grpc.NewClient(clusterUrl, grpc.WithTransportCredentials(transportCred))
 	if err != nil {
-		log.Fatalf("Failed to create grpc client: %v", err)
+		logging.Fatal("Failed to create grpc client", "err", err)
 	}
 	return pb.NewPeerAPIClient(conn)
 }
 
-func StartApiServer(config *config.Config, state *state.State, etcdServer *etcdserver.EtcdServer) {
+func StartApiServer(config *config.Config, state *state.State, etcdServer *etcdserver.EtcdServer, auditor *audit.Auditor, healthServer grpc_health_v1.HealthServer) {
 	peerApiServer := PeerAPIServer{State: state, EtcdServer: etcdServer}
 
-	cert, err := tls.LoadX509KeyPair(state.ServerCrtFile, state.ServerKeyFile)
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	listenAddr := config.PeerAPIListenHost()
 	lis, err := net.Listen("tcp", listenAddr)
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		logging.Fatal("Failed to listen", "err", err)
 	}
 
-	caCertPool := x509.NewCertPool()
-	caCertPool.AddCert(state.CA.Leaf)
-
-	transportCred := credentials.NewTLS(&tls.Config{
-		Certificates: []tls.Certificate{cert},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    caCertPool,
-	})
-
-	grpcServer := grpc.NewServer(grpc.Creds(transportCred))
+	transportCred := credentials.NewTLS(state.ServerTLSConfig(tls.RequireAndVerifyClientCert, state.PeerAPICertPool))
+
+	grpcServer := grpc.NewServer(
+		grpc.Creds(transportCred),
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor,
+			metrics.UnaryServerInterceptor,
+			auditor.UnaryServerInterceptor,
+			peerApiServer.UnaryAuthInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor,
+			metrics.StreamServerInterceptor,
+			auditor.StreamServerInterceptor,
+			peerApiServer.StreamAuthInterceptor,
+		),
+	)
 	pb.RegisterPeerAPIServer(grpcServer, &peerApiServer)
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
 	go func() {
 		err = grpcServer.Serve(lis)
 		if err != nil {
-			log.Fatalf("Failed to start peer API: %v", err)
+			logging.Fatal("Failed to start peer API", "err", err)
 		}
 	}()
 
-	log.Printf("Started peer api at https://%v", listenAddr)
+	slog.Info("Started peer api", "addr", "https://"+listenAddr)
 }
@@ -0,0 +1,185 @@
+package peer_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	etcdErrors "go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+const peerStatusTimeout = 5 * time.Second
+
+func memberToPb(member *membership.Member) *pb.Peer {
+	id := strconv.FormatUint(uint64(member.ID), 10)
+	return &pb.Peer{
+		Id:         &id,
+		Name:       &member.Name,
+		PeerUrls:   member.PeerURLs,
+		ClientUrls: member.ClientURLs,
+		IsLearner:  &member.IsLearner,
+	}
+}
+
+// Find a cluster member from its id or name
+func (server *PeerAPIServer) getMember(peer string) (*membership.Member, error) {
+	for _, member := range server.EtcdServer.Cluster().Members() {
+		if member.Name == peer || strconv.FormatUint(uint64(member.ID), 10) == peer {
+			return member, nil
+		}
+	}
+
+	return nil, PeerNotFoundError
+}
+
+// Membership changes etcd refuses because of the cluster state
+func isMembershipPreconditionError(err error) bool {
+	return errors.Is(err, etcdErrors.ErrNotEnoughStartedMembers) ||
+		errors.Is(err, etcdErrors.ErrUnhealthy) ||
+		errors.Is(err, etcdErrors.ErrLearnerNotReady) ||
+		errors.Is(err, membership.ErrMemberNotLearner) ||
+		errors.Is(err, membership.ErrIDNotFound) ||
+		errors.Is(err, membership.ErrIDRemoved)
+}
+
+func (server *PeerAPIServer) RemovePeer(ctx context.Context, req *pb.RemovePeerRequest) (*pb.RemovePeerResponse, error) {
+	member, err := server.getMember(*req.Peer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The registry would stop before answering
+	if member.ID == server.EtcdServer.MemberID() {
+		return nil, status.Errorf(codes.FailedPrecondition, "A registry cannot remove itself, use another registry")
+	}
+
+	_, err = server.EtcdServer.RemoveMember(ctx, uint64(member.ID))
+	if err != nil {
+		if isMembershipPreconditionError(err) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Failed to remove peer: %v", err)
+		}
+
+		slog.ErrorContext(ctx, "Error removing peer", "peer", member.Name, "err", err)
+		return nil, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Removed peer", "peer", member.Name, "id", member.ID.String())
+
+	// Forget the API addresses, CA trust and defragmentation time of the
+	// removed registry
+	for _, namespace := range []string{utils.PeerAgentApiNamespace, utils.PeerApiNamespace, utils.CATrustNamespace, utils.DefragNamespace} {
+		_, err = server.EtcdServer.DeleteRange(ctx, &etcdserverpb.DeleteRangeRequest{
+			Key: fmt.Appendf(nil, "%s/%s", namespace, member.Name),
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "Error cleaning up removed peer", "peer", member.Name, "err", err)
+			return nil, utils.ServerError
+		}
+	}
+
+	return &pb.RemovePeerResponse{}, nil
+}
+
+func (server *PeerAPIServer) PromotePeer(ctx context.Context, req *pb.PromotePeerRequest) (*pb.PromotePeerResponse, error) {
+	member, err := server.getMember(*req.Peer)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.EtcdServer.PromoteMember(ctx, uint64(member.ID))
+	if err != nil {
+		if isMembershipPreconditionError(err) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Failed to promote peer: %v", err)
+		}
+
+		slog.ErrorContext(ctx, "Error promoting peer", "peer", member.Name, "err", err)
+		return nil, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Promoted peer", "peer", member.Name, "id", member.ID.String())
+
+	return &pb.PromotePeerResponse{}, nil
+}
+
+// Query the etcd maintenance API of a member on its client URLs
+func (server *PeerAPIServer) memberStatus(ctx context.Context, member *membership.Member) (*etcdserverpb.StatusResponse, error) {
+	if len(member.ClientURLs) == 0 {
+		return nil, fmt.Errorf("member has not started yet")
+	}
+
+	transportCred := credentials.NewTLS(clientTLSConfig(server.State))
+
+	var err error
+	for _, clientUrl := range member.ClientURLs {
+		u, parseErr := url.Parse(clientUrl)
+		if parseErr != nil {
+			err = parseErr
+			continue
+		}
+
+		var conn *grpc.ClientConn
+		conn, err = grpc.NewClient(u.Host, grpc.WithTransportCredentials(transportCred))
+		if err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, peerStatusTimeout)
+		var res *etcdserverpb.StatusResponse
+		res, err = etcdserverpb.NewMaintenanceClient(conn).Status(ctx, &etcdserverpb.StatusRequest{})
+		cancel()
+		conn.Close()
+
+		if err == nil {
+			return res, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (server *PeerAPIServer) PeerStatus(ctx context.Context, req *pb.PeerStatusRequest) (*pb.PeerStatusResponse, error) {
+	members := server.EtcdServer.Cluster().Members()
+	leader := server.EtcdServer.Leader()
+
+	peers := make([]*pb.PeerStatus, len(members))
+	for i, member := range members {
+		isLeader := member.ID == leader
+		peerStatus := &pb.PeerStatus{
+			Peer:   memberToPb(member),
+			Leader: &isLeader,
+		}
+
+		res, err := server.memberStatus(ctx, member)
+		if err != nil {
+			peerStatus.Errors = []string{err.Error()}
+		} else {
+			peerStatus.Version = &res.Version
+			peerStatus.DbSize = &res.DbSize
+			peerStatus.DbSizeInUse = &res.DbSizeInUse
+			peerStatus.RaftTerm = &res.RaftTerm
+			peerStatus.RaftIndex = &res.RaftIndex
+			peerStatus.RaftAppliedIndex = &res.RaftAppliedIndex
+			peerStatus.Errors = res.Errors
+		}
+
+		healthy := err == nil && len(peerStatus.Errors) == 0
+		peerStatus.Healthy = &healthy
+
+		peers[i] = peerStatus
+	}
+
+	return &pb.PeerStatusResponse{Peers: peers}, nil
+}
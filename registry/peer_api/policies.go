@@ -0,0 +1,108 @@
+package peer_api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ssle/registry/schemas"
+	"ssle/registry/utils"
+	pb "ssle/services"
+)
+
+var (
+	InvalidPolicyNameError = status.Errorf(codes.InvalidArgument, "Policy name must not be empty or contain a slash")
+	PolicyNotFoundError    = status.Errorf(codes.NotFound, "Policy not found")
+)
+
+func policyToPb(policy *schemas.PolicySchema) *pb.Policy {
+	res := &pb.Policy{
+		Name:             &policy.Name,
+		Tags:             policy.Tags,
+		AllowLocations:   policy.AllowLocations,
+		AllowDatacenters: policy.AllowDatacenters,
+		AllowServices:    policy.AllowServices,
+	}
+	if policy.Service != "" {
+		res.Service = &policy.Service
+	}
+	if policy.Location != "" {
+		res.Location = &policy.Location
+	}
+	if policy.Datacenter != "" {
+		res.Datacenter = &policy.Datacenter
+	}
+	return res
+}
+
+// Create a policy or replace the one with the same name
+func (server *PeerAPIServer) SetPolicy(ctx context.Context, req *pb.SetPolicyRequest) (*pb.SetPolicyResponse, error) {
+	if *req.Policy.Name == "" || strings.Contains(*req.Policy.Name, "/") {
+		return nil, InvalidPolicyNameError
+	}
+
+	policy := schemas.PolicySchema{
+		Name:             *req.Policy.Name,
+		Service:          req.Policy.GetService(),
+		Tags:             req.Policy.Tags,
+		Location:         req.Policy.GetLocation(),
+		Datacenter:       req.Policy.GetDatacenter(),
+		AllowLocations:   req.Policy.AllowLocations,
+		AllowDatacenters: req.Policy.AllowDatacenters,
+		AllowServices:    req.Policy.AllowServices,
+	}
+
+	serializedPolicy, err := json.Marshal(policy)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding policy", "err", err)
+		return nil, utils.ServerError
+	}
+
+	_, err = server.EtcdServer.Put(ctx, &etcdserverpb.PutRequest{
+		Key:   utils.PolicyKey(policy.Name),
+		Value: serializedPolicy,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing policy", "err", err)
+		return nil, utils.ServerError
+	}
+
+	slog.InfoContext(ctx, "Set policy", "name", policy.Name)
+
+	return &pb.SetPolicyResponse{}, nil
+}
+
+func (server *PeerAPIServer) ListPolicies(ctx context.Context, req *pb.ListPoliciesRequest) (*pb.ListPoliciesResponse, error) {
+	policies, err := utils.GetPolicies(ctx, server.EtcdServer)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*pb.Policy, len(policies))
+	for i := range policies {
+		res[i] = policyToPb(&policies[i])
+	}
+
+	return &pb.ListPoliciesResponse{Policies: res}, nil
+}
+
+func (server *PeerAPIServer) RemovePolicy(ctx context.Context, req *pb.RemovePolicyRequest) (*pb.RemovePolicyResponse, error) {
+	res, err := server.EtcdServer.DeleteRange(ctx, &etcdserverpb.DeleteRangeRequest{Key: utils.PolicyKey(*req.Name)})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error deleting policy", "err", err)
+		return nil, utils.ServerError
+	}
+
+	if res.Deleted == 0 {
+		return nil, PolicyNotFoundError
+	}
+
+	slog.InfoContext(ctx, "Removed policy", "name", *req.Name)
+
+	return &pb.RemovePolicyResponse{}, nil
+}
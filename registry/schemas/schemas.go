@@ -5,13 +5,113 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"time"
 )
 
 type NodeSchema struct {
 	Name       string `json:"name"`
 	Datacenter string `json:"dc"`
 	Location   string `json:"location"`
-	Type       string `json:type`
+	Type       string `json:"type"`
+	Disabled   bool   `json:"disabled,omitempty"`
+}
+
+type CertificateSchema struct {
+	Serial       string    `json:"serial"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	Revoked      bool      `json:"revoked,omitempty"`
+	CAGeneration int       `json:"ca_generation,omitempty"`
+}
+
+type CAGenerationSchema struct {
+	Generation int       `json:"generation"`
+	Start      time.Time `json:"start"`
+	// PEM certificates of the generation CAs when it isn't derived from the
+	// registry token, registries that don't have the token it was derived
+	// from can still trust it
+	ServerCA   string `json:"server_ca,omitempty"`
+	AgentCA    string `json:"agent_ca,omitempty"`
+	OperatorCA string `json:"operator_ca,omitempty"`
+}
+
+// Generations of the registry CAs that are trusted, the active one signs new
+// certificates.
+type CAStateSchema struct {
+	Active      int                  `json:"active"`
+	Generations []CAGenerationSchema `json:"generations"`
+}
+
+func (caState *CAStateSchema) ActiveGeneration() CAGenerationSchema {
+	for _, generation := range caState.Generations {
+		if generation.Generation == caState.Active {
+			return generation
+		}
+	}
+
+	return CAGenerationSchema{}
+}
+
+type BootstrapTokenSchema struct {
+	Name       string `json:"name"`
+	Datacenter string `json:"dc"`
+}
+
+// Operator allowed to use the peer API with a certificate issued by the
+// registry, only the certificate with the recorded serial is accepted
+type OperatorSchema struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+	// Datacenter the operator is restricted to, all of them if empty
+	Datacenter string    `json:"dc,omitempty"`
+	Serial     string    `json:"serial"`
+	NotAfter   time.Time `json:"not_after"`
+}
+
+// Restricts which agents can discover the services the policy selects.
+// Services selected by no policy are visible to every agent, the others only
+// to the agents allowed by one of the policies selecting them. Observers
+// monitor their whole datacenter and are not restricted.
+type PolicySchema struct {
+	Name string `json:"name"`
+
+	// Services selected, an empty field matches any value
+	Service    string   `json:"service,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	Datacenter string   `json:"dc,omitempty"`
+
+	// Agents allowed by location or datacenter, or discovering on behalf of
+	// one of the services
+	AllowLocations   []string `json:"allow_locations,omitempty"`
+	AllowDatacenters []string `json:"allow_dcs,omitempty"`
+	AllowServices    []string `json:"allow_services,omitempty"`
+}
+
+// Whether the workloads of the source service may connect to the
+// destination service, either can be * to match every service
+type IntentionSchema struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// allow or deny
+	Action      string `json:"action"`
+	Description string `json:"description,omitempty"`
+}
+
+// Administrative or security relevant action handled by a registry
+type AuditRecordSchema struct {
+	Time      time.Time `json:"time"`
+	Registry  string    `json:"registry"`
+	Actor     string    `json:"actor"`
+	ActorType string    `json:"actor_type"`
+	Address   string    `json:"address,omitempty"`
+	Action    string    `json:"action"`
+	// Datacenter and name of the node, operator or peer acted on
+	Datacenter string `json:"dc,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Outcome    string `json:"outcome"`
+	Error      string `json:"error,omitempty"`
+	RequestID  string `json:"request_id,omitempty"`
 }
 
 type Hostname struct {
@@ -2,6 +2,7 @@ package state
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/ed25519"
 	"crypto/hkdf"
 	"crypto/rand"
@@ -10,37 +11,85 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/big"
 	"net"
 	"os"
 	"path/filepath"
 	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"ssle/registry/config"
 	"ssle/registry/schemas"
+	"ssle/services/logging"
 )
 
+const (
+	PeerCertificateExpiry = 90 * 24 * time.Hour
+	// Peer certificates are renewed once they are in the last third of their
+	// lifetime
+	peerCertificateRenewBefore = PeerCertificateExpiry / 3
+	peerCertificateCheckPeriod = time.Hour
+)
+
+type caGeneration struct {
+	server tls.Certificate
+	agent  tls.Certificate
+	// Only trusted by the peer API, etcd trusts the server CA alone. Unknown
+	// for generations pinned before operators had their own CA.
+	operator tls.Certificate
+}
+
 type State struct {
+	// Replaced when a generation derived from a rotated token is activated
 	Token   []byte
+	Start   time.Time
 	EtcdDir string
 
-	CA             tls.Certificate
 	CACrtFile      string
-	AgentCA        tls.Certificate
 	AgentCACrtFile string
-
-	ServerKeyPair tls.Certificate
-	ServerCrtFile string
-	ServerKeyFile string
+	ServerCrtFile  string
+	ServerKeyFile  string
+
+	// Generations in the CA bundle when the registry started, etcd only
+	// loads its trusted CAs then
+	StartupTrust []int
+
+	mu             sync.RWMutex
+	config         config.Config
+	tokenFile      string
+	caStateFile    string
+	caKeyFile      string
+	agentCAKeyFile string
+	caState        schemas.CAStateSchema
+	cas            map[int]caGeneration
+	serverKeyPair  tls.Certificate
+
+	// Tokens of generations derived from a rotated cluster token, they are
+	// shared between registries over the peer API and never stored in etcd
+	tokens     map[int][]byte
+	tokensFile string
 }
 
-func createCA(token []byte, start time.Time, implicit string, ou string) ([]byte, []byte) {
-	keyRandom, err := hkdf.Expand(sha256.New, token, implicit, ed25519.SeedSize)
+// Derive the CA of a generation from the cluster token, generation 0 is the
+// one every cluster starts with.
+func createCA(token []byte, start time.Time, implicit string, ou string, generation int) ([]byte, []byte) {
+	info := implicit
+	subject := pkix.Name{
+		Organization:       []string{"SSLE Project 01"},
+		OrganizationalUnit: []string{ou},
+	}
+	if generation > 0 {
+		info = fmt.Sprintf("%s-%d", implicit, generation)
+		subject.CommonName = fmt.Sprintf("%s CA %d", ou, generation)
+	}
+
+	keyRandom, err := hkdf.Expand(sha256.New, token, info, ed25519.SeedSize)
 	if err != nil {
 		panic(err.Error())
 	}
@@ -51,12 +100,9 @@ func createCA(token []byte, start time.Time, implicit string, ou string) ([]byte
 	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(1),
-		Subject: pkix.Name{
-			Organization:       []string{"SSLE Project 01"},
-			OrganizationalUnit: []string{ou},
-		},
-		NotBefore: start,
+		SerialNumber: big.NewInt(int64(generation) + 1),
+		Subject:      subject,
+		NotBefore:    start,
 		// 10 Years
 		NotAfter:              start.Add(10 * 365 * 24 * time.Hour),
 		KeyUsage:              x509.KeyUsageCertSign,
@@ -101,8 +147,7 @@ func createPeerCrt(config config.Config, CA tls.Certificate) ([]byte, []byte) {
 	}
 
 	notBefore := time.Now()
-	// 90 Days
-	notAfter := notBefore.Add(90 * 24 * time.Hour)
+	notAfter := notBefore.Add(PeerCertificateExpiry)
 
 	template := x509.Certificate{
 		Subject: pkix.Name{
@@ -137,8 +182,13 @@ func createPeerCrt(config config.Config, CA tls.Certificate) ([]byte, []byte) {
 	return crt, key
 }
 
-func decodeToken(encodedToken []byte) ([]byte, time.Time) {
-	parts := bytes.SplitN(encodedToken, []byte("::"), 2)
+// Share tokens are <start>::<token>, followed by ::<generation>::<start> of
+// the active CA generation once the CAs have been rolled over
+func decodeToken(encodedToken []byte) ([]byte, time.Time, *schemas.CAGenerationSchema) {
+	parts := bytes.SplitN(encodedToken, []byte("::"), 4)
+	if len(parts) != 2 && len(parts) != 4 {
+		logging.Fatal("Failed to decode token: malformed token")
+	}
 
 	timeMilli, err := strconv.ParseInt(string(parts[0]), 10, 64)
 	if err != nil {
@@ -150,16 +200,46 @@ func decodeToken(encodedToken []byte) ([]byte, time.Time) {
 	token := make([]byte, base64.StdEncoding.DecodedLen(len(rawToken)))
 	n, err := base64.StdEncoding.Decode(token, rawToken)
 	if err != nil {
-		log.Fatalf("Failed to decode token: %v", err)
+		logging.Fatal("Failed to decode token", "err", err)
+	}
+
+	if len(parts) == 2 {
+		return token[:n], time.UnixMilli(timeMilli), nil
+	}
+
+	generation, err := strconv.Atoi(string(parts[2]))
+	if err != nil {
+		logging.Fatal("Failed to decode token CA generation", "err", err)
+	}
+
+	generationMilli, err := strconv.ParseInt(string(parts[3]), 10, 64)
+	if err != nil {
+		logging.Fatal("Failed to decode token CA generation", "err", err)
+	}
+
+	return token[:n], time.UnixMilli(timeMilli), &schemas.CAGenerationSchema{
+		Generation: generation,
+		Start:      time.UnixMilli(generationMilli),
 	}
-	return token[:n], time.UnixMilli(timeMilli)
 }
 
-func loadStateToken(config config.Config) ([]byte, time.Time) {
+func encodeToken(token []byte, start time.Time) []byte {
+	encodedToken := make([]byte, base64.StdEncoding.EncodedLen(len(token)))
+	base64.StdEncoding.Encode(encodedToken, token)
+
+	return slices.Concat(
+		fmt.Appendf(nil, "%d", start.UnixMilli()),
+		[]byte("::"),
+		encodedToken,
+	)
+}
+
+func loadStateToken(config config.Config) ([]byte, time.Time, *schemas.CAGenerationSchema) {
 	tokenFile := filepath.Join(config.Dir, "token")
 
 	var token []byte
 	var start time.Time
+	var generation *schemas.CAGenerationSchema
 
 	if _, err := os.Stat(tokenFile); os.IsNotExist(err) {
 		if config.InitialToken == "" {
@@ -167,122 +247,680 @@ func loadStateToken(config config.Config) ([]byte, time.Time) {
 			rand.Read(token)
 			start = time.Now()
 		} else {
-			token, start = decodeToken([]byte(config.InitialToken))
+			token, start, generation = decodeToken([]byte(config.InitialToken))
+		}
+
+		os.WriteFile(tokenFile, encodeToken(token, start), 0600)
+	} else {
+		encodedToken, err := os.ReadFile(tokenFile)
+		if err != nil {
+			logging.Fatal("Failed to read token file", "err", err)
 		}
+		token, start, _ = decodeToken(encodedToken)
+	}
+
+	return token, start, generation
+}
+
+func loadStateCAState(config config.Config, start time.Time, generation *schemas.CAGenerationSchema) (string, schemas.CAStateSchema) {
+	caStateFile := filepath.Join(config.Dir, "ca.json")
 
-		encodedToken := make([]byte, base64.StdEncoding.EncodedLen(len(token)))
-		base64.StdEncoding.Encode(encodedToken, token)
+	var caState schemas.CAStateSchema
 
-		shareToken := slices.Concat(
-			fmt.Appendf(nil, "%d", start.UnixMilli()),
-			[]byte("::"),
-			encodedToken,
-		)
+	data, err := os.ReadFile(caStateFile)
+	if err == nil {
+		err = json.Unmarshal(data, &caState)
+		if err != nil {
+			logging.Fatal("Failed to decode CA state", "err", err)
+		}
+	} else if os.IsNotExist(err) {
+		if generation == nil {
+			generation = &schemas.CAGenerationSchema{Generation: 0, Start: start}
+		}
 
-		os.WriteFile(tokenFile, shareToken, 0600)
+		caState = schemas.CAStateSchema{
+			Active:      generation.Generation,
+			Generations: []schemas.CAGenerationSchema{*generation},
+		}
 	} else {
-		encodedToken, err := os.ReadFile(tokenFile)
+		logging.Fatal("Failed to read CA state", "err", err)
+	}
+
+	return caStateFile, caState
+}
+
+func loadStateTokens(config config.Config) (string, map[int][]byte) {
+	tokensFile := filepath.Join(config.Dir, "ca_tokens.json")
+
+	tokens := map[int][]byte{}
+
+	data, err := os.ReadFile(tokensFile)
+	if err == nil {
+		err = json.Unmarshal(data, &tokens)
 		if err != nil {
-			log.Fatalf("Failed to read token file: %v", err)
+			logging.Fatal("Failed to decode CA generation tokens", "err", err)
 		}
-		token, start = decodeToken(encodedToken)
+	} else if !os.IsNotExist(err) {
+		logging.Fatal("Failed to read CA generation tokens", "err", err)
 	}
 
-	return token, start
+	return tokensFile, tokens
 }
 
-func loadStateCA(config config.Config, token []byte, start time.Time) (string, string, tls.Certificate) {
-	certFile := filepath.Join(config.Dir, "ca.crt")
-	keyFile := filepath.Join(config.Dir, "ca.key")
+func writeKeyPair(crtFile string, crtBytes []byte, keyFile string, keyBytes []byte) error {
+	err := os.WriteFile(crtFile, crtBytes, 0600)
+	if err != nil {
+		return err
+	}
 
-	crtBytes, keyBytes := createCA(token, start, "CA", "Servers")
-	keyPair, err := tls.X509KeyPair(crtBytes, keyBytes)
+	return os.WriteFile(keyFile, keyBytes, 0600)
+}
+
+func encodeCrt(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func encodeKey(key crypto.PrivateKey) ([]byte, error) {
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
-		log.Fatalf("Failed to load CA key pair: %v", err)
+		return nil, err
 	}
 
-	err = os.WriteFile(certFile, crtBytes, 0600)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), nil
+}
+
+// CA certificate without its key
+func parseCA(crtBytes string) (tls.Certificate, error) {
+	block, _ := pem.Decode([]byte(crtBytes))
+	if block == nil {
+		return tls.Certificate{}, fmt.Errorf("malformed CA certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		log.Fatalf("Error: Failed to write CA certificate: %v", err)
+		return tls.Certificate{}, err
 	}
 
-	err = os.WriteFile(keyFile, keyBytes, 0600)
+	return tls.Certificate{Certificate: [][]byte{cert.Raw}, Leaf: cert}, nil
+}
+
+// Derive the CAs of a generation along with their keys
+func deriveCAGeneration(token []byte, generation schemas.CAGenerationSchema) (caGeneration, error) {
+	crtBytes, keyBytes := createCA(token, generation.Start, "CA", "Servers", generation.Generation)
+	server, err := tls.X509KeyPair(crtBytes, keyBytes)
 	if err != nil {
-		log.Fatalf("Error: Failed to write CA key: %v", err)
+		return caGeneration{}, err
 	}
 
-	return certFile, keyFile, keyPair
+	agentCrtBytes, agentKeyBytes := createCA(token, generation.Start, "Agent-CA", "Agents", generation.Generation)
+	agent, err := tls.X509KeyPair(agentCrtBytes, agentKeyBytes)
+	if err != nil {
+		return caGeneration{}, err
+	}
+
+	operatorCrtBytes, operatorKeyBytes := createCA(token, generation.Start, "Operator-CA", "Operators", generation.Generation)
+	operator, err := tls.X509KeyPair(operatorCrtBytes, operatorKeyBytes)
+	if err != nil {
+		return caGeneration{}, err
+	}
+
+	return caGeneration{server: server, agent: agent, operator: operator}, nil
 }
 
-func loadStateAgentCA(config config.Config, token []byte, start time.Time) (string, string, tls.Certificate) {
-	certFile := filepath.Join(config.Dir, "agent-ca.crt")
-	keyFile := filepath.Join(config.Dir, "agent-ca.key")
+// Whether a generation with recorded certificates is derived from token, CAs
+// are deterministic so deriving them again gives the same certificates
+func tokenDerives(token []byte, generation schemas.CAGenerationSchema) bool {
+	crtBytes, _ := createCA(token, generation.Start, "CA", "Servers", generation.Generation)
+	return bytes.Equal(crtBytes, []byte(generation.ServerCA))
+}
 
-	crtBytes, keyBytes := createCA(token, start, "Agent-CA", "Agents")
-	keyPair, err := tls.X509KeyPair(crtBytes, keyBytes)
+// Token a generation is derived from, if this registry knows it
+func (state *State) generationToken(generation schemas.CAGenerationSchema) ([]byte, bool) {
+	if generation.ServerCA == "" {
+		return state.Token, true
+	}
+
+	for _, token := range [][]byte{state.Token, state.tokens[generation.Generation]} {
+		if token != nil && tokenDerives(token, generation) {
+			return token, true
+		}
+	}
+
+	return nil, false
+}
+
+// CAs of a generation, only their certificates if its token isn't known
+func (state *State) generationCAs(generation schemas.CAGenerationSchema) (caGeneration, error) {
+	if token, found := state.generationToken(generation); found {
+		return deriveCAGeneration(token, generation)
+	}
+
+	server, err := parseCA(generation.ServerCA)
 	if err != nil {
-		log.Fatalf("Failed to load CA key pair: %v", err)
+		return caGeneration{}, err
 	}
 
-	err = os.WriteFile(certFile, crtBytes, 0600)
+	agent, err := parseCA(generation.AgentCA)
 	if err != nil {
-		log.Fatalf("Error: Failed to write agent CA certificate: %v", err)
+		return caGeneration{}, err
 	}
 
-	err = os.WriteFile(keyFile, keyBytes, 0600)
+	var operator tls.Certificate
+	if generation.OperatorCA != "" {
+		operator, err = parseCA(generation.OperatorCA)
+		if err != nil {
+			return caGeneration{}, err
+		}
+	}
+
+	return caGeneration{server: server, agent: agent, operator: operator}, nil
+}
+
+func (state *State) writeTokens() error {
+	serialized, err := json.Marshal(state.tokens)
 	if err != nil {
-		log.Fatalf("Error: Failed to write agent CA key: %v", err)
+		return err
 	}
 
-	return certFile, keyFile, keyPair
+	return os.WriteFile(state.tokensFile, serialized, 0600)
 }
 
-func loadStatePeerCrt(config config.Config, CA tls.Certificate) (string, string, tls.Certificate) {
-	certFile := filepath.Join(config.Dir, "peer.crt")
-	keyFile := filepath.Join(config.Dir, "peer.key")
+// Derive the CAs of every trusted generation and write the bundles with all
+// of them, along with the active CA keys.
+func (state *State) loadCAs(caState schemas.CAStateSchema) error {
+	cas := map[int]caGeneration{}
+	var serverBundle, agentBundle []byte
 
-	crtBytes, keyBytes := createPeerCrt(config, CA)
+	for _, generation := range caState.Generations {
+		ca, err := state.generationCAs(generation)
+		if err != nil {
+			return err
+		}
 
-	keyPair, err := tls.X509KeyPair(crtBytes, keyBytes)
+		cas[generation.Generation] = ca
+		serverBundle = append(serverBundle, encodeCrt(ca.server.Leaf)...)
+		agentBundle = append(agentBundle, encodeCrt(ca.agent.Leaf)...)
+
+		if generation.Generation == caState.Active {
+			if ca.server.PrivateKey == nil {
+				return fmt.Errorf("token of the active CA generation %d is not known", caState.Active)
+			}
+
+			keyBytes, err := encodeKey(ca.server.PrivateKey)
+			if err != nil {
+				return err
+			}
+
+			err = os.WriteFile(state.caKeyFile, keyBytes, 0600)
+			if err != nil {
+				return err
+			}
+
+			agentKeyBytes, err := encodeKey(ca.agent.PrivateKey)
+			if err != nil {
+				return err
+			}
+
+			err = os.WriteFile(state.agentCAKeyFile, agentKeyBytes, 0600)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, found := cas[caState.Active]; !found {
+		return fmt.Errorf("active CA generation %d is not trusted", caState.Active)
+	}
+
+	err := os.WriteFile(state.CACrtFile, serverBundle, 0600)
 	if err != nil {
-		log.Fatalf("Failed to load CA key pair: %v", err)
+		return err
 	}
 
-	err = os.WriteFile(certFile, crtBytes, 0600)
+	err = os.WriteFile(state.AgentCACrtFile, agentBundle, 0600)
 	if err != nil {
-		log.Fatalf("Error: Failed to write peer certificate: %v", err)
+		return err
 	}
 
-	err = os.WriteFile(keyFile, keyBytes, 0600)
+	serializedState, err := json.Marshal(caState)
 	if err != nil {
-		log.Fatalf("Error: Failed to write peer key: %v", err)
+		return err
 	}
 
-	return certFile, keyFile, keyPair
+	err = os.WriteFile(state.caStateFile, serializedState, 0600)
+	if err != nil {
+		return err
+	}
+
+	// Forget the tokens of retired generations, rollovers only move forward
+	// so they can't be needed again
+	oldest := slices.MinFunc(caState.Generations, func(a, b schemas.CAGenerationSchema) int {
+		return a.Generation - b.Generation
+	})
+	pruned := false
+	for generation := range state.tokens {
+		if generation < oldest.Generation {
+			delete(state.tokens, generation)
+			pruned = true
+		}
+	}
+	if pruned {
+		err = state.writeTokens()
+		if err != nil {
+			return err
+		}
+	}
+
+	state.cas = cas
+	state.caState = caState
+
+	return nil
 }
 
-func LoadState(config config.Config) State {
+// Issue a new peer certificate from the active CA, etcd reloads it from the
+// files on every handshake.
+func (state *State) renewServerCrt() error {
+	crtBytes, keyBytes := createPeerCrt(state.config, state.cas[state.caState.Active].server)
+
+	keyPair, err := tls.X509KeyPair(crtBytes, keyBytes)
+	if err != nil {
+		return err
+	}
+
+	err = writeKeyPair(state.ServerCrtFile, crtBytes, state.ServerKeyFile, keyBytes)
+	if err != nil {
+		return err
+	}
+
+	state.serverKeyPair = keyPair
+
+	return nil
+}
+
+func LoadState(config config.Config) *State {
 	err := os.Mkdir(config.Dir, 0700)
 	if err != nil && !os.IsExist(err) {
-		log.Fatalf("Failed to create state dir: %v", err)
+		logging.Fatal("Failed to create state dir", "err", err)
 	}
 
-	token, start := loadStateToken(config)
-	caCrtFile, _, CA := loadStateCA(config, token, start)
-	agentCaCrtFile, _, AgentCA := loadStateAgentCA(config, token, start)
-	serverCrtFile, serverKeyFile, serverKeyPair := loadStatePeerCrt(config, CA)
+	token, start, generation := loadStateToken(config)
+	caStateFile, caState := loadStateCAState(config, start, generation)
+	tokensFile, tokens := loadStateTokens(config)
 
-	return State{
+	state := &State{
 		Token:   token,
+		Start:   start,
 		EtcdDir: filepath.Join(config.Dir, "etcd"),
 
-		CA:             CA,
-		CACrtFile:      caCrtFile,
-		AgentCA:        AgentCA,
-		AgentCACrtFile: agentCaCrtFile,
+		CACrtFile:      filepath.Join(config.Dir, "ca.crt"),
+		AgentCACrtFile: filepath.Join(config.Dir, "agent-ca.crt"),
+		ServerCrtFile:  filepath.Join(config.Dir, "peer.crt"),
+		ServerKeyFile:  filepath.Join(config.Dir, "peer.key"),
+
+		config:         config,
+		tokenFile:      filepath.Join(config.Dir, "token"),
+		tokens:         tokens,
+		tokensFile:     tokensFile,
+		caStateFile:    caStateFile,
+		caKeyFile:      filepath.Join(config.Dir, "ca.key"),
+		agentCAKeyFile: filepath.Join(config.Dir, "agent-ca.key"),
+	}
+
+	err = state.loadCAs(caState)
+	if err != nil {
+		logging.Fatal("Failed to load CAs", "err", err)
+	}
+
+	for _, generation := range caState.Generations {
+		state.StartupTrust = append(state.StartupTrust, generation.Generation)
+	}
+
+	err = state.renewServerCrt()
+	if err != nil {
+		logging.Fatal("Failed to write peer certificate", "err", err)
+	}
+
+	return state
+}
+
+func (state *State) CAState() schemas.CAStateSchema {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	caState := state.caState
+	caState.Generations = slices.Clone(caState.Generations)
+	return caState
+}
+
+// Switch to a new set of trusted CAs, re-issuing the peer certificate if the
+// active generation changed.
+func (state *State) UpdateCAState(caState schemas.CAStateSchema) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	previousActive := state.caState.Active
+
+	err := state.loadCAs(caState)
+	if err != nil {
+		return err
+	}
+
+	if caState.Active == previousActive {
+		return nil
+	}
+
+	slog.Info("CA generation is now active, renewing peer certificate", "generation", caState.Active)
+
+	// Once a generation derived from a rotated token is active, it becomes
+	// the registry token. loadCAs made sure it is known.
+	active := caState.ActiveGeneration()
+	token, _ := state.generationToken(active)
+
+	if !bytes.Equal(token, state.Token) {
+		slog.Info("Switching to the rotated cluster token")
+
+		err = os.WriteFile(state.tokenFile, encodeToken(token, active.Start), 0600)
+		if err != nil {
+			return err
+		}
+
+		state.Token = token
+		state.Start = active.Start
+	}
+
+	return state.renewServerCrt()
+}
+
+// Record the certificates of the generations derived from the registry
+// token, registries can't tell them apart once they use another token
+func (state *State) PinCAGenerations(caState *schemas.CAStateSchema) error {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	for i, generation := range caState.Generations {
+		if generation.ServerCA != "" && generation.OperatorCA != "" {
+			continue
+		}
+
+		ca, err := state.generationCAs(generation)
+		if err != nil {
+			return err
+		}
+
+		caState.Generations[i].ServerCA = string(encodeCrt(ca.server.Leaf))
+		caState.Generations[i].AgentCA = string(encodeCrt(ca.agent.Leaf))
+		if ca.operator.Leaf != nil {
+			caState.Generations[i].OperatorCA = string(encodeCrt(ca.operator.Leaf))
+		}
+	}
+
+	return nil
+}
+
+// Create a CA generation derived from a new random cluster token. The token
+// is only kept by this registry until the others fetch it.
+func (state *State) NewTokenGeneration(generation int, start time.Time) (schemas.CAGenerationSchema, error) {
+	token := make([]byte, 32)
+	rand.Read(token)
+
+	newGeneration := schemas.CAGenerationSchema{Generation: generation, Start: start}
+	ca, err := deriveCAGeneration(token, newGeneration)
+	if err != nil {
+		return schemas.CAGenerationSchema{}, err
+	}
+	newGeneration.ServerCA = string(encodeCrt(ca.server.Leaf))
+	newGeneration.AgentCA = string(encodeCrt(ca.agent.Leaf))
+	newGeneration.OperatorCA = string(encodeCrt(ca.operator.Leaf))
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.tokens[generation] = token
+	err = state.writeTokens()
+	if err != nil {
+		return schemas.CAGenerationSchema{}, err
+	}
+
+	return newGeneration, nil
+}
+
+// Token of a CA generation, if this registry knows it
+func (state *State) CAToken(generation int) ([]byte, bool) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	if token, found := state.tokens[generation]; found {
+		return token, true
+	}
+
+	for _, g := range state.caState.Generations {
+		if g.Generation == generation {
+			return state.generationToken(g)
+		}
+	}
 
-		ServerKeyPair: serverKeyPair,
-		ServerCrtFile: serverCrtFile,
-		ServerKeyFile: serverKeyFile,
+	return nil, false
+}
+
+// Generations that may still be activated and whose token this registry
+// doesn't know
+func (state *State) MissingCATokens(caState schemas.CAStateSchema) []schemas.CAGenerationSchema {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	missing := []schemas.CAGenerationSchema{}
+	for _, generation := range caState.Generations {
+		if generation.Generation < caState.Active {
+			continue
+		}
+
+		if _, found := state.generationToken(generation); !found {
+			missing = append(missing, generation)
+		}
+	}
+
+	return missing
+}
+
+// Remember the token of a generation obtained from another registry
+func (state *State) AddCAToken(generation schemas.CAGenerationSchema, token []byte) error {
+	if !tokenDerives(token, generation) {
+		return fmt.Errorf("token doesn't derive CA generation %d", generation.Generation)
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.tokens[generation.Generation] = token
+	return state.writeTokens()
+}
+
+// Tokens of the CA generations derived from rotated cluster tokens, in the
+// format of the state tokens file
+func (state *State) EncodedCATokens() ([]byte, error) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return json.Marshal(state.tokens)
+}
+
+// Registry token in the format of the state token file
+func (state *State) EncodedToken() string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return string(encodeToken(state.Token, state.Start))
+}
+
+// Token new registries use to join the cluster, it includes the active CA
+// generation so they can issue themselves a trusted peer certificate.
+func (state *State) JoinToken(caState schemas.CAStateSchema) string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	// The active generation may be derived from a rotated token this
+	// registry didn't switch to yet
+	token, start := state.Token, state.Start
+	active := caState.ActiveGeneration()
+	if activeToken, found := state.generationToken(active); found && !bytes.Equal(activeToken, state.Token) {
+		token, start = activeToken, active.Start
+	}
+
+	shareToken := encodeToken(token, start)
+	if caState.Active != 0 {
+		shareToken = fmt.Appendf(shareToken, "::%d::%d", active.Generation, active.Start.UnixMilli())
+	}
+	return string(shareToken)
+}
+
+func (state *State) SigningCA() tls.Certificate {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return state.cas[state.caState.Active].server
+}
+
+// Operators are signed by their own CA so that etcd, which trusts the server
+// CA, doesn't accept their certificates
+func (state *State) SigningOperatorCA() tls.Certificate {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return state.cas[state.caState.Active].operator
+}
+
+func (state *State) SigningAgentCA() (tls.Certificate, int) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return state.cas[state.caState.Active].agent, state.caState.Active
+}
+
+func (state *State) CertPool() *x509.CertPool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	pool := x509.NewCertPool()
+	for _, ca := range state.cas {
+		pool.AddCert(ca.server.Leaf)
+	}
+	return pool
+}
+
+// Client CAs of the peer API, registries and operators
+func (state *State) PeerAPICertPool() *x509.CertPool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	pool := x509.NewCertPool()
+	for _, ca := range state.cas {
+		pool.AddCert(ca.server.Leaf)
+		if ca.operator.Leaf != nil {
+			pool.AddCert(ca.operator.Leaf)
+		}
+	}
+	return pool
+}
+
+func (state *State) AgentCertPool() *x509.CertPool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	pool := x509.NewCertPool()
+	for _, ca := range state.cas {
+		pool.AddCert(ca.agent.Leaf)
+	}
+	return pool
+}
+
+// PEM bundle with every trusted server CA, nodes use it to verify registries
+func (state *State) CABundle() []byte {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	var bundle []byte
+	for _, generation := range state.caState.Generations {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: state.cas[generation.Generation].server.Leaf.Raw,
+		})...)
+	}
+	return bundle
+}
+
+// PEM bundle with every trusted agent CA, workloads use it to verify each
+// other
+func (state *State) AgentCABundle() []byte {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	var bundle []byte
+	for _, generation := range state.caState.Generations {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: state.cas[generation.Generation].agent.Leaf.Raw,
+		})...)
+	}
+	return bundle
+}
+
+// Whether a node certificate was issued by the active agent CA
+func (state *State) IssuedByActiveAgentCA(cert *x509.Certificate) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return cert.CheckSignatureFrom(state.cas[state.caState.Active].agent.Leaf) == nil
+}
+
+// Whether an operator certificate was issued by a trusted operator CA, and not
+// by the server CA that used to sign them
+func (state *State) IssuedByOperatorCA(cert *x509.Certificate) bool {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	for _, ca := range state.cas {
+		if ca.operator.Leaf != nil && cert.CheckSignatureFrom(ca.operator.Leaf) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (state *State) ServerCertificate() *tls.Certificate {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	return &state.serverKeyPair
+}
+
+// TLS configuration for the registry servers, the certificate and the client
+// CAs are looked up on every connection so they follow renewals and CA
+// rollovers.
+func (state *State) ServerTLSConfig(clientAuth tls.ClientAuthType, clientCAs func() *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				Certificates: []tls.Certificate{*state.ServerCertificate()},
+				ClientAuth:   clientAuth,
+				ClientCAs:    clientCAs(),
+			}, nil
+		},
+	}
+}
+
+// Renew the peer certificate before it expires without restarting
+func (state *State) ServerCrtRenewalJob() {
+	for {
+		time.Sleep(peerCertificateCheckPeriod)
+
+		state.mu.Lock()
+		if time.Until(state.serverKeyPair.Leaf.NotAfter) < peerCertificateRenewBefore {
+			slog.Info("Renewing peer certificate")
+			err := state.renewServerCrt()
+			if err != nil {
+				slog.Error("Failed to renew peer certificate", "err", err)
+			}
+		}
+		state.mu.Unlock()
 	}
 }
@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/schemas"
+)
+
+func nodeCertificateKey(dc string, nodeName string, serial string) []byte {
+	return fmt.Appendf(nil, "%s/%s/%s/%s", NodeCertificatesNamespace, dc, nodeName, serial)
+}
+
+func nodeCertificatesPrefix(dc string, nodeName string) []byte {
+	return fmt.Appendf(nil, "%s/%s/%s/", NodeCertificatesNamespace, dc, nodeName)
+}
+
+// Parse a PEM encoded certificate signing request and check its signature
+func ParseCSR(csrPem []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPem)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("Malformed certificate request")
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = csr.CheckSignature()
+	if err != nil {
+		return nil, err
+	}
+
+	return csr, nil
+}
+
+func CertificateSerial(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+// Record a certificate issued to a node. The record is attached to a lease
+// that expires with the certificate, since revoking it is pointless after.
+func RecordNodeCertificate(
+	ctx context.Context,
+	etcd *etcdserver.EtcdServer,
+	dc string,
+	nodeName string,
+	cert *x509.Certificate,
+	caGeneration int,
+) error {
+	record := schemas.CertificateSchema{
+		Serial:       CertificateSerial(cert.SerialNumber),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		CAGeneration: caGeneration,
+	}
+
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding certificate record", "err", err)
+		return ServerError
+	}
+
+	ttl := int64(time.Until(cert.NotAfter).Seconds()) + 1
+	lease, err := etcd.LeaseGrant(ctx, &etcdserverpb.LeaseGrantRequest{TTL: ttl})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating certificate lease", "err", err)
+		return ServerError
+	}
+
+	_, err = etcd.Put(ctx, &etcdserverpb.PutRequest{
+		Key:   nodeCertificateKey(dc, nodeName, record.Serial),
+		Value: serialized,
+		Lease: lease.ID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error recording certificate", "err", err)
+		return ServerError
+	}
+
+	return nil
+}
+
+// Certificates without a record are revoked, except those of the first CA
+// generation which may have been issued before serials were recorded. Those
+// are accepted until they expire.
+func IsNodeCertificateRevoked(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string, cert *x509.Certificate) (bool, error) {
+	res, err := etcd.Range(ctx, &etcdserverpb.RangeRequest{
+		Key: nodeCertificateKey(dc, nodeName, CertificateSerial(cert.SerialNumber)),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Only CAs of later generations have a common name
+	if len(res.Kvs) < 1 {
+		return cert.Issuer.CommonName != "", nil
+	}
+
+	var record schemas.CertificateSchema
+	err = json.Unmarshal(res.Kvs[0].Value, &record)
+	if err != nil {
+		return false, err
+	}
+
+	return record.Revoked, nil
+}
+
+func GetNodeCertificates(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string) ([]schemas.CertificateSchema, error) {
+	prefix := nodeCertificatesPrefix(dc, nodeName)
+	res, err := etcd.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching node certificates", "err", err)
+		return nil, ServerError
+	}
+
+	records := make([]schemas.CertificateSchema, len(res.Kvs))
+	for i, kv := range res.Kvs {
+		err = json.Unmarshal(kv.Value, &records[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding node certificate", "err", err)
+			return nil, ServerError
+		}
+	}
+
+	return records, nil
+}
+
+// Revoke the certificate of a node with the given serial, or all of them if
+// it is empty. Returns the serials that were revoked.
+func RevokeNodeCertificates(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string, serial string) ([]string, error) {
+	revoked, ops, err := RevokeNodeCertificatesOps(ctx, etcd, dc, nodeName, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ops) == 0 {
+		return revoked, nil
+	}
+
+	_, err = etcd.Txn(ctx, &etcdserverpb.TxnRequest{Success: ops})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error revoking node certificates", "err", err)
+		return nil, ServerError
+	}
+
+	return revoked, nil
+}
+
+// Operations marking the certificates of a node as revoked, along with their
+// serials.
+func RevokeNodeCertificatesOps(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string, serial string) ([]string, []*etcdserverpb.RequestOp, error) {
+	req := &etcdserverpb.RangeRequest{}
+	if serial != "" {
+		req.Key = nodeCertificateKey(dc, nodeName, serial)
+	} else {
+		req.Key = nodeCertificatesPrefix(dc, nodeName)
+		req.RangeEnd = PrefixEnd(req.Key)
+	}
+
+	res, err := etcd.Range(ctx, req)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching node certificates", "err", err)
+		return nil, nil, ServerError
+	}
+
+	revoked := []string{}
+	ops := []*etcdserverpb.RequestOp{}
+	for _, kv := range res.Kvs {
+		var record schemas.CertificateSchema
+		err = json.Unmarshal(kv.Value, &record)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding node certificate", "err", err)
+			return nil, nil, ServerError
+		}
+
+		if record.Revoked {
+			continue
+		}
+		record.Revoked = true
+
+		serialized, err := json.Marshal(record)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error encoding certificate record", "err", err)
+			return nil, nil, ServerError
+		}
+
+		revoked = append(revoked, record.Serial)
+		ops = append(ops, &etcdserverpb.RequestOp{
+			Request: &etcdserverpb.RequestOp_RequestPut{
+				RequestPut: &etcdserverpb.PutRequest{
+					Key:   kv.Key,
+					Value: serialized,
+					Lease: kv.Lease,
+				},
+			},
+		})
+	}
+
+	return revoked, ops, nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/schemas"
+)
+
+const (
+	IntentionAllow = "allow"
+	IntentionDeny  = "deny"
+
+	IntentionWildcard = "*"
+)
+
+func IntentionKey(source string, destination string) []byte {
+	return fmt.Appendf(nil, "%s/%s/%s", IntentionsNamespace, destination, source)
+}
+
+func GetIntentions(ctx context.Context, etcd *etcdserver.EtcdServer) ([]schemas.IntentionSchema, error) {
+	prefix := fmt.Appendf(nil, "%s/", IntentionsNamespace)
+	res, err := etcd.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching intentions", "err", err)
+		return nil, ServerError
+	}
+
+	intentions := make([]schemas.IntentionSchema, len(res.Kvs))
+	for i, kv := range res.Kvs {
+		err = json.Unmarshal(kv.Value, &intentions[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding intention", "err", err)
+			return nil, ServerError
+		}
+	}
+
+	return intentions, nil
+}
+
+func intentionPrecedence(intention *schemas.IntentionSchema) int {
+	precedence := 0
+	if intention.Destination != IntentionWildcard {
+		precedence += 2
+	}
+	if intention.Source != IntentionWildcard {
+		precedence += 1
+	}
+	return precedence
+}
+
+// The most specific intention matching a connection, an exact destination
+// takes precedence over an exact source. Returns nil if none matches.
+func MatchIntention(intentions []schemas.IntentionSchema, source string, destination string) *schemas.IntentionSchema {
+	var match *schemas.IntentionSchema
+	for i := range intentions {
+		intention := &intentions[i]
+		if intention.Source != IntentionWildcard && intention.Source != source ||
+			intention.Destination != IntentionWildcard && intention.Destination != destination {
+			continue
+		}
+
+		if match == nil || intentionPrecedence(intention) > intentionPrecedence(match) {
+			match = intention
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"testing"
+
+	"ssle/registry/schemas"
+)
+
+func TestMatchIntention(t *testing.T) {
+	intention := func(source string, destination string, action string) schemas.IntentionSchema {
+		return schemas.IntentionSchema{Source: source, Destination: destination, Action: action}
+	}
+
+	tests := []struct {
+		name        string
+		intentions  []schemas.IntentionSchema
+		source      string
+		destination string
+		// Source and destination of the expected match, nil when the
+		// connection is denied by default
+		want *schemas.IntentionSchema
+	}{
+		{
+			name:        "no intentions",
+			intentions:  nil,
+			source:      "web",
+			destination: "db",
+			want:        nil,
+		},
+		{
+			name: "no match",
+			intentions: []schemas.IntentionSchema{
+				intention("web", "cache", IntentionAllow),
+				intention("api", IntentionWildcard, IntentionAllow),
+			},
+			source:      "web",
+			destination: "db",
+			want:        nil,
+		},
+		{
+			name: "exact",
+			intentions: []schemas.IntentionSchema{
+				intention("web", "db", IntentionAllow),
+			},
+			source:      "web",
+			destination: "db",
+			want:        &schemas.IntentionSchema{Source: "web", Destination: "db", Action: IntentionAllow},
+		},
+		{
+			name: "exact over wildcard source",
+			intentions: []schemas.IntentionSchema{
+				intention(IntentionWildcard, "db", IntentionDeny),
+				intention("web", "db", IntentionAllow),
+			},
+			source:      "web",
+			destination: "db",
+			want:        &schemas.IntentionSchema{Source: "web", Destination: "db", Action: IntentionAllow},
+		},
+		{
+			name: "exact destination over exact source",
+			intentions: []schemas.IntentionSchema{
+				intention("web", IntentionWildcard, IntentionAllow),
+				intention(IntentionWildcard, "db", IntentionDeny),
+			},
+			source:      "web",
+			destination: "db",
+			want:        &schemas.IntentionSchema{Source: IntentionWildcard, Destination: "db", Action: IntentionDeny},
+		},
+		{
+			name: "exact source over wildcard",
+			intentions: []schemas.IntentionSchema{
+				intention(IntentionWildcard, IntentionWildcard, IntentionDeny),
+				intention("web", IntentionWildcard, IntentionAllow),
+			},
+			source:      "web",
+			destination: "db",
+			want:        &schemas.IntentionSchema{Source: "web", Destination: IntentionWildcard, Action: IntentionAllow},
+		},
+		{
+			name: "wildcard",
+			intentions: []schemas.IntentionSchema{
+				intention(IntentionWildcard, IntentionWildcard, IntentionAllow),
+			},
+			source:      "web",
+			destination: "db",
+			want:        &schemas.IntentionSchema{Source: IntentionWildcard, Destination: IntentionWildcard, Action: IntentionAllow},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MatchIntention(test.intentions, test.source, test.destination)
+			if test.want == nil {
+				if got != nil {
+					t.Fatalf("expected no match, got %+v", *got)
+				}
+				return
+			}
+
+			if got == nil || *got != *test.want {
+				t.Fatalf("expected %+v, got %+v", *test.want, got)
+			}
+		})
+	}
+}
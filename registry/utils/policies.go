@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/schemas"
+)
+
+func PolicyKey(name string) []byte {
+	return fmt.Appendf(nil, "%s/%s", PoliciesNamespace, name)
+}
+
+func GetPolicies(ctx context.Context, etcd *etcdserver.EtcdServer) ([]schemas.PolicySchema, error) {
+	prefix := fmt.Appendf(nil, "%s/", PoliciesNamespace)
+	res, err := etcd.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: PrefixEnd(prefix),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fetching policies", "err", err)
+		return nil, ServerError
+	}
+
+	policies := make([]schemas.PolicySchema, len(res.Kvs))
+	for i, kv := range res.Kvs {
+		err = json.Unmarshal(kv.Value, &policies[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding policy", "err", err)
+			return nil, ServerError
+		}
+	}
+
+	return policies, nil
+}
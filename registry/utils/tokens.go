@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+
+	"ssle/registry/schemas"
+)
+
+// Tokens are stored hashed so they can't be recovered from the registry data
+func bootstrapTokenKey(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Appendf(nil, "%s/%s", BootstrapTokensNamespace, hex.EncodeToString(hash[:]))
+}
+
+// Create a single use token a node can exchange for its credentials, it
+// expires after ttl.
+func CreateBootstrapToken(ctx context.Context, etcd *etcdserver.EtcdServer, node *schemas.NodeSchema, ttl time.Duration) (string, error) {
+	token := rand.Text()
+
+	serialized, err := json.Marshal(schemas.BootstrapTokenSchema{
+		Name:       node.Name,
+		Datacenter: node.Datacenter,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding bootstrap token", "err", err)
+		return "", ServerError
+	}
+
+	lease, err := etcd.LeaseGrant(ctx, &etcdserverpb.LeaseGrantRequest{TTL: int64(ttl.Seconds())})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating bootstrap token lease", "err", err)
+		return "", ServerError
+	}
+
+	_, err = etcd.Put(ctx, &etcdserverpb.PutRequest{
+		Key:   bootstrapTokenKey(token),
+		Value: serialized,
+		Lease: lease.ID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error storing bootstrap token", "err", err)
+		return "", ServerError
+	}
+
+	return token, nil
+}
+
+// Consume a bootstrap token, returning the node it was issued for or nil if
+// it is not valid.
+func ConsumeBootstrapToken(ctx context.Context, etcd *etcdserver.EtcdServer, token string) (*schemas.BootstrapTokenSchema, error) {
+	key := bootstrapTokenKey(token)
+
+	res, err := etcd.Txn(ctx, &etcdserverpb.TxnRequest{
+		Success: []*etcdserverpb.RequestOp{{
+			Request: &etcdserverpb.RequestOp_RequestDeleteRange{
+				RequestDeleteRange: &etcdserverpb.DeleteRangeRequest{
+					Key:    key,
+					PrevKv: true,
+				},
+			},
+		}},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error consuming bootstrap token", "err", err)
+		return nil, ServerError
+	}
+
+	// The delete is what makes the token single use, only the request that
+	// actually removed it gets the node back
+	deleted := res.Responses[0].GetResponseDeleteRange()
+	if deleted == nil || len(deleted.PrevKvs) < 1 {
+		return nil, nil
+	}
+
+	var bootstrap schemas.BootstrapTokenSchema
+	err = json.Unmarshal(deleted.PrevKvs[0].Value, &bootstrap)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error decoding bootstrap token", "err", err)
+		return nil, ServerError
+	}
+
+	return &bootstrap, nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"crypto"
 	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/x509"
@@ -10,9 +11,12 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/big"
 	"net"
+	"net/url"
 	"slices"
+	"strings"
 	"time"
 
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -23,8 +27,10 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	"ssle/registry/metrics"
 	"ssle/registry/schemas"
 	"ssle/registry/state"
+	"ssle/services/spiffe"
 )
 
 const (
@@ -33,12 +39,29 @@ const (
 	PrometheusServicesNamespace = "prom"
 	NodesNamespace              = "nodes"
 	NodesLeasesNamespace        = "node_lease"
+	NodeCertificatesNamespace   = "node_certs"
+	BootstrapTokensNamespace    = "bootstrap_token"
+	CAStateKey                  = "ca"
+	CATrustNamespace            = "ca_trust"
 	PeerAgentApiNamespace       = "peer_agent_api"
-
-	AgentCertificateOU           = "Agents"
-	ObserverCertificateOU        = "Observers"
-	NodeCertificateExpiry        = 7 * 24 * time.Hour
-	NodeKeepaliveTTL      uint32 = 30
+	PeerApiNamespace            = "peer_api"
+	DefragLockKey               = "defrag_lock"
+	DefragNamespace             = "defrag"
+	OperatorsNamespace          = "operators"
+	AuditNamespace              = "audit"
+	PoliciesNamespace           = "policies"
+	IntentionsNamespace         = "intentions"
+
+	AgentCertificateOU               = "Agents"
+	ObserverCertificateOU            = "Observers"
+	ServerCertificateOU              = "Servers"
+	OperatorCertificateOU            = "Operators"
+	OperatorCertificateExpiry        = 365 * 24 * time.Hour
+	WorkloadCertificateOU            = "Workloads"
+	WorkloadCertificateExpiry        = time.Hour
+	NodeCertificateExpiry            = 7 * 24 * time.Hour
+	NodeKeepaliveTTL          uint32 = 30
+	BootstrapTokenTTL                = time.Hour
 )
 
 var (
@@ -64,12 +87,30 @@ func ExtractPeerCertificate(ctx context.Context) (*x509.Certificate, error) {
 	return mtls.State.PeerCertificates[0], nil
 }
 
-func ExtractPeerDatacenterNode(cert *x509.Certificate) (string, string, error) {
-	if len(cert.Subject.OrganizationalUnit) != 2 {
+// Role and datacenter of a node certificate or CSR. Both are stored as OUs
+// of the same RDN, which is encoded as a set so their order isn't preserved.
+func NodeRoleDatacenter(subject pkix.Name) (string, string, error) {
+	ous := subject.OrganizationalUnit
+	if len(ous) != 2 {
 		return "", "", errors.New("Malformed agent certificate")
 	}
 
-	return cert.Subject.OrganizationalUnit[0], cert.Subject.CommonName, nil
+	for i, role := range ous {
+		if role == AgentCertificateOU || role == ObserverCertificateOU {
+			return role, ous[1-i], nil
+		}
+	}
+
+	return "", "", errors.New("Malformed agent certificate")
+}
+
+func ExtractPeerDatacenterNode(cert *x509.Certificate) (string, string, error) {
+	_, dc, err := NodeRoleDatacenter(cert.Subject)
+	if err != nil {
+		return "", "", err
+	}
+
+	return dc, cert.Subject.CommonName, nil
 }
 
 func GetNodeSchema(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string) (*schemas.NodeSchema, error) {
@@ -99,26 +140,46 @@ func GetNodeSchema(ctx context.Context, etcd *etcdserver.EtcdServer, dc string,
 func AuthenticateNodeFromCertificate(ctx context.Context, cert *x509.Certificate, etcd *etcdserver.EtcdServer) (string, *schemas.NodeSchema, error) {
 	dc, name, err := ExtractPeerDatacenterNode(cert)
 	if err != nil {
-		log.Printf("Error getting node auth: %v", err)
+		slog.ErrorContext(ctx, "Error getting node auth", "err", err)
 		return "", nil, AuthFailure
 	}
 
 	node, err := GetNodeSchema(ctx, etcd, dc, name)
 	if err != nil {
-		log.Printf("Error getting node schema: %v", err)
+		slog.ErrorContext(ctx, "Error getting node schema", "err", err)
 		return "", nil, AuthFailure
 	}
 
 	if node == nil {
-		log.Printf("Error getting node schema: %v/%v does not exist", dc, name)
+		slog.WarnContext(ctx, "Error authenticating node: node does not exist", "datacenter", dc, "node", name)
+		return "", nil, AuthFailure
+	}
+
+	if node.Disabled {
+		slog.WarnContext(ctx, "Error authenticating node: node is disabled", "datacenter", dc, "node", name)
+		return "", nil, AuthFailure
+	}
+
+	revoked, err := IsNodeCertificateRevoked(ctx, etcd, dc, name, cert)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error getting node certificate", "err", err)
 		return "", nil, AuthFailure
 	}
 
-	return cert.Subject.OrganizationalUnit[1], node, nil
+	if revoked {
+		slog.WarnContext(ctx, "Error authenticating node: certificate is revoked or unknown", "datacenter", dc, "node", name, "serial", fmt.Sprintf("%x", cert.SerialNumber))
+		return "", nil, AuthFailure
+	}
+
+	role, _, _ := NodeRoleDatacenter(cert.Subject)
+	return role, node, nil
 }
 
 func AuthenticateNode(ctx context.Context, etcd *etcdserver.EtcdServer, implicit string) (*schemas.NodeSchema, error) {
 	cert, err := ExtractPeerCertificate(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	role, node, err := AuthenticateNodeFromCertificate(ctx, cert, etcd)
 	if err != nil {
@@ -222,12 +283,142 @@ func GetNodeLease(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, n
 			return lease.ID, nil
 		}
 
-		log.Print("Transaction failed to update lease for node, retrying")
+		slog.WarnContext(ctx, "Transaction failed to update lease for node, retrying")
 	}
 
 	return 0, ServerError
 }
 
+// Revoke the lease of a node, removing all services it registered
+func RevokeNodeLease(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string) error {
+	leaseId, err := FindNodeLease(ctx, etcd, dc, nodeName)
+	if err != nil {
+		return err
+	}
+
+	return RevokeLease(ctx, etcd, leaseId)
+}
+
+// Lease id of a node without renewing it, 0 if it has none
+func FindNodeLease(ctx context.Context, etcd *etcdserver.EtcdServer, dc string, nodeName string) (int64, error) {
+	key := fmt.Appendf(nil, "%s/%s/%s", NodesLeasesNamespace, dc, nodeName)
+
+	res, err := etcd.Range(ctx, &etcdserverpb.RangeRequest{Key: key})
+	if err != nil {
+		return 0, ServerError
+	}
+
+	if len(res.Kvs) == 0 {
+		return 0, nil
+	}
+
+	var leaseId int64
+	err = json.Unmarshal(res.Kvs[0].Value, &leaseId)
+	// If we have an error in decoding the lease id, something must have
+	// gone wrong when storing it, so ignore it, worst case the lease
+	// will expire.
+	if err != nil {
+		return 0, nil
+	}
+
+	return leaseId, nil
+}
+
+func RevokeLease(ctx context.Context, etcd *etcdserver.EtcdServer, leaseId int64) error {
+	if leaseId == 0 {
+		return nil
+	}
+
+	_, err := etcd.LeaseRevoke(ctx, &etcdserverpb.LeaseRevokeRequest{ID: leaseId})
+	if err != nil && !errors.Is(err, lease.ErrLeaseNotFound) {
+		return ServerError
+	}
+
+	return nil
+}
+
+// Delete every service registered by a node along with its lease
+func DeleteNodeServices(ctx context.Context, etcd *etcdserver.EtcdServer, node *schemas.NodeSchema) error {
+	err := RevokeNodeLease(ctx, etcd, node.Datacenter, node.Name)
+	if err != nil {
+		return err
+	}
+
+	ops, err := DeleteNodeServicesOps(ctx, etcd, node)
+	if err != nil {
+		return err
+	}
+
+	_, err = etcd.Txn(ctx, &etcdserverpb.TxnRequest{Success: ops})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error deleting node services", "err", err)
+		return ServerError
+	}
+
+	return nil
+}
+
+// Operations deleting the services of a node and its lease key. Services are
+// attached to the lease, but this also cleans up any that were left behind
+// without one.
+func DeleteNodeServicesOps(ctx context.Context, etcd *etcdserver.EtcdServer, node *schemas.NodeSchema) ([]*etcdserverpb.RequestOp, error) {
+	prefix := fmt.Appendf(nil, "%s/%s/%s/", DCServicesNamespace, node.Datacenter, node.Name)
+	res, err := etcd.Range(ctx, &etcdserverpb.RangeRequest{
+		Key:      prefix,
+		RangeEnd: PrefixEnd(prefix),
+		KeysOnly: true,
+	})
+	if err != nil {
+		return nil, ServerError
+	}
+
+	ops := []*etcdserverpb.RequestOp{
+		{
+			Request: &etcdserverpb.RequestOp_RequestDeleteRange{
+				RequestDeleteRange: &etcdserverpb.DeleteRangeRequest{
+					Key:      prefix,
+					RangeEnd: PrefixEnd(prefix),
+				},
+			},
+		},
+		{
+			Request: &etcdserverpb.RequestOp_RequestDeleteRange{
+				RequestDeleteRange: &etcdserverpb.DeleteRangeRequest{
+					Key: fmt.Appendf(nil, "%s/%s/%s", NodesLeasesNamespace, node.Datacenter, node.Name),
+				},
+			},
+		},
+	}
+
+	for _, kv := range res.Kvs {
+		// dcsvc/<datacenter>/<node>/<service>/<instance>
+		parts := strings.Split(string(kv.Key), "/")
+		if len(parts) != 5 {
+			continue
+		}
+
+		svcKey := fmt.Appendf(
+			nil,
+			"%s/%s/%s/%s/%s/%s",
+			ServiceNamespace,
+			parts[3],
+			node.Location,
+			node.Datacenter,
+			node.Name,
+			parts[4],
+		)
+		ops = append(ops, &etcdserverpb.RequestOp{
+			Request: &etcdserverpb.RequestOp_RequestDeleteRange{
+				RequestDeleteRange: &etcdserverpb.DeleteRangeRequest{
+					Key: svcKey,
+				},
+			},
+		})
+	}
+
+	return ops, nil
+}
+
 func PrefixEnd(prefix []byte) []byte {
 	end := make([]byte, len(prefix))
 	copy(end, prefix)
@@ -242,8 +433,17 @@ func PrefixEnd(prefix []byte) []byte {
 	return end
 }
 
-func CreateNodeCrt(state *state.State, datacenter string, node string, implicit string) ([]byte, []byte) {
-	pub, priv, err := ed25519.GenerateKey(nil)
+// Sign a certificate for the public key of a node and record it
+func SignNodeCrt(
+	ctx context.Context,
+	state *state.State,
+	etcd *etcdserver.EtcdServer,
+	datacenter string,
+	node string,
+	implicit string,
+	pub crypto.PublicKey,
+) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
 	if err != nil {
 		panic(err.Error())
 	}
@@ -252,6 +452,7 @@ func CreateNodeCrt(state *state.State, datacenter string, node string, implicit
 	notAfter := notBefore.Add(NodeCertificateExpiry)
 
 	template := x509.Certificate{
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			Organization:       []string{"SSLE Project 01"},
 			OrganizationalUnit: []string{implicit, datacenter},
@@ -266,17 +467,115 @@ func CreateNodeCrt(state *state.State, datacenter string, node string, implicit
 		BasicConstraintsValid: true,
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template, state.AgentCA.Leaf, pub, state.AgentCA.PrivateKey)
+	ca, generation := state.SigningAgentCA()
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, ca.Leaf, pub, ca.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the certificate before handing it out so it can be revoked
+	err = RecordNodeCertificate(ctx, etcd, datacenter, node, &template, generation)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.CertificatesIssued.WithLabelValues(datacenter, implicit).Inc()
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}), nil
+}
+
+func CreateNodeCrt(
+	ctx context.Context,
+	state *state.State,
+	etcd *etcdserver.EtcdServer,
+	datacenter string,
+	node string,
+	implicit string,
+) ([]byte, []byte, error) {
+	pub, priv, err := ed25519.GenerateKey(nil)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	crt, err := SignNodeCrt(ctx, state, etcd, datacenter, node, implicit, pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	keyDer, err := x509.MarshalPKCS8PrivateKey(priv)
 	if err != nil {
 		panic(err.Error())
 	}
 	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
 
-	return crt, key
+	return crt, key, nil
+}
+
+// Sign a certificate for the public key of an operator with the operator CA,
+// only the peer API trusts it
+func SignOperatorCrt(state *state.State, name string, pub crypto.PublicKey) ([]byte, *x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	notBefore := time.Now()
+	notAfter := notBefore.Add(OperatorCertificateExpiry)
+
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"SSLE Project 01"},
+			OrganizationalUnit: []string{OperatorCertificateOU},
+			CommonName:         name,
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	ca := state.SigningOperatorCA()
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, ca.Leaf, pub, ca.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}), &template, nil
+}
+
+// Sign a short lived certificate identifying a service workload, with its
+// SPIFFE id as URI SAN. Its subject can't be mistaken for a node.
+func SignWorkloadCrt(state *state.State, datacenter string, service string, instance string, pub crypto.PublicKey) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	notBefore := time.Now()
+	notAfter := notBefore.Add(WorkloadCertificateExpiry)
+
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"SSLE Project 01"},
+			OrganizationalUnit: []string{WorkloadCertificateOU},
+			CommonName:         instance,
+		},
+		URIs:                  []*url.URL{spiffe.ID(datacenter, service)},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	ca, _ := state.SigningAgentCA()
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, ca.Leaf, pub, ca.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}), nil
 }
@@ -21,6 +21,65 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type HealthStatus int32
+
+const (
+	HealthStatus_HEALTHY  HealthStatus = 1
+	HealthStatus_WARNING  HealthStatus = 2
+	HealthStatus_CRITICAL HealthStatus = 3
+)
+
+// Enum value maps for HealthStatus.
+var (
+	HealthStatus_name = map[int32]string{
+		1: "HEALTHY",
+		2: "WARNING",
+		3: "CRITICAL",
+	}
+	HealthStatus_value = map[string]int32{
+		"HEALTHY":  1,
+		"WARNING":  2,
+		"CRITICAL": 3,
+	}
+)
+
+func (x HealthStatus) Enum() *HealthStatus {
+	p := new(HealthStatus)
+	*p = x
+	return p
+}
+
+func (x HealthStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_api_proto_enumTypes[0].Descriptor()
+}
+
+func (HealthStatus) Type() protoreflect.EnumType {
+	return &file_agent_api_proto_enumTypes[0]
+}
+
+func (x HealthStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *HealthStatus) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = HealthStatus(num)
+	return nil
+}
+
+// Deprecated: Use HealthStatus.Descriptor instead.
+func (HealthStatus) EnumDescriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{0}
+}
+
 type PortSpec struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
@@ -91,6 +150,9 @@ type ServiceSpec struct {
 	Addresses     []string               `protobuf:"bytes,6,rep,name=addresses" json:"addresses,omitempty"`
 	Ports         []*PortSpec            `protobuf:"bytes,7,rep,name=ports" json:"ports,omitempty"`
 	MetricsPort   *uint32                `protobuf:"varint,8,opt,name=metrics_port,json=metricsPort" json:"metrics_port,omitempty"`
+	Health        *HealthStatus          `protobuf:"varint,9,opt,name=health,enum=HealthStatus" json:"health,omitempty"`
+	Tags          []string               `protobuf:"bytes,10,rep,name=tags" json:"tags,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,11,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -181,6 +243,27 @@ func (x *ServiceSpec) GetMetricsPort() uint32 {
 	return 0
 }
 
+func (x *ServiceSpec) GetHealth() HealthStatus {
+	if x != nil && x.Health != nil {
+		return *x.Health
+	}
+	return HealthStatus_HEALTHY
+}
+
+func (x *ServiceSpec) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ServiceSpec) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type HeartbeatRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -255,6 +338,7 @@ func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
 
 type ConfigRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Csr           []byte                 `protobuf:"bytes,1,opt,name=csr" json:"csr,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -289,6 +373,13 @@ func (*ConfigRequest) Descriptor() ([]byte, []int) {
 	return file_agent_api_proto_rawDescGZIP(), []int{4}
 }
 
+func (x *ConfigRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
 type ConfigResponse struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Certificate     []byte                 `protobuf:"bytes,1,opt,name=certificate" json:"certificate,omitempty"`
@@ -296,6 +387,7 @@ type ConfigResponse struct {
 	HeartbeatPeriod *uint32                `protobuf:"varint,3,req,name=heartbeat_period,json=heartbeatPeriod" json:"heartbeat_period,omitempty"`
 	RenewPeriod     *uint64                `protobuf:"varint,4,req,name=renew_period,json=renewPeriod" json:"renew_period,omitempty"`
 	RegistryAddrs   []string               `protobuf:"bytes,5,rep,name=registry_addrs,json=registryAddrs" json:"registry_addrs,omitempty"`
+	CaBundle        []byte                 `protobuf:"bytes,6,opt,name=ca_bundle,json=caBundle" json:"ca_bundle,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -365,20 +457,126 @@ func (x *ConfigResponse) GetRegistryAddrs() []string {
 	return nil
 }
 
-type DiscoverRequest struct {
+func (x *ConfigResponse) GetCaBundle() []byte {
+	if x != nil {
+		return x.CaBundle
+	}
+	return nil
+}
+
+type BootstrapRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Service       *string                `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
-	Location      *string                `protobuf:"bytes,2,opt,name=location" json:"location,omitempty"`
-	Datacenter    *string                `protobuf:"bytes,3,opt,name=datacenter" json:"datacenter,omitempty"`
-	Node          *string                `protobuf:"bytes,4,opt,name=node" json:"node,omitempty"`
-	Instance      *string                `protobuf:"bytes,5,opt,name=instance" json:"instance,omitempty"`
+	Token         *string                `protobuf:"bytes,1,req,name=token" json:"token,omitempty"`
+	Csr           []byte                 `protobuf:"bytes,2,req,name=csr" json:"csr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BootstrapRequest) Reset() {
+	*x = BootstrapRequest{}
+	mi := &file_agent_api_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BootstrapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BootstrapRequest) ProtoMessage() {}
+
+func (x *BootstrapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BootstrapRequest.ProtoReflect.Descriptor instead.
+func (*BootstrapRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BootstrapRequest) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *BootstrapRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
+type BootstrapResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificate   []byte                 `protobuf:"bytes,1,req,name=certificate" json:"certificate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *BootstrapResponse) Reset() {
+	*x = BootstrapResponse{}
+	mi := &file_agent_api_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BootstrapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BootstrapResponse) ProtoMessage() {}
+
+func (x *BootstrapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BootstrapResponse.ProtoReflect.Descriptor instead.
+func (*BootstrapResponse) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BootstrapResponse) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+type DiscoverRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Service          *string                `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
+	Location         *string                `protobuf:"bytes,2,opt,name=location" json:"location,omitempty"`
+	Datacenter       *string                `protobuf:"bytes,3,opt,name=datacenter" json:"datacenter,omitempty"`
+	Node             *string                `protobuf:"bytes,4,opt,name=node" json:"node,omitempty"`
+	Instance         *string                `protobuf:"bytes,5,opt,name=instance" json:"instance,omitempty"`
+	IncludeUnhealthy *bool                  `protobuf:"varint,6,opt,name=include_unhealthy,json=includeUnhealthy" json:"include_unhealthy,omitempty"`
+	Tags             []string               `protobuf:"bytes,7,rep,name=tags" json:"tags,omitempty"`
+	SourceService    *string                `protobuf:"bytes,8,opt,name=source_service,json=sourceService" json:"source_service,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
 func (x *DiscoverRequest) Reset() {
 	*x = DiscoverRequest{}
-	mi := &file_agent_api_proto_msgTypes[6]
+	mi := &file_agent_api_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -390,7 +588,7 @@ func (x *DiscoverRequest) String() string {
 func (*DiscoverRequest) ProtoMessage() {}
 
 func (x *DiscoverRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[6]
+	mi := &file_agent_api_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +601,7 @@ func (x *DiscoverRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscoverRequest.ProtoReflect.Descriptor instead.
 func (*DiscoverRequest) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{6}
+	return file_agent_api_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DiscoverRequest) GetService() string {
@@ -441,6 +639,27 @@ func (x *DiscoverRequest) GetInstance() string {
 	return ""
 }
 
+func (x *DiscoverRequest) GetIncludeUnhealthy() bool {
+	if x != nil && x.IncludeUnhealthy != nil {
+		return *x.IncludeUnhealthy
+	}
+	return false
+}
+
+func (x *DiscoverRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *DiscoverRequest) GetSourceService() string {
+	if x != nil && x.SourceService != nil {
+		return *x.SourceService
+	}
+	return ""
+}
+
 type DiscoverResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Services      []*ServiceSpec         `protobuf:"bytes,1,rep,name=services" json:"services,omitempty"`
@@ -450,7 +669,7 @@ type DiscoverResponse struct {
 
 func (x *DiscoverResponse) Reset() {
 	*x = DiscoverResponse{}
-	mi := &file_agent_api_proto_msgTypes[7]
+	mi := &file_agent_api_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -462,7 +681,7 @@ func (x *DiscoverResponse) String() string {
 func (*DiscoverResponse) ProtoMessage() {}
 
 func (x *DiscoverResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[7]
+	mi := &file_agent_api_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +694,7 @@ func (x *DiscoverResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiscoverResponse.ProtoReflect.Descriptor instead.
 func (*DiscoverResponse) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{7}
+	return file_agent_api_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DiscoverResponse) GetServices() []*ServiceSpec {
@@ -492,13 +711,16 @@ type RegisterServiceRequest struct {
 	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses" json:"addresses,omitempty"`
 	Ports         []*PortSpec            `protobuf:"bytes,4,rep,name=ports" json:"ports,omitempty"`
 	MetricsPort   *uint32                `protobuf:"varint,5,opt,name=metrics_port,json=metricsPort" json:"metrics_port,omitempty"`
+	Health        *HealthStatus          `protobuf:"varint,6,opt,name=health,enum=HealthStatus" json:"health,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags" json:"tags,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,8,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RegisterServiceRequest) Reset() {
 	*x = RegisterServiceRequest{}
-	mi := &file_agent_api_proto_msgTypes[8]
+	mi := &file_agent_api_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -510,7 +732,7 @@ func (x *RegisterServiceRequest) String() string {
 func (*RegisterServiceRequest) ProtoMessage() {}
 
 func (x *RegisterServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[8]
+	mi := &file_agent_api_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -523,7 +745,7 @@ func (x *RegisterServiceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterServiceRequest.ProtoReflect.Descriptor instead.
 func (*RegisterServiceRequest) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{8}
+	return file_agent_api_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RegisterServiceRequest) GetService() string {
@@ -561,6 +783,27 @@ func (x *RegisterServiceRequest) GetMetricsPort() uint32 {
 	return 0
 }
 
+func (x *RegisterServiceRequest) GetHealth() HealthStatus {
+	if x != nil && x.Health != nil {
+		return *x.Health
+	}
+	return HealthStatus_HEALTHY
+}
+
+func (x *RegisterServiceRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *RegisterServiceRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type RegisterServiceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Service       *ServiceSpec           `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
@@ -570,7 +813,7 @@ type RegisterServiceResponse struct {
 
 func (x *RegisterServiceResponse) Reset() {
 	*x = RegisterServiceResponse{}
-	mi := &file_agent_api_proto_msgTypes[9]
+	mi := &file_agent_api_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -582,7 +825,7 @@ func (x *RegisterServiceResponse) String() string {
 func (*RegisterServiceResponse) ProtoMessage() {}
 
 func (x *RegisterServiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[9]
+	mi := &file_agent_api_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -595,7 +838,7 @@ func (x *RegisterServiceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterServiceResponse.ProtoReflect.Descriptor instead.
 func (*RegisterServiceResponse) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{9}
+	return file_agent_api_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RegisterServiceResponse) GetService() *ServiceSpec {
@@ -615,7 +858,7 @@ type DeregisterServiceRequest struct {
 
 func (x *DeregisterServiceRequest) Reset() {
 	*x = DeregisterServiceRequest{}
-	mi := &file_agent_api_proto_msgTypes[10]
+	mi := &file_agent_api_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -627,7 +870,7 @@ func (x *DeregisterServiceRequest) String() string {
 func (*DeregisterServiceRequest) ProtoMessage() {}
 
 func (x *DeregisterServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[10]
+	mi := &file_agent_api_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -640,7 +883,7 @@ func (x *DeregisterServiceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeregisterServiceRequest.ProtoReflect.Descriptor instead.
 func (*DeregisterServiceRequest) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{10}
+	return file_agent_api_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DeregisterServiceRequest) GetService() string {
@@ -665,7 +908,7 @@ type DeregisterServiceResponse struct {
 
 func (x *DeregisterServiceResponse) Reset() {
 	*x = DeregisterServiceResponse{}
-	mi := &file_agent_api_proto_msgTypes[11]
+	mi := &file_agent_api_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -677,7 +920,7 @@ func (x *DeregisterServiceResponse) String() string {
 func (*DeregisterServiceResponse) ProtoMessage() {}
 
 func (x *DeregisterServiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[11]
+	mi := &file_agent_api_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -690,30 +933,33 @@ func (x *DeregisterServiceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeregisterServiceResponse.ProtoReflect.Descriptor instead.
 func (*DeregisterServiceResponse) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{11}
+	return file_agent_api_proto_rawDescGZIP(), []int{13}
 }
 
-type ResetRequest struct {
+type UpdateHealthRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *string                `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
+	Instance      *string                `protobuf:"bytes,2,req,name=instance" json:"instance,omitempty"`
+	Health        *HealthStatus          `protobuf:"varint,3,req,name=health,enum=HealthStatus" json:"health,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResetRequest) Reset() {
-	*x = ResetRequest{}
-	mi := &file_agent_api_proto_msgTypes[12]
+func (x *UpdateHealthRequest) Reset() {
+	*x = UpdateHealthRequest{}
+	mi := &file_agent_api_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResetRequest) String() string {
+func (x *UpdateHealthRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetRequest) ProtoMessage() {}
+func (*UpdateHealthRequest) ProtoMessage() {}
 
-func (x *ResetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[12]
+func (x *UpdateHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -724,32 +970,53 @@ func (x *ResetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
-func (*ResetRequest) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use UpdateHealthRequest.ProtoReflect.Descriptor instead.
+func (*UpdateHealthRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{14}
 }
 
-type ResetResponse struct {
+func (x *UpdateHealthRequest) GetService() string {
+	if x != nil && x.Service != nil {
+		return *x.Service
+	}
+	return ""
+}
+
+func (x *UpdateHealthRequest) GetInstance() string {
+	if x != nil && x.Instance != nil {
+		return *x.Instance
+	}
+	return ""
+}
+
+func (x *UpdateHealthRequest) GetHealth() HealthStatus {
+	if x != nil && x.Health != nil {
+		return *x.Health
+	}
+	return HealthStatus_HEALTHY
+}
+
+type UpdateHealthResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResetResponse) Reset() {
-	*x = ResetResponse{}
-	mi := &file_agent_api_proto_msgTypes[13]
+func (x *UpdateHealthResponse) Reset() {
+	*x = UpdateHealthResponse{}
+	mi := &file_agent_api_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResetResponse) String() string {
+func (x *UpdateHealthResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetResponse) ProtoMessage() {}
+func (*UpdateHealthResponse) ProtoMessage() {}
 
-func (x *ResetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[13]
+func (x *UpdateHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -760,32 +1027,32 @@ func (x *ResetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
-func (*ResetResponse) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use UpdateHealthResponse.ProtoReflect.Descriptor instead.
+func (*UpdateHealthResponse) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{15}
 }
 
-type GetDatacenterServicesRequest struct {
+type ResetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDatacenterServicesRequest) Reset() {
-	*x = GetDatacenterServicesRequest{}
-	mi := &file_agent_api_proto_msgTypes[14]
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	mi := &file_agent_api_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDatacenterServicesRequest) String() string {
+func (x *ResetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDatacenterServicesRequest) ProtoMessage() {}
+func (*ResetRequest) ProtoMessage() {}
 
-func (x *GetDatacenterServicesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[14]
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -796,21 +1063,484 @@ func (x *GetDatacenterServicesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDatacenterServicesRequest.ProtoReflect.Descriptor instead.
-func (*GetDatacenterServicesRequest) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{16}
 }
 
-type GetDatacenterServicesResponse struct {
+type ResetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Services      []*ServiceSpec         `protobuf:"bytes,1,rep,name=services" json:"services,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDatacenterServicesResponse) Reset() {
-	*x = GetDatacenterServicesResponse{}
-	mi := &file_agent_api_proto_msgTypes[15]
+func (x *ResetResponse) Reset() {
+	*x = ResetResponse{}
+	mi := &file_agent_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetResponse) ProtoMessage() {}
+
+func (x *ResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{17}
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *string                `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
+	Location      *string                `protobuf:"bytes,2,opt,name=location" json:"location,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,3,opt,name=datacenter" json:"datacenter,omitempty"`
+	Node          *string                `protobuf:"bytes,4,opt,name=node" json:"node,omitempty"`
+	StartRevision *int64                 `protobuf:"varint,5,opt,name=start_revision,json=startRevision" json:"start_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_agent_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchRequest) GetService() string {
+	if x != nil && x.Service != nil {
+		return *x.Service
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetNode() string {
+	if x != nil && x.Node != nil {
+		return *x.Node
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetStartRevision() int64 {
+	if x != nil && x.StartRevision != nil {
+		return *x.StartRevision
+	}
+	return 0
+}
+
+type WatchResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Revision *int64                 `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
+	// Types that are valid to be assigned to Notification:
+	//
+	//	*WatchResponse_Update
+	//	*WatchResponse_Delete
+	Notification  isWatchResponse_Notification `protobuf_oneof:"notification"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	mi := &file_agent_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchResponse) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
+func (x *WatchResponse) GetNotification() isWatchResponse_Notification {
+	if x != nil {
+		return x.Notification
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetUpdate() *WatchServiceUpdate {
+	if x != nil {
+		if x, ok := x.Notification.(*WatchResponse_Update); ok {
+			return x.Update
+		}
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetDelete() *WatchServiceDelete {
+	if x != nil {
+		if x, ok := x.Notification.(*WatchResponse_Delete); ok {
+			return x.Delete
+		}
+	}
+	return nil
+}
+
+type isWatchResponse_Notification interface {
+	isWatchResponse_Notification()
+}
+
+type WatchResponse_Update struct {
+	Update *WatchServiceUpdate `protobuf:"bytes,1,opt,name=update,oneof"`
+}
+
+type WatchResponse_Delete struct {
+	Delete *WatchServiceDelete `protobuf:"bytes,2,opt,name=delete,oneof"`
+}
+
+func (*WatchResponse_Update) isWatchResponse_Notification() {}
+
+func (*WatchResponse_Delete) isWatchResponse_Notification() {}
+
+type SignWorkloadCertificateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       *string                `protobuf:"bytes,1,req,name=service" json:"service,omitempty"`
+	Instance      *string                `protobuf:"bytes,2,req,name=instance" json:"instance,omitempty"`
+	Csr           []byte                 `protobuf:"bytes,3,req,name=csr" json:"csr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignWorkloadCertificateRequest) Reset() {
+	*x = SignWorkloadCertificateRequest{}
+	mi := &file_agent_api_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignWorkloadCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignWorkloadCertificateRequest) ProtoMessage() {}
+
+func (x *SignWorkloadCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignWorkloadCertificateRequest.ProtoReflect.Descriptor instead.
+func (*SignWorkloadCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SignWorkloadCertificateRequest) GetService() string {
+	if x != nil && x.Service != nil {
+		return *x.Service
+	}
+	return ""
+}
+
+func (x *SignWorkloadCertificateRequest) GetInstance() string {
+	if x != nil && x.Instance != nil {
+		return *x.Instance
+	}
+	return ""
+}
+
+func (x *SignWorkloadCertificateRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
+type SignWorkloadCertificateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificate   []byte                 `protobuf:"bytes,1,req,name=certificate" json:"certificate,omitempty"`
+	CaBundle      []byte                 `protobuf:"bytes,2,req,name=ca_bundle,json=caBundle" json:"ca_bundle,omitempty"`
+	RenewPeriod   *uint64                `protobuf:"varint,3,req,name=renew_period,json=renewPeriod" json:"renew_period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignWorkloadCertificateResponse) Reset() {
+	*x = SignWorkloadCertificateResponse{}
+	mi := &file_agent_api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignWorkloadCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignWorkloadCertificateResponse) ProtoMessage() {}
+
+func (x *SignWorkloadCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignWorkloadCertificateResponse.ProtoReflect.Descriptor instead.
+func (*SignWorkloadCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SignWorkloadCertificateResponse) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+func (x *SignWorkloadCertificateResponse) GetCaBundle() []byte {
+	if x != nil {
+		return x.CaBundle
+	}
+	return nil
+}
+
+func (x *SignWorkloadCertificateResponse) GetRenewPeriod() uint64 {
+	if x != nil && x.RenewPeriod != nil {
+		return *x.RenewPeriod
+	}
+	return 0
+}
+
+type AuthorizeConnectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *string                `protobuf:"bytes,1,req,name=source" json:"source,omitempty"`
+	Destination   *string                `protobuf:"bytes,2,req,name=destination" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeConnectionRequest) Reset() {
+	*x = AuthorizeConnectionRequest{}
+	mi := &file_agent_api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeConnectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeConnectionRequest) ProtoMessage() {}
+
+func (x *AuthorizeConnectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeConnectionRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizeConnectionRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AuthorizeConnectionRequest) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
+}
+
+func (x *AuthorizeConnectionRequest) GetDestination() string {
+	if x != nil && x.Destination != nil {
+		return *x.Destination
+	}
+	return ""
+}
+
+type AuthorizeConnectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Allowed       *bool                  `protobuf:"varint,1,req,name=allowed" json:"allowed,omitempty"`
+	Intention     *string                `protobuf:"bytes,2,opt,name=intention" json:"intention,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeConnectionResponse) Reset() {
+	*x = AuthorizeConnectionResponse{}
+	mi := &file_agent_api_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeConnectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeConnectionResponse) ProtoMessage() {}
+
+func (x *AuthorizeConnectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeConnectionResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizeConnectionResponse) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AuthorizeConnectionResponse) GetAllowed() bool {
+	if x != nil && x.Allowed != nil {
+		return *x.Allowed
+	}
+	return false
+}
+
+func (x *AuthorizeConnectionResponse) GetIntention() string {
+	if x != nil && x.Intention != nil {
+		return *x.Intention
+	}
+	return ""
+}
+
+type GetDatacenterServicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatacenterServicesRequest) Reset() {
+	*x = GetDatacenterServicesRequest{}
+	mi := &file_agent_api_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDatacenterServicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDatacenterServicesRequest) ProtoMessage() {}
+
+func (x *GetDatacenterServicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_api_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDatacenterServicesRequest.ProtoReflect.Descriptor instead.
+func (*GetDatacenterServicesRequest) Descriptor() ([]byte, []int) {
+	return file_agent_api_proto_rawDescGZIP(), []int{24}
+}
+
+type GetDatacenterServicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Services      []*ServiceSpec         `protobuf:"bytes,1,rep,name=services" json:"services,omitempty"`
+	Revision      *int64                 `protobuf:"varint,2,opt,name=revision" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDatacenterServicesResponse) Reset() {
+	*x = GetDatacenterServicesResponse{}
+	mi := &file_agent_api_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -822,7 +1552,7 @@ func (x *GetDatacenterServicesResponse) String() string {
 func (*GetDatacenterServicesResponse) ProtoMessage() {}
 
 func (x *GetDatacenterServicesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[15]
+	mi := &file_agent_api_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -835,7 +1565,7 @@ func (x *GetDatacenterServicesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDatacenterServicesResponse.ProtoReflect.Descriptor instead.
 func (*GetDatacenterServicesResponse) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{15}
+	return file_agent_api_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *GetDatacenterServicesResponse) GetServices() []*ServiceSpec {
@@ -845,15 +1575,23 @@ func (x *GetDatacenterServicesResponse) GetServices() []*ServiceSpec {
 	return nil
 }
 
+func (x *GetDatacenterServicesResponse) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
 type WatchDatacenterServicesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartRevision *int64                 `protobuf:"varint,1,opt,name=start_revision,json=startRevision" json:"start_revision,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WatchDatacenterServicesRequest) Reset() {
 	*x = WatchDatacenterServicesRequest{}
-	mi := &file_agent_api_proto_msgTypes[16]
+	mi := &file_agent_api_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -865,7 +1603,7 @@ func (x *WatchDatacenterServicesRequest) String() string {
 func (*WatchDatacenterServicesRequest) ProtoMessage() {}
 
 func (x *WatchDatacenterServicesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[16]
+	mi := &file_agent_api_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -878,7 +1616,14 @@ func (x *WatchDatacenterServicesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchDatacenterServicesRequest.ProtoReflect.Descriptor instead.
 func (*WatchDatacenterServicesRequest) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{16}
+	return file_agent_api_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchDatacenterServicesRequest) GetStartRevision() int64 {
+	if x != nil && x.StartRevision != nil {
+		return *x.StartRevision
+	}
+	return 0
 }
 
 type WatchServiceUpdate struct {
@@ -890,7 +1635,7 @@ type WatchServiceUpdate struct {
 
 func (x *WatchServiceUpdate) Reset() {
 	*x = WatchServiceUpdate{}
-	mi := &file_agent_api_proto_msgTypes[17]
+	mi := &file_agent_api_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -902,7 +1647,7 @@ func (x *WatchServiceUpdate) String() string {
 func (*WatchServiceUpdate) ProtoMessage() {}
 
 func (x *WatchServiceUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[17]
+	mi := &file_agent_api_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -915,7 +1660,7 @@ func (x *WatchServiceUpdate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchServiceUpdate.ProtoReflect.Descriptor instead.
 func (*WatchServiceUpdate) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{17}
+	return file_agent_api_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *WatchServiceUpdate) GetService() *ServiceSpec {
@@ -930,13 +1675,15 @@ type WatchServiceDelete struct {
 	ServiceName   *string                `protobuf:"bytes,1,req,name=service_name,json=serviceName" json:"service_name,omitempty"`
 	Node          *string                `protobuf:"bytes,2,req,name=node" json:"node,omitempty"`
 	Instance      *string                `protobuf:"bytes,3,req,name=instance" json:"instance,omitempty"`
+	Location      *string                `protobuf:"bytes,4,opt,name=location" json:"location,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,5,opt,name=datacenter" json:"datacenter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WatchServiceDelete) Reset() {
 	*x = WatchServiceDelete{}
-	mi := &file_agent_api_proto_msgTypes[18]
+	mi := &file_agent_api_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -948,7 +1695,7 @@ func (x *WatchServiceDelete) String() string {
 func (*WatchServiceDelete) ProtoMessage() {}
 
 func (x *WatchServiceDelete) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[18]
+	mi := &file_agent_api_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -961,7 +1708,7 @@ func (x *WatchServiceDelete) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchServiceDelete.ProtoReflect.Descriptor instead.
 func (*WatchServiceDelete) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{18}
+	return file_agent_api_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *WatchServiceDelete) GetServiceName() string {
@@ -985,8 +1732,23 @@ func (x *WatchServiceDelete) GetInstance() string {
 	return ""
 }
 
+func (x *WatchServiceDelete) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *WatchServiceDelete) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
 type WatchDatacenterServicesResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Revision *int64                 `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
 	// Types that are valid to be assigned to Notification:
 	//
 	//	*WatchDatacenterServicesResponse_Update
@@ -998,7 +1760,7 @@ type WatchDatacenterServicesResponse struct {
 
 func (x *WatchDatacenterServicesResponse) Reset() {
 	*x = WatchDatacenterServicesResponse{}
-	mi := &file_agent_api_proto_msgTypes[19]
+	mi := &file_agent_api_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1010,7 +1772,7 @@ func (x *WatchDatacenterServicesResponse) String() string {
 func (*WatchDatacenterServicesResponse) ProtoMessage() {}
 
 func (x *WatchDatacenterServicesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_api_proto_msgTypes[19]
+	mi := &file_agent_api_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1023,7 +1785,14 @@ func (x *WatchDatacenterServicesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchDatacenterServicesResponse.ProtoReflect.Descriptor instead.
 func (*WatchDatacenterServicesResponse) Descriptor() ([]byte, []int) {
-	return file_agent_api_proto_rawDescGZIP(), []int{19}
+	return file_agent_api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchDatacenterServicesResponse) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
 }
 
 func (x *WatchDatacenterServicesResponse) GetNotification() isWatchDatacenterServicesResponse_Notification {
@@ -1075,7 +1844,7 @@ const file_agent_api_proto_rawDesc = "" +
 	"\bPortSpec\x12\x12\n" +
 	"\x04name\x18\x01 \x02(\tR\x04name\x12\x12\n" +
 	"\x04port\x18\x02 \x02(\rR\x04port\x12\x1a\n" +
-	"\bprotocol\x18\x03 \x01(\tR\bprotocol\"\xfe\x01\n" +
+	"\bprotocol\x18\x03 \x01(\tR\bprotocol\"\xae\x03\n" +
 	"\vServiceSpec\x12!\n" +
 	"\fservice_name\x18\x01 \x02(\tR\vserviceName\x12\x1a\n" +
 	"\binstance\x18\x02 \x02(\tR\binstance\x12\x1a\n" +
@@ -1086,16 +1855,30 @@ const file_agent_api_proto_rawDesc = "" +
 	"\x04node\x18\x05 \x02(\tR\x04node\x12\x1c\n" +
 	"\taddresses\x18\x06 \x03(\tR\taddresses\x12\x1f\n" +
 	"\x05ports\x18\a \x03(\v2\t.PortSpecR\x05ports\x12!\n" +
-	"\fmetrics_port\x18\b \x01(\rR\vmetricsPort\"\x12\n" +
+	"\fmetrics_port\x18\b \x01(\rR\vmetricsPort\x12%\n" +
+	"\x06health\x18\t \x01(\x0e2\r.HealthStatusR\x06health\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x126\n" +
+	"\bmetadata\x18\v \x03(\v2\x1a.ServiceSpec.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x12\n" +
 	"\x10HeartbeatRequest\"\x13\n" +
-	"\x11HeartbeatResponse\"\x0f\n" +
-	"\rConfigRequest\"\xb9\x01\n" +
+	"\x11HeartbeatResponse\"!\n" +
+	"\rConfigRequest\x12\x10\n" +
+	"\x03csr\x18\x01 \x01(\fR\x03csr\"\xd6\x01\n" +
 	"\x0eConfigResponse\x12 \n" +
 	"\vcertificate\x18\x01 \x01(\fR\vcertificate\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\fR\x03key\x12)\n" +
 	"\x10heartbeat_period\x18\x03 \x02(\rR\x0fheartbeatPeriod\x12!\n" +
 	"\frenew_period\x18\x04 \x02(\x04R\vrenewPeriod\x12%\n" +
-	"\x0eregistry_addrs\x18\x05 \x03(\tR\rregistryAddrs\"\x97\x01\n" +
+	"\x0eregistry_addrs\x18\x05 \x03(\tR\rregistryAddrs\x12\x1b\n" +
+	"\tca_bundle\x18\x06 \x01(\fR\bcaBundle\":\n" +
+	"\x10BootstrapRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x02(\tR\x05token\x12\x10\n" +
+	"\x03csr\x18\x02 \x02(\fR\x03csr\"5\n" +
+	"\x11BootstrapResponse\x12 \n" +
+	"\vcertificate\x18\x01 \x02(\fR\vcertificate\"\xff\x01\n" +
 	"\x0fDiscoverRequest\x12\x18\n" +
 	"\aservice\x18\x01 \x02(\tR\aservice\x12\x1a\n" +
 	"\blocation\x18\x02 \x01(\tR\blocation\x12\x1e\n" +
@@ -1103,46 +1886,103 @@ const file_agent_api_proto_rawDesc = "" +
 	"datacenter\x18\x03 \x01(\tR\n" +
 	"datacenter\x12\x12\n" +
 	"\x04node\x18\x04 \x01(\tR\x04node\x12\x1a\n" +
-	"\binstance\x18\x05 \x01(\tR\binstance\"<\n" +
+	"\binstance\x18\x05 \x01(\tR\binstance\x12+\n" +
+	"\x11include_unhealthy\x18\x06 \x01(\bR\x10includeUnhealthy\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12%\n" +
+	"\x0esource_service\x18\b \x01(\tR\rsourceService\"<\n" +
 	"\x10DiscoverResponse\x12(\n" +
-	"\bservices\x18\x01 \x03(\v2\f.ServiceSpecR\bservices\"\xb0\x01\n" +
+	"\bservices\x18\x01 \x03(\v2\f.ServiceSpecR\bservices\"\xeb\x02\n" +
 	"\x16RegisterServiceRequest\x12\x18\n" +
 	"\aservice\x18\x01 \x02(\tR\aservice\x12\x1a\n" +
 	"\binstance\x18\x02 \x02(\tR\binstance\x12\x1c\n" +
 	"\taddresses\x18\x03 \x03(\tR\taddresses\x12\x1f\n" +
 	"\x05ports\x18\x04 \x03(\v2\t.PortSpecR\x05ports\x12!\n" +
-	"\fmetrics_port\x18\x05 \x01(\rR\vmetricsPort\"A\n" +
+	"\fmetrics_port\x18\x05 \x01(\rR\vmetricsPort\x12%\n" +
+	"\x06health\x18\x06 \x01(\x0e2\r.HealthStatusR\x06health\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12A\n" +
+	"\bmetadata\x18\b \x03(\v2%.RegisterServiceRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"A\n" +
 	"\x17RegisterServiceResponse\x12&\n" +
 	"\aservice\x18\x01 \x02(\v2\f.ServiceSpecR\aservice\"P\n" +
 	"\x18DeregisterServiceRequest\x12\x18\n" +
 	"\aservice\x18\x01 \x02(\tR\aservice\x12\x1a\n" +
 	"\binstance\x18\x02 \x02(\tR\binstance\"\x1b\n" +
-	"\x19DeregisterServiceResponse\"\x0e\n" +
+	"\x19DeregisterServiceResponse\"r\n" +
+	"\x13UpdateHealthRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x02(\tR\aservice\x12\x1a\n" +
+	"\binstance\x18\x02 \x02(\tR\binstance\x12%\n" +
+	"\x06health\x18\x03 \x02(\x0e2\r.HealthStatusR\x06health\"\x16\n" +
+	"\x14UpdateHealthResponse\"\x0e\n" +
 	"\fResetRequest\"\x0f\n" +
-	"\rResetResponse\"\x1e\n" +
-	"\x1cGetDatacenterServicesRequest\"I\n" +
+	"\rResetResponse\"\x9f\x01\n" +
+	"\fWatchRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x02(\tR\aservice\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x03 \x01(\tR\n" +
+	"datacenter\x12\x12\n" +
+	"\x04node\x18\x04 \x01(\tR\x04node\x12%\n" +
+	"\x0estart_revision\x18\x05 \x01(\x03R\rstartRevision\"\x99\x01\n" +
+	"\rWatchResponse\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\x12-\n" +
+	"\x06update\x18\x01 \x01(\v2\x13.WatchServiceUpdateH\x00R\x06update\x12-\n" +
+	"\x06delete\x18\x02 \x01(\v2\x13.WatchServiceDeleteH\x00R\x06deleteB\x0e\n" +
+	"\fnotification\"h\n" +
+	"\x1eSignWorkloadCertificateRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x02(\tR\aservice\x12\x1a\n" +
+	"\binstance\x18\x02 \x02(\tR\binstance\x12\x10\n" +
+	"\x03csr\x18\x03 \x02(\fR\x03csr\"\x83\x01\n" +
+	"\x1fSignWorkloadCertificateResponse\x12 \n" +
+	"\vcertificate\x18\x01 \x02(\fR\vcertificate\x12\x1b\n" +
+	"\tca_bundle\x18\x02 \x02(\fR\bcaBundle\x12!\n" +
+	"\frenew_period\x18\x03 \x02(\x04R\vrenewPeriod\"V\n" +
+	"\x1aAuthorizeConnectionRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x02(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x02(\tR\vdestination\"U\n" +
+	"\x1bAuthorizeConnectionResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x02(\bR\aallowed\x12\x1c\n" +
+	"\tintention\x18\x02 \x01(\tR\tintention\"\x1e\n" +
+	"\x1cGetDatacenterServicesRequest\"e\n" +
 	"\x1dGetDatacenterServicesResponse\x12(\n" +
-	"\bservices\x18\x01 \x03(\v2\f.ServiceSpecR\bservices\" \n" +
-	"\x1eWatchDatacenterServicesRequest\"<\n" +
+	"\bservices\x18\x01 \x03(\v2\f.ServiceSpecR\bservices\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x03R\brevision\"G\n" +
+	"\x1eWatchDatacenterServicesRequest\x12%\n" +
+	"\x0estart_revision\x18\x01 \x01(\x03R\rstartRevision\"<\n" +
 	"\x12WatchServiceUpdate\x12&\n" +
-	"\aservice\x18\x01 \x02(\v2\f.ServiceSpecR\aservice\"g\n" +
+	"\aservice\x18\x01 \x02(\v2\f.ServiceSpecR\aservice\"\xa3\x01\n" +
 	"\x12WatchServiceDelete\x12!\n" +
 	"\fservice_name\x18\x01 \x02(\tR\vserviceName\x12\x12\n" +
 	"\x04node\x18\x02 \x02(\tR\x04node\x12\x1a\n" +
-	"\binstance\x18\x03 \x02(\tR\binstance\"\x8f\x01\n" +
-	"\x1fWatchDatacenterServicesResponse\x12-\n" +
+	"\binstance\x18\x03 \x02(\tR\binstance\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x05 \x01(\tR\n" +
+	"datacenter\"\xab\x01\n" +
+	"\x1fWatchDatacenterServicesResponse\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x03R\brevision\x12-\n" +
 	"\x06update\x18\x01 \x01(\v2\x13.WatchServiceUpdateH\x00R\x06update\x12-\n" +
 	"\x06delete\x18\x02 \x01(\v2\x13.WatchServiceDeleteH\x00R\x06deleteB\x0e\n" +
-	"\fnotification2l\n" +
+	"\fnotification*6\n" +
+	"\fHealthStatus\x12\v\n" +
+	"\aHEALTHY\x10\x01\x12\v\n" +
+	"\aWARNING\x10\x02\x12\f\n" +
+	"\bCRITICAL\x10\x032\xa2\x01\n" +
 	"\aNodeAPI\x124\n" +
 	"\tHeartbeat\x12\x11.HeartbeatRequest\x1a\x12.HeartbeatResponse\"\x00\x12+\n" +
-	"\x06Config\x12\x0e.ConfigRequest\x1a\x0f.ConfigResponse\"\x002\xef\x01\n" +
+	"\x06Config\x12\x0e.ConfigRequest\x1a\x0f.ConfigResponse\"\x00\x124\n" +
+	"\tBootstrap\x12\x11.BootstrapRequest\x1a\x12.BootstrapResponse\"\x002\x8e\x04\n" +
 	"\bAgentAPI\x121\n" +
 	"\bDiscover\x12\x10.DiscoverRequest\x1a\x11.DiscoverResponse\"\x00\x12?\n" +
 	"\bRegister\x12\x17.RegisterServiceRequest\x1a\x18.RegisterServiceResponse\"\x00\x12E\n" +
 	"\n" +
-	"Deregister\x12\x19.DeregisterServiceRequest\x1a\x1a.DeregisterServiceResponse\"\x00\x12(\n" +
-	"\x05Reset\x12\r.ResetRequest\x1a\x0e.ResetResponse\"\x002\xc9\x01\n" +
+	"Deregister\x12\x19.DeregisterServiceRequest\x1a\x1a.DeregisterServiceResponse\"\x00\x12=\n" +
+	"\fUpdateHealth\x12\x14.UpdateHealthRequest\x1a\x15.UpdateHealthResponse\"\x00\x12(\n" +
+	"\x05Reset\x12\r.ResetRequest\x1a\x0e.ResetResponse\"\x00\x12*\n" +
+	"\x05Watch\x12\r.WatchRequest\x1a\x0e.WatchResponse\"\x000\x01\x12^\n" +
+	"\x17SignWorkloadCertificate\x12\x1f.SignWorkloadCertificateRequest\x1a .SignWorkloadCertificateResponse\"\x00\x12R\n" +
+	"\x13AuthorizeConnection\x12\x1b.AuthorizeConnectionRequest\x1a\x1c.AuthorizeConnectionResponse\"\x002\xc9\x01\n" +
 	"\vObserverAPI\x12X\n" +
 	"\x15GetDatacenterServices\x12\x1d.GetDatacenterServicesRequest\x1a\x1e.GetDatacenterServicesResponse\"\x00\x12`\n" +
 	"\x17WatchDatacenterServices\x12\x1f.WatchDatacenterServicesRequest\x1a .WatchDatacenterServicesResponse\"\x000\x01B\x0fZ\rssle/services"
@@ -1159,59 +1999,90 @@ func file_agent_api_proto_rawDescGZIP() []byte {
 	return file_agent_api_proto_rawDescData
 }
 
-var file_agent_api_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_agent_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_agent_api_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_agent_api_proto_goTypes = []any{
-	(*PortSpec)(nil),                        // 0: PortSpec
-	(*ServiceSpec)(nil),                     // 1: ServiceSpec
-	(*HeartbeatRequest)(nil),                // 2: HeartbeatRequest
-	(*HeartbeatResponse)(nil),               // 3: HeartbeatResponse
-	(*ConfigRequest)(nil),                   // 4: ConfigRequest
-	(*ConfigResponse)(nil),                  // 5: ConfigResponse
-	(*DiscoverRequest)(nil),                 // 6: DiscoverRequest
-	(*DiscoverResponse)(nil),                // 7: DiscoverResponse
-	(*RegisterServiceRequest)(nil),          // 8: RegisterServiceRequest
-	(*RegisterServiceResponse)(nil),         // 9: RegisterServiceResponse
-	(*DeregisterServiceRequest)(nil),        // 10: DeregisterServiceRequest
-	(*DeregisterServiceResponse)(nil),       // 11: DeregisterServiceResponse
-	(*ResetRequest)(nil),                    // 12: ResetRequest
-	(*ResetResponse)(nil),                   // 13: ResetResponse
-	(*GetDatacenterServicesRequest)(nil),    // 14: GetDatacenterServicesRequest
-	(*GetDatacenterServicesResponse)(nil),   // 15: GetDatacenterServicesResponse
-	(*WatchDatacenterServicesRequest)(nil),  // 16: WatchDatacenterServicesRequest
-	(*WatchServiceUpdate)(nil),              // 17: WatchServiceUpdate
-	(*WatchServiceDelete)(nil),              // 18: WatchServiceDelete
-	(*WatchDatacenterServicesResponse)(nil), // 19: WatchDatacenterServicesResponse
+	(HealthStatus)(0),                       // 0: HealthStatus
+	(*PortSpec)(nil),                        // 1: PortSpec
+	(*ServiceSpec)(nil),                     // 2: ServiceSpec
+	(*HeartbeatRequest)(nil),                // 3: HeartbeatRequest
+	(*HeartbeatResponse)(nil),               // 4: HeartbeatResponse
+	(*ConfigRequest)(nil),                   // 5: ConfigRequest
+	(*ConfigResponse)(nil),                  // 6: ConfigResponse
+	(*BootstrapRequest)(nil),                // 7: BootstrapRequest
+	(*BootstrapResponse)(nil),               // 8: BootstrapResponse
+	(*DiscoverRequest)(nil),                 // 9: DiscoverRequest
+	(*DiscoverResponse)(nil),                // 10: DiscoverResponse
+	(*RegisterServiceRequest)(nil),          // 11: RegisterServiceRequest
+	(*RegisterServiceResponse)(nil),         // 12: RegisterServiceResponse
+	(*DeregisterServiceRequest)(nil),        // 13: DeregisterServiceRequest
+	(*DeregisterServiceResponse)(nil),       // 14: DeregisterServiceResponse
+	(*UpdateHealthRequest)(nil),             // 15: UpdateHealthRequest
+	(*UpdateHealthResponse)(nil),            // 16: UpdateHealthResponse
+	(*ResetRequest)(nil),                    // 17: ResetRequest
+	(*ResetResponse)(nil),                   // 18: ResetResponse
+	(*WatchRequest)(nil),                    // 19: WatchRequest
+	(*WatchResponse)(nil),                   // 20: WatchResponse
+	(*SignWorkloadCertificateRequest)(nil),  // 21: SignWorkloadCertificateRequest
+	(*SignWorkloadCertificateResponse)(nil), // 22: SignWorkloadCertificateResponse
+	(*AuthorizeConnectionRequest)(nil),      // 23: AuthorizeConnectionRequest
+	(*AuthorizeConnectionResponse)(nil),     // 24: AuthorizeConnectionResponse
+	(*GetDatacenterServicesRequest)(nil),    // 25: GetDatacenterServicesRequest
+	(*GetDatacenterServicesResponse)(nil),   // 26: GetDatacenterServicesResponse
+	(*WatchDatacenterServicesRequest)(nil),  // 27: WatchDatacenterServicesRequest
+	(*WatchServiceUpdate)(nil),              // 28: WatchServiceUpdate
+	(*WatchServiceDelete)(nil),              // 29: WatchServiceDelete
+	(*WatchDatacenterServicesResponse)(nil), // 30: WatchDatacenterServicesResponse
+	nil,                                     // 31: ServiceSpec.MetadataEntry
+	nil,                                     // 32: RegisterServiceRequest.MetadataEntry
 }
 var file_agent_api_proto_depIdxs = []int32{
-	0,  // 0: ServiceSpec.ports:type_name -> PortSpec
-	1,  // 1: DiscoverResponse.services:type_name -> ServiceSpec
-	0,  // 2: RegisterServiceRequest.ports:type_name -> PortSpec
-	1,  // 3: RegisterServiceResponse.service:type_name -> ServiceSpec
-	1,  // 4: GetDatacenterServicesResponse.services:type_name -> ServiceSpec
-	1,  // 5: WatchServiceUpdate.service:type_name -> ServiceSpec
-	17, // 6: WatchDatacenterServicesResponse.update:type_name -> WatchServiceUpdate
-	18, // 7: WatchDatacenterServicesResponse.delete:type_name -> WatchServiceDelete
-	2,  // 8: NodeAPI.Heartbeat:input_type -> HeartbeatRequest
-	4,  // 9: NodeAPI.Config:input_type -> ConfigRequest
-	6,  // 10: AgentAPI.Discover:input_type -> DiscoverRequest
-	8,  // 11: AgentAPI.Register:input_type -> RegisterServiceRequest
-	10, // 12: AgentAPI.Deregister:input_type -> DeregisterServiceRequest
-	12, // 13: AgentAPI.Reset:input_type -> ResetRequest
-	14, // 14: ObserverAPI.GetDatacenterServices:input_type -> GetDatacenterServicesRequest
-	16, // 15: ObserverAPI.WatchDatacenterServices:input_type -> WatchDatacenterServicesRequest
-	3,  // 16: NodeAPI.Heartbeat:output_type -> HeartbeatResponse
-	5,  // 17: NodeAPI.Config:output_type -> ConfigResponse
-	7,  // 18: AgentAPI.Discover:output_type -> DiscoverResponse
-	9,  // 19: AgentAPI.Register:output_type -> RegisterServiceResponse
-	11, // 20: AgentAPI.Deregister:output_type -> DeregisterServiceResponse
-	13, // 21: AgentAPI.Reset:output_type -> ResetResponse
-	15, // 22: ObserverAPI.GetDatacenterServices:output_type -> GetDatacenterServicesResponse
-	19, // 23: ObserverAPI.WatchDatacenterServices:output_type -> WatchDatacenterServicesResponse
-	16, // [16:24] is the sub-list for method output_type
-	8,  // [8:16] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	1,  // 0: ServiceSpec.ports:type_name -> PortSpec
+	0,  // 1: ServiceSpec.health:type_name -> HealthStatus
+	31, // 2: ServiceSpec.metadata:type_name -> ServiceSpec.MetadataEntry
+	2,  // 3: DiscoverResponse.services:type_name -> ServiceSpec
+	1,  // 4: RegisterServiceRequest.ports:type_name -> PortSpec
+	0,  // 5: RegisterServiceRequest.health:type_name -> HealthStatus
+	32, // 6: RegisterServiceRequest.metadata:type_name -> RegisterServiceRequest.MetadataEntry
+	2,  // 7: RegisterServiceResponse.service:type_name -> ServiceSpec
+	0,  // 8: UpdateHealthRequest.health:type_name -> HealthStatus
+	28, // 9: WatchResponse.update:type_name -> WatchServiceUpdate
+	29, // 10: WatchResponse.delete:type_name -> WatchServiceDelete
+	2,  // 11: GetDatacenterServicesResponse.services:type_name -> ServiceSpec
+	2,  // 12: WatchServiceUpdate.service:type_name -> ServiceSpec
+	28, // 13: WatchDatacenterServicesResponse.update:type_name -> WatchServiceUpdate
+	29, // 14: WatchDatacenterServicesResponse.delete:type_name -> WatchServiceDelete
+	3,  // 15: NodeAPI.Heartbeat:input_type -> HeartbeatRequest
+	5,  // 16: NodeAPI.Config:input_type -> ConfigRequest
+	7,  // 17: NodeAPI.Bootstrap:input_type -> BootstrapRequest
+	9,  // 18: AgentAPI.Discover:input_type -> DiscoverRequest
+	11, // 19: AgentAPI.Register:input_type -> RegisterServiceRequest
+	13, // 20: AgentAPI.Deregister:input_type -> DeregisterServiceRequest
+	15, // 21: AgentAPI.UpdateHealth:input_type -> UpdateHealthRequest
+	17, // 22: AgentAPI.Reset:input_type -> ResetRequest
+	19, // 23: AgentAPI.Watch:input_type -> WatchRequest
+	21, // 24: AgentAPI.SignWorkloadCertificate:input_type -> SignWorkloadCertificateRequest
+	23, // 25: AgentAPI.AuthorizeConnection:input_type -> AuthorizeConnectionRequest
+	25, // 26: ObserverAPI.GetDatacenterServices:input_type -> GetDatacenterServicesRequest
+	27, // 27: ObserverAPI.WatchDatacenterServices:input_type -> WatchDatacenterServicesRequest
+	4,  // 28: NodeAPI.Heartbeat:output_type -> HeartbeatResponse
+	6,  // 29: NodeAPI.Config:output_type -> ConfigResponse
+	8,  // 30: NodeAPI.Bootstrap:output_type -> BootstrapResponse
+	10, // 31: AgentAPI.Discover:output_type -> DiscoverResponse
+	12, // 32: AgentAPI.Register:output_type -> RegisterServiceResponse
+	14, // 33: AgentAPI.Deregister:output_type -> DeregisterServiceResponse
+	16, // 34: AgentAPI.UpdateHealth:output_type -> UpdateHealthResponse
+	18, // 35: AgentAPI.Reset:output_type -> ResetResponse
+	20, // 36: AgentAPI.Watch:output_type -> WatchResponse
+	22, // 37: AgentAPI.SignWorkloadCertificate:output_type -> SignWorkloadCertificateResponse
+	24, // 38: AgentAPI.AuthorizeConnection:output_type -> AuthorizeConnectionResponse
+	26, // 39: ObserverAPI.GetDatacenterServices:output_type -> GetDatacenterServicesResponse
+	30, // 40: ObserverAPI.WatchDatacenterServices:output_type -> WatchDatacenterServicesResponse
+	28, // [28:41] is the sub-list for method output_type
+	15, // [15:28] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_agent_api_proto_init() }
@@ -1220,6 +2091,10 @@ func file_agent_api_proto_init() {
 		return
 	}
 	file_agent_api_proto_msgTypes[19].OneofWrappers = []any{
+		(*WatchResponse_Update)(nil),
+		(*WatchResponse_Delete)(nil),
+	}
+	file_agent_api_proto_msgTypes[29].OneofWrappers = []any{
 		(*WatchDatacenterServicesResponse_Update)(nil),
 		(*WatchDatacenterServicesResponse_Delete)(nil),
 	}
@@ -1228,13 +2103,14 @@ func file_agent_api_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_agent_api_proto_rawDesc), len(file_agent_api_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   20,
+			NumEnums:      1,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   3,
 		},
 		GoTypes:           file_agent_api_proto_goTypes,
 		DependencyIndexes: file_agent_api_proto_depIdxs,
+		EnumInfos:         file_agent_api_proto_enumTypes,
 		MessageInfos:      file_agent_api_proto_msgTypes,
 	}.Build()
 	File_agent_api_proto = out.File
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	NodeAPI_Heartbeat_FullMethodName = "/NodeAPI/Heartbeat"
 	NodeAPI_Config_FullMethodName    = "/NodeAPI/Config"
+	NodeAPI_Bootstrap_FullMethodName = "/NodeAPI/Bootstrap"
 )
 
 // NodeAPIClient is the client API for NodeAPI service.
@@ -29,6 +30,7 @@ const (
 type NodeAPIClient interface {
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	Config(ctx context.Context, in *ConfigRequest, opts ...grpc.CallOption) (*ConfigResponse, error)
+	Bootstrap(ctx context.Context, in *BootstrapRequest, opts ...grpc.CallOption) (*BootstrapResponse, error)
 }
 
 type nodeAPIClient struct {
@@ -59,12 +61,23 @@ func (c *nodeAPIClient) Config(ctx context.Context, in *ConfigRequest, opts ...g
 	return out, nil
 }
 
+func (c *nodeAPIClient) Bootstrap(ctx context.Context, in *BootstrapRequest, opts ...grpc.CallOption) (*BootstrapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BootstrapResponse)
+	err := c.cc.Invoke(ctx, NodeAPI_Bootstrap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NodeAPIServer is the server API for NodeAPI service.
 // All implementations must embed UnimplementedNodeAPIServer
 // for forward compatibility.
 type NodeAPIServer interface {
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	Config(context.Context, *ConfigRequest) (*ConfigResponse, error)
+	Bootstrap(context.Context, *BootstrapRequest) (*BootstrapResponse, error)
 	mustEmbedUnimplementedNodeAPIServer()
 }
 
@@ -81,6 +94,9 @@ func (UnimplementedNodeAPIServer) Heartbeat(context.Context, *HeartbeatRequest)
 func (UnimplementedNodeAPIServer) Config(context.Context, *ConfigRequest) (*ConfigResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Config not implemented")
 }
+func (UnimplementedNodeAPIServer) Bootstrap(context.Context, *BootstrapRequest) (*BootstrapResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Bootstrap not implemented")
+}
 func (UnimplementedNodeAPIServer) mustEmbedUnimplementedNodeAPIServer() {}
 func (UnimplementedNodeAPIServer) testEmbeddedByValue()                 {}
 
@@ -138,6 +154,24 @@ func _NodeAPI_Config_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeAPI_Bootstrap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BootstrapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeAPIServer).Bootstrap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeAPI_Bootstrap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeAPIServer).Bootstrap(ctx, req.(*BootstrapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NodeAPI_ServiceDesc is the grpc.ServiceDesc for NodeAPI service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,16 +187,24 @@ var NodeAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Config",
 			Handler:    _NodeAPI_Config_Handler,
 		},
+		{
+			MethodName: "Bootstrap",
+			Handler:    _NodeAPI_Bootstrap_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "agent_api.proto",
 }
 
 const (
-	AgentAPI_Discover_FullMethodName   = "/AgentAPI/Discover"
-	AgentAPI_Register_FullMethodName   = "/AgentAPI/Register"
-	AgentAPI_Deregister_FullMethodName = "/AgentAPI/Deregister"
-	AgentAPI_Reset_FullMethodName      = "/AgentAPI/Reset"
+	AgentAPI_Discover_FullMethodName                = "/AgentAPI/Discover"
+	AgentAPI_Register_FullMethodName                = "/AgentAPI/Register"
+	AgentAPI_Deregister_FullMethodName              = "/AgentAPI/Deregister"
+	AgentAPI_UpdateHealth_FullMethodName            = "/AgentAPI/UpdateHealth"
+	AgentAPI_Reset_FullMethodName                   = "/AgentAPI/Reset"
+	AgentAPI_Watch_FullMethodName                   = "/AgentAPI/Watch"
+	AgentAPI_SignWorkloadCertificate_FullMethodName = "/AgentAPI/SignWorkloadCertificate"
+	AgentAPI_AuthorizeConnection_FullMethodName     = "/AgentAPI/AuthorizeConnection"
 )
 
 // AgentAPIClient is the client API for AgentAPI service.
@@ -172,7 +214,11 @@ type AgentAPIClient interface {
 	Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error)
 	Register(ctx context.Context, in *RegisterServiceRequest, opts ...grpc.CallOption) (*RegisterServiceResponse, error)
 	Deregister(ctx context.Context, in *DeregisterServiceRequest, opts ...grpc.CallOption) (*DeregisterServiceResponse, error)
+	UpdateHealth(ctx context.Context, in *UpdateHealthRequest, opts ...grpc.CallOption) (*UpdateHealthResponse, error)
 	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
+	SignWorkloadCertificate(ctx context.Context, in *SignWorkloadCertificateRequest, opts ...grpc.CallOption) (*SignWorkloadCertificateResponse, error)
+	AuthorizeConnection(ctx context.Context, in *AuthorizeConnectionRequest, opts ...grpc.CallOption) (*AuthorizeConnectionResponse, error)
 }
 
 type agentAPIClient struct {
@@ -213,6 +259,16 @@ func (c *agentAPIClient) Deregister(ctx context.Context, in *DeregisterServiceRe
 	return out, nil
 }
 
+func (c *agentAPIClient) UpdateHealth(ctx context.Context, in *UpdateHealthRequest, opts ...grpc.CallOption) (*UpdateHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateHealthResponse)
+	err := c.cc.Invoke(ctx, AgentAPI_UpdateHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentAPIClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ResetResponse)
@@ -223,6 +279,45 @@ func (c *agentAPIClient) Reset(ctx context.Context, in *ResetRequest, opts ...gr
 	return out, nil
 }
 
+func (c *agentAPIClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentAPI_ServiceDesc.Streams[0], AgentAPI_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentAPI_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
+func (c *agentAPIClient) SignWorkloadCertificate(ctx context.Context, in *SignWorkloadCertificateRequest, opts ...grpc.CallOption) (*SignWorkloadCertificateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignWorkloadCertificateResponse)
+	err := c.cc.Invoke(ctx, AgentAPI_SignWorkloadCertificate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentAPIClient) AuthorizeConnection(ctx context.Context, in *AuthorizeConnectionRequest, opts ...grpc.CallOption) (*AuthorizeConnectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizeConnectionResponse)
+	err := c.cc.Invoke(ctx, AgentAPI_AuthorizeConnection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentAPIServer is the server API for AgentAPI service.
 // All implementations must embed UnimplementedAgentAPIServer
 // for forward compatibility.
@@ -230,7 +325,11 @@ type AgentAPIServer interface {
 	Discover(context.Context, *DiscoverRequest) (*DiscoverResponse, error)
 	Register(context.Context, *RegisterServiceRequest) (*RegisterServiceResponse, error)
 	Deregister(context.Context, *DeregisterServiceRequest) (*DeregisterServiceResponse, error)
+	UpdateHealth(context.Context, *UpdateHealthRequest) (*UpdateHealthResponse, error)
 	Reset(context.Context, *ResetRequest) (*ResetResponse, error)
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
+	SignWorkloadCertificate(context.Context, *SignWorkloadCertificateRequest) (*SignWorkloadCertificateResponse, error)
+	AuthorizeConnection(context.Context, *AuthorizeConnectionRequest) (*AuthorizeConnectionResponse, error)
 	mustEmbedUnimplementedAgentAPIServer()
 }
 
@@ -250,9 +349,21 @@ func (UnimplementedAgentAPIServer) Register(context.Context, *RegisterServiceReq
 func (UnimplementedAgentAPIServer) Deregister(context.Context, *DeregisterServiceRequest) (*DeregisterServiceResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Deregister not implemented")
 }
+func (UnimplementedAgentAPIServer) UpdateHealth(context.Context, *UpdateHealthRequest) (*UpdateHealthResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateHealth not implemented")
+}
 func (UnimplementedAgentAPIServer) Reset(context.Context, *ResetRequest) (*ResetResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Reset not implemented")
 }
+func (UnimplementedAgentAPIServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedAgentAPIServer) SignWorkloadCertificate(context.Context, *SignWorkloadCertificateRequest) (*SignWorkloadCertificateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SignWorkloadCertificate not implemented")
+}
+func (UnimplementedAgentAPIServer) AuthorizeConnection(context.Context, *AuthorizeConnectionRequest) (*AuthorizeConnectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AuthorizeConnection not implemented")
+}
 func (UnimplementedAgentAPIServer) mustEmbedUnimplementedAgentAPIServer() {}
 func (UnimplementedAgentAPIServer) testEmbeddedByValue()                  {}
 
@@ -328,6 +439,24 @@ func _AgentAPI_Deregister_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentAPI_UpdateHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentAPIServer).UpdateHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentAPI_UpdateHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentAPIServer).UpdateHealth(ctx, req.(*UpdateHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AgentAPI_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ResetRequest)
 	if err := dec(in); err != nil {
@@ -346,6 +475,53 @@ func _AgentAPI_Reset_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentAPI_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentAPIServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentAPI_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+func _AgentAPI_SignWorkloadCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignWorkloadCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentAPIServer).SignWorkloadCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentAPI_SignWorkloadCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentAPIServer).SignWorkloadCertificate(ctx, req.(*SignWorkloadCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentAPI_AuthorizeConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentAPIServer).AuthorizeConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentAPI_AuthorizeConnection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentAPIServer).AuthorizeConnection(ctx, req.(*AuthorizeConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentAPI_ServiceDesc is the grpc.ServiceDesc for AgentAPI service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -365,12 +541,30 @@ var AgentAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Deregister",
 			Handler:    _AgentAPI_Deregister_Handler,
 		},
+		{
+			MethodName: "UpdateHealth",
+			Handler:    _AgentAPI_UpdateHealth_Handler,
+		},
 		{
 			MethodName: "Reset",
 			Handler:    _AgentAPI_Reset_Handler,
 		},
+		{
+			MethodName: "SignWorkloadCertificate",
+			Handler:    _AgentAPI_SignWorkloadCertificate_Handler,
+		},
+		{
+			MethodName: "AuthorizeConnection",
+			Handler:    _AgentAPI_AuthorizeConnection_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _AgentAPI_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "agent_api.proto",
 }
 
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// Dependency the daemon needs to be ready, Check returns why it isn't
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Serve /healthz, which answers as long as the process is running, and
+// /readyz, which fails if any of the readiness checks does
+func HandleHealth(mux *http.ServeMux, checks []ReadinessCheck) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+
+		failed := false
+		body := ""
+		for _, check := range checks {
+			if err := check.Check(ctx); err != nil {
+				failed = true
+				body += fmt.Sprintf("%s: %v\n", check.Name, err)
+			} else {
+				body += fmt.Sprintf("%s: ok\n", check.Name)
+			}
+		}
+
+		if failed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprint(w, body)
+	})
+}
@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC metadata carrying the id used to correlate the logs of a request
+// between the clients and the registry
+const RequestIDMetadata = "x-request-id"
+
+type contextKey struct{}
+
+type requestIDKey struct{}
+
+// Handler adding the attributes stored in the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(contextKey{}).([]any); ok {
+		record.Add(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func NewHandler(w io.Writer, level string, format string) (slog.Handler, error) {
+	var lvl slog.Level
+	err := lvl.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %v", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return contextHandler{slog.NewTextHandler(w, opts)}, nil
+	case "json":
+		return contextHandler{slog.NewJSONHandler(w, opts)}, nil
+	default:
+		return nil, fmt.Errorf("invalid log format %v, must be text or json", format)
+	}
+}
+
+// Log to stderr with the given level and format, either text or json. The
+// standard log package is redirected as well, at the info level.
+func Setup(level string, format string, attrs ...any) {
+	handler, err := NewHandler(os.Stderr, level, format)
+	if err != nil {
+		log.Fatalf("Error configuring logging: %v", err)
+	}
+
+	slog.SetDefault(slog.New(handler).With(attrs...))
+}
+
+// Add fields to the logs written with the returned context
+func WithAttrs(ctx context.Context, attrs ...any) context.Context {
+	existing, _ := ctx.Value(contextKey{}).([]any)
+	return context.WithValue(ctx, contextKey{}, append(existing[:len(existing):len(existing)], attrs...))
+}
+
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// Use the request id sent by the client, or a new one
+func requestContext(ctx context.Context, method string) context.Context {
+	id := newRequestID()
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(RequestIDMetadata)) > 0 {
+		id = md.Get(RequestIDMetadata)[0]
+	}
+
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return WithAttrs(ctx, "request_id", id, "method", method)
+}
+
+// Id of the request being handled, empty outside of the server interceptors
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func logRequest(ctx context.Context, start time.Time, err error) {
+	slog.DebugContext(ctx, "Handled request", "code", status.Code(err).String(), "duration", time.Since(start))
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *serverStream) Context() context.Context {
+	return stream.ctx
+}
+
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx = requestContext(ctx, info.FullMethod)
+	res, err := handler(ctx, req)
+	logRequest(ctx, start, err)
+	return res, err
+}
+
+func StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx := requestContext(stream.Context(), info.FullMethod)
+	err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	logRequest(ctx, start, err)
+	return err
+}
+
+// Send a request id with every call, failed calls are logged with it so they
+// can be found in the registry logs
+func clientContext(ctx context.Context, method string) (context.Context, context.Context) {
+	id := newRequestID()
+	return metadata.AppendToOutgoingContext(ctx, RequestIDMetadata, id), WithAttrs(ctx, "request_id", id, "method", method)
+}
+
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, logCtx := clientContext(ctx, method)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		slog.DebugContext(logCtx, "Request failed", "err", err)
+	}
+	return err
+}
+
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, logCtx := clientContext(ctx, method)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		slog.DebugContext(logCtx, "Request failed", "err", err)
+	}
+	return stream, err
+}
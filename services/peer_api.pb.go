@@ -77,12 +77,187 @@ func (NodeType) EnumDescriptor() ([]byte, []int) {
 	return file_peer_api_proto_rawDescGZIP(), []int{0}
 }
 
+type CARotationPhase int32
+
+const (
+	CARotationPhase_INTRODUCE CARotationPhase = 1
+	CARotationPhase_ACTIVATE  CARotationPhase = 2
+	CARotationPhase_RETIRE    CARotationPhase = 3
+)
+
+// Enum value maps for CARotationPhase.
+var (
+	CARotationPhase_name = map[int32]string{
+		1: "INTRODUCE",
+		2: "ACTIVATE",
+		3: "RETIRE",
+	}
+	CARotationPhase_value = map[string]int32{
+		"INTRODUCE": 1,
+		"ACTIVATE":  2,
+		"RETIRE":    3,
+	}
+)
+
+func (x CARotationPhase) Enum() *CARotationPhase {
+	p := new(CARotationPhase)
+	*p = x
+	return p
+}
+
+func (x CARotationPhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CARotationPhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_peer_api_proto_enumTypes[1].Descriptor()
+}
+
+func (CARotationPhase) Type() protoreflect.EnumType {
+	return &file_peer_api_proto_enumTypes[1]
+}
+
+func (x CARotationPhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *CARotationPhase) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = CARotationPhase(num)
+	return nil
+}
+
+// Deprecated: Use CARotationPhase.Descriptor instead.
+func (CARotationPhase) EnumDescriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{1}
+}
+
+type OperatorRole int32
+
+const (
+	OperatorRole_ADMIN        OperatorRole = 1
+	OperatorRole_NODE_MANAGER OperatorRole = 2
+	OperatorRole_READ_ONLY    OperatorRole = 3
+)
+
+// Enum value maps for OperatorRole.
+var (
+	OperatorRole_name = map[int32]string{
+		1: "ADMIN",
+		2: "NODE_MANAGER",
+		3: "READ_ONLY",
+	}
+	OperatorRole_value = map[string]int32{
+		"ADMIN":        1,
+		"NODE_MANAGER": 2,
+		"READ_ONLY":    3,
+	}
+)
+
+func (x OperatorRole) Enum() *OperatorRole {
+	p := new(OperatorRole)
+	*p = x
+	return p
+}
+
+func (x OperatorRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OperatorRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_peer_api_proto_enumTypes[2].Descriptor()
+}
+
+func (OperatorRole) Type() protoreflect.EnumType {
+	return &file_peer_api_proto_enumTypes[2]
+}
+
+func (x OperatorRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *OperatorRole) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = OperatorRole(num)
+	return nil
+}
+
+// Deprecated: Use OperatorRole.Descriptor instead.
+func (OperatorRole) EnumDescriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{2}
+}
+
+type IntentionAction int32
+
+const (
+	IntentionAction_ALLOW IntentionAction = 1
+	IntentionAction_DENY  IntentionAction = 2
+)
+
+// Enum value maps for IntentionAction.
+var (
+	IntentionAction_name = map[int32]string{
+		1: "ALLOW",
+		2: "DENY",
+	}
+	IntentionAction_value = map[string]int32{
+		"ALLOW": 1,
+		"DENY":  2,
+	}
+)
+
+func (x IntentionAction) Enum() *IntentionAction {
+	p := new(IntentionAction)
+	*p = x
+	return p
+}
+
+func (x IntentionAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IntentionAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_peer_api_proto_enumTypes[3].Descriptor()
+}
+
+func (IntentionAction) Type() protoreflect.EnumType {
+	return &file_peer_api_proto_enumTypes[3]
+}
+
+func (x IntentionAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *IntentionAction) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = IntentionAction(num)
+	return nil
+}
+
+// Deprecated: Use IntentionAction.Descriptor instead.
+func (IntentionAction) EnumDescriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{3}
+}
+
 type Peer struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            *string                `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
 	Name          *string                `protobuf:"bytes,2,req,name=name" json:"name,omitempty"`
 	PeerUrls      []string               `protobuf:"bytes,3,rep,name=peer_urls,json=peerUrls" json:"peer_urls,omitempty"`
 	ClientUrls    []string               `protobuf:"bytes,4,rep,name=client_urls,json=clientUrls" json:"client_urls,omitempty"`
+	IsLearner     *bool                  `protobuf:"varint,5,opt,name=is_learner,json=isLearner" json:"is_learner,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -145,6 +320,13 @@ func (x *Peer) GetClientUrls() []string {
 	return nil
 }
 
+func (x *Peer) GetIsLearner() bool {
+	if x != nil && x.IsLearner != nil {
+		return *x.IsLearner
+	}
+	return false
+}
+
 type GetPeersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -305,30 +487,27 @@ func (*AddSelfPeerResponse) Descriptor() ([]byte, []int) {
 	return file_peer_api_proto_rawDescGZIP(), []int{4}
 }
 
-type AddNodeRequest struct {
+type RemovePeerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
-	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
-	Location      *string                `protobuf:"bytes,3,req,name=location" json:"location,omitempty"`
-	NodeType      *NodeType              `protobuf:"varint,4,req,name=node_type,json=nodeType,enum=NodeType" json:"node_type,omitempty"`
+	Peer          *string                `protobuf:"bytes,1,req,name=peer" json:"peer,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddNodeRequest) Reset() {
-	*x = AddNodeRequest{}
+func (x *RemovePeerRequest) Reset() {
+	*x = RemovePeerRequest{}
 	mi := &file_peer_api_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddNodeRequest) String() string {
+func (x *RemovePeerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddNodeRequest) ProtoMessage() {}
+func (*RemovePeerRequest) ProtoMessage() {}
 
-func (x *AddNodeRequest) ProtoReflect() protoreflect.Message {
+func (x *RemovePeerRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_peer_api_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -340,61 +519,38 @@ func (x *AddNodeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddNodeRequest.ProtoReflect.Descriptor instead.
-func (*AddNodeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RemovePeerRequest.ProtoReflect.Descriptor instead.
+func (*RemovePeerRequest) Descriptor() ([]byte, []int) {
 	return file_peer_api_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *AddNodeRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
-	}
-	return ""
-}
-
-func (x *AddNodeRequest) GetDatacenter() string {
-	if x != nil && x.Datacenter != nil {
-		return *x.Datacenter
-	}
-	return ""
-}
-
-func (x *AddNodeRequest) GetLocation() string {
-	if x != nil && x.Location != nil {
-		return *x.Location
+func (x *RemovePeerRequest) GetPeer() string {
+	if x != nil && x.Peer != nil {
+		return *x.Peer
 	}
 	return ""
 }
 
-func (x *AddNodeRequest) GetNodeType() NodeType {
-	if x != nil && x.NodeType != nil {
-		return *x.NodeType
-	}
-	return NodeType_AGENT
-}
-
-type AddNodeResponse struct {
+type RemovePeerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Certificate   []byte                 `protobuf:"bytes,1,req,name=certificate" json:"certificate,omitempty"`
-	Key           []byte                 `protobuf:"bytes,2,req,name=key" json:"key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddNodeResponse) Reset() {
-	*x = AddNodeResponse{}
+func (x *RemovePeerResponse) Reset() {
+	*x = RemovePeerResponse{}
 	mi := &file_peer_api_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddNodeResponse) String() string {
+func (x *RemovePeerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddNodeResponse) ProtoMessage() {}
+func (*RemovePeerResponse) ProtoMessage() {}
 
-func (x *AddNodeResponse) ProtoReflect() protoreflect.Message {
+func (x *RemovePeerResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_peer_api_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -406,47 +562,32 @@ func (x *AddNodeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddNodeResponse.ProtoReflect.Descriptor instead.
-func (*AddNodeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RemovePeerResponse.ProtoReflect.Descriptor instead.
+func (*RemovePeerResponse) Descriptor() ([]byte, []int) {
 	return file_peer_api_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *AddNodeResponse) GetCertificate() []byte {
-	if x != nil {
-		return x.Certificate
-	}
-	return nil
-}
-
-func (x *AddNodeResponse) GetKey() []byte {
-	if x != nil {
-		return x.Key
-	}
-	return nil
-}
-
-type GetNodeCredentialsRequest struct {
+type PromotePeerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
-	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	Peer          *string                `protobuf:"bytes,1,req,name=peer" json:"peer,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNodeCredentialsRequest) Reset() {
-	*x = GetNodeCredentialsRequest{}
+func (x *PromotePeerRequest) Reset() {
+	*x = PromotePeerRequest{}
 	mi := &file_peer_api_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNodeCredentialsRequest) String() string {
+func (x *PromotePeerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNodeCredentialsRequest) ProtoMessage() {}
+func (*PromotePeerRequest) ProtoMessage() {}
 
-func (x *GetNodeCredentialsRequest) ProtoReflect() protoreflect.Message {
+func (x *PromotePeerRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_peer_api_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -458,47 +599,38 @@ func (x *GetNodeCredentialsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNodeCredentialsRequest.ProtoReflect.Descriptor instead.
-func (*GetNodeCredentialsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PromotePeerRequest.ProtoReflect.Descriptor instead.
+func (*PromotePeerRequest) Descriptor() ([]byte, []int) {
 	return file_peer_api_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetNodeCredentialsRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
-	}
-	return ""
-}
-
-func (x *GetNodeCredentialsRequest) GetDatacenter() string {
-	if x != nil && x.Datacenter != nil {
-		return *x.Datacenter
+func (x *PromotePeerRequest) GetPeer() string {
+	if x != nil && x.Peer != nil {
+		return *x.Peer
 	}
 	return ""
 }
 
-type GetNodeCredentialsResponse struct {
+type PromotePeerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Certificate   []byte                 `protobuf:"bytes,1,req,name=certificate" json:"certificate,omitempty"`
-	Key           []byte                 `protobuf:"bytes,2,req,name=key" json:"key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetNodeCredentialsResponse) Reset() {
-	*x = GetNodeCredentialsResponse{}
+func (x *PromotePeerResponse) Reset() {
+	*x = PromotePeerResponse{}
 	mi := &file_peer_api_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetNodeCredentialsResponse) String() string {
+func (x *PromotePeerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetNodeCredentialsResponse) ProtoMessage() {}
+func (*PromotePeerResponse) ProtoMessage() {}
 
-func (x *GetNodeCredentialsResponse) ProtoReflect() protoreflect.Message {
+func (x *PromotePeerResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_peer_api_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -510,52 +642,2958 @@ func (x *GetNodeCredentialsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetNodeCredentialsResponse.ProtoReflect.Descriptor instead.
-func (*GetNodeCredentialsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use PromotePeerResponse.ProtoReflect.Descriptor instead.
+func (*PromotePeerResponse) Descriptor() ([]byte, []int) {
 	return file_peer_api_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetNodeCredentialsResponse) GetCertificate() []byte {
+type PeerStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Peer             *Peer                  `protobuf:"bytes,1,req,name=peer" json:"peer,omitempty"`
+	Healthy          *bool                  `protobuf:"varint,2,opt,name=healthy" json:"healthy,omitempty"`
+	Leader           *bool                  `protobuf:"varint,3,opt,name=leader" json:"leader,omitempty"`
+	Version          *string                `protobuf:"bytes,4,opt,name=version" json:"version,omitempty"`
+	DbSize           *int64                 `protobuf:"varint,5,opt,name=db_size,json=dbSize" json:"db_size,omitempty"`
+	RaftTerm         *uint64                `protobuf:"varint,6,opt,name=raft_term,json=raftTerm" json:"raft_term,omitempty"`
+	RaftIndex        *uint64                `protobuf:"varint,7,opt,name=raft_index,json=raftIndex" json:"raft_index,omitempty"`
+	RaftAppliedIndex *uint64                `protobuf:"varint,8,opt,name=raft_applied_index,json=raftAppliedIndex" json:"raft_applied_index,omitempty"`
+	Errors           []string               `protobuf:"bytes,9,rep,name=errors" json:"errors,omitempty"`
+	DbSizeInUse      *int64                 `protobuf:"varint,10,opt,name=db_size_in_use,json=dbSizeInUse" json:"db_size_in_use,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PeerStatus) Reset() {
+	*x = PeerStatus{}
+	mi := &file_peer_api_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerStatus) ProtoMessage() {}
+
+func (x *PeerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[9]
 	if x != nil {
-		return x.Certificate
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerStatus.ProtoReflect.Descriptor instead.
+func (*PeerStatus) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PeerStatus) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
 	}
 	return nil
 }
 
-func (x *GetNodeCredentialsResponse) GetKey() []byte {
+func (x *PeerStatus) GetHealthy() bool {
+	if x != nil && x.Healthy != nil {
+		return *x.Healthy
+	}
+	return false
+}
+
+func (x *PeerStatus) GetLeader() bool {
+	if x != nil && x.Leader != nil {
+		return *x.Leader
+	}
+	return false
+}
+
+func (x *PeerStatus) GetVersion() string {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return ""
+}
+
+func (x *PeerStatus) GetDbSize() int64 {
+	if x != nil && x.DbSize != nil {
+		return *x.DbSize
+	}
+	return 0
+}
+
+func (x *PeerStatus) GetRaftTerm() uint64 {
+	if x != nil && x.RaftTerm != nil {
+		return *x.RaftTerm
+	}
+	return 0
+}
+
+func (x *PeerStatus) GetRaftIndex() uint64 {
+	if x != nil && x.RaftIndex != nil {
+		return *x.RaftIndex
+	}
+	return 0
+}
+
+func (x *PeerStatus) GetRaftAppliedIndex() uint64 {
+	if x != nil && x.RaftAppliedIndex != nil {
+		return *x.RaftAppliedIndex
+	}
+	return 0
+}
+
+func (x *PeerStatus) GetErrors() []string {
 	if x != nil {
-		return x.Key
+		return x.Errors
 	}
 	return nil
 }
 
-var File_peer_api_proto protoreflect.FileDescriptor
+func (x *PeerStatus) GetDbSizeInUse() int64 {
+	if x != nil && x.DbSizeInUse != nil {
+		return *x.DbSizeInUse
+	}
+	return 0
+}
 
-const file_peer_api_proto_rawDesc = "" +
-	"\n" +
-	"\x0epeer_api.proto\"h\n" +
-	"\x04Peer\x12\x0e\n" +
-	"\x02id\x18\x01 \x02(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x02(\tR\x04name\x12\x1b\n" +
-	"\tpeer_urls\x18\x03 \x03(\tR\bpeerUrls\x12\x1f\n" +
-	"\vclient_urls\x18\x04 \x03(\tR\n" +
-	"clientUrls\"\x11\n" +
-	"\x0fGetPeersRequest\"/\n" +
-	"\x10GetPeersResponse\x12\x1b\n" +
-	"\x05peers\x18\x01 \x03(\v2\x05.PeerR\x05peers\"=\n" +
-	"\x12AddSelfPeerRequest\x12'\n" +
-	"\x0fadvertised_urls\x18\x01 \x03(\tR\x0eadvertisedUrls\"\x15\n" +
-	"\x13AddSelfPeerResponse\"\x88\x01\n" +
+type PeerStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerStatusRequest) Reset() {
+	*x = PeerStatusRequest{}
+	mi := &file_peer_api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerStatusRequest) ProtoMessage() {}
+
+func (x *PeerStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerStatusRequest.ProtoReflect.Descriptor instead.
+func (*PeerStatusRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{10}
+}
+
+type PeerStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Peers         []*PeerStatus          `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerStatusResponse) Reset() {
+	*x = PeerStatusResponse{}
+	mi := &file_peer_api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerStatusResponse) ProtoMessage() {}
+
+func (x *PeerStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerStatusResponse.ProtoReflect.Descriptor instead.
+func (*PeerStatusResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PeerStatusResponse) GetPeers() []*PeerStatus {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type AddNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	Location      *string                `protobuf:"bytes,3,req,name=location" json:"location,omitempty"`
+	NodeType      *NodeType              `protobuf:"varint,4,req,name=node_type,json=nodeType,enum=NodeType" json:"node_type,omitempty"`
+	Bootstrap     *bool                  `protobuf:"varint,5,opt,name=bootstrap" json:"bootstrap,omitempty"`
+	TokenTtl      *uint32                `protobuf:"varint,6,opt,name=token_ttl,json=tokenTtl" json:"token_ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddNodeRequest) Reset() {
+	*x = AddNodeRequest{}
+	mi := &file_peer_api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNodeRequest) ProtoMessage() {}
+
+func (x *AddNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNodeRequest.ProtoReflect.Descriptor instead.
+func (*AddNodeRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AddNodeRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetNodeType() NodeType {
+	if x != nil && x.NodeType != nil {
+		return *x.NodeType
+	}
+	return NodeType_AGENT
+}
+
+func (x *AddNodeRequest) GetBootstrap() bool {
+	if x != nil && x.Bootstrap != nil {
+		return *x.Bootstrap
+	}
+	return false
+}
+
+func (x *AddNodeRequest) GetTokenTtl() uint32 {
+	if x != nil && x.TokenTtl != nil {
+		return *x.TokenTtl
+	}
+	return 0
+}
+
+type AddNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificate   []byte                 `protobuf:"bytes,1,opt,name=certificate" json:"certificate,omitempty"`
+	Key           []byte                 `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	Token         *string                `protobuf:"bytes,3,opt,name=token" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddNodeResponse) Reset() {
+	*x = AddNodeResponse{}
+	mi := &file_peer_api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNodeResponse) ProtoMessage() {}
+
+func (x *AddNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNodeResponse.ProtoReflect.Descriptor instead.
+func (*AddNodeResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AddNodeResponse) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+func (x *AddNodeResponse) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *AddNodeResponse) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+type GetNodeCredentialsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeCredentialsRequest) Reset() {
+	*x = GetNodeCredentialsRequest{}
+	mi := &file_peer_api_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeCredentialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeCredentialsRequest) ProtoMessage() {}
+
+func (x *GetNodeCredentialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeCredentialsRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeCredentialsRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetNodeCredentialsRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *GetNodeCredentialsRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+type GetNodeCredentialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificate   []byte                 `protobuf:"bytes,1,req,name=certificate" json:"certificate,omitempty"`
+	Key           []byte                 `protobuf:"bytes,2,req,name=key" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeCredentialsResponse) Reset() {
+	*x = GetNodeCredentialsResponse{}
+	mi := &file_peer_api_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeCredentialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeCredentialsResponse) ProtoMessage() {}
+
+func (x *GetNodeCredentialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeCredentialsResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeCredentialsResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetNodeCredentialsResponse) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+func (x *GetNodeCredentialsResponse) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type Node struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	Location      *string                `protobuf:"bytes,3,req,name=location" json:"location,omitempty"`
+	NodeType      *NodeType              `protobuf:"varint,4,req,name=node_type,json=nodeType,enum=NodeType" json:"node_type,omitempty"`
+	Disabled      *bool                  `protobuf:"varint,5,opt,name=disabled" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_peer_api_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Node) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Node) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *Node) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *Node) GetNodeType() NodeType {
+	if x != nil && x.NodeType != nil {
+		return *x.NodeType
+	}
+	return NodeType_AGENT
+}
+
+func (x *Node) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+type ListNodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Datacenter    *string                `protobuf:"bytes,1,opt,name=datacenter" json:"datacenter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesRequest) Reset() {
+	*x = ListNodesRequest{}
+	mi := &file_peer_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesRequest) ProtoMessage() {}
+
+func (x *ListNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListNodesRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+type ListNodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*Node                `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesResponse) Reset() {
+	*x = ListNodesResponse{}
+	mi := &file_peer_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesResponse) ProtoMessage() {}
+
+func (x *ListNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListNodesResponse) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type NodeCertificate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Serial        *string                `protobuf:"bytes,1,req,name=serial" json:"serial,omitempty"`
+	NotBefore     *int64                 `protobuf:"varint,2,req,name=not_before,json=notBefore" json:"not_before,omitempty"`
+	NotAfter      *int64                 `protobuf:"varint,3,req,name=not_after,json=notAfter" json:"not_after,omitempty"`
+	Revoked       *bool                  `protobuf:"varint,4,opt,name=revoked" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeCertificate) Reset() {
+	*x = NodeCertificate{}
+	mi := &file_peer_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeCertificate) ProtoMessage() {}
+
+func (x *NodeCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeCertificate.ProtoReflect.Descriptor instead.
+func (*NodeCertificate) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *NodeCertificate) GetSerial() string {
+	if x != nil && x.Serial != nil {
+		return *x.Serial
+	}
+	return ""
+}
+
+func (x *NodeCertificate) GetNotBefore() int64 {
+	if x != nil && x.NotBefore != nil {
+		return *x.NotBefore
+	}
+	return 0
+}
+
+func (x *NodeCertificate) GetNotAfter() int64 {
+	if x != nil && x.NotAfter != nil {
+		return *x.NotAfter
+	}
+	return 0
+}
+
+func (x *NodeCertificate) GetRevoked() bool {
+	if x != nil && x.Revoked != nil {
+		return *x.Revoked
+	}
+	return false
+}
+
+type GetNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeRequest) Reset() {
+	*x = GetNodeRequest{}
+	mi := &file_peer_api_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeRequest) ProtoMessage() {}
+
+func (x *GetNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetNodeRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *GetNodeRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+type GetNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Node          *Node                  `protobuf:"bytes,1,req,name=node" json:"node,omitempty"`
+	Services      []*ServiceSpec         `protobuf:"bytes,2,rep,name=services" json:"services,omitempty"`
+	Certificates  []*NodeCertificate     `protobuf:"bytes,3,rep,name=certificates" json:"certificates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeResponse) Reset() {
+	*x = GetNodeResponse{}
+	mi := &file_peer_api_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeResponse) ProtoMessage() {}
+
+func (x *GetNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetNodeResponse) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *GetNodeResponse) GetServices() []*ServiceSpec {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *GetNodeResponse) GetCertificates() []*NodeCertificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+type RemoveNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeRequest) Reset() {
+	*x = RemoveNodeRequest{}
+	mi := &file_peer_api_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeRequest) ProtoMessage() {}
+
+func (x *RemoveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RemoveNodeRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *RemoveNodeRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+type RemoveNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeResponse) Reset() {
+	*x = RemoveNodeResponse{}
+	mi := &file_peer_api_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeResponse) ProtoMessage() {}
+
+func (x *RemoveNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveNodeResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{23}
+}
+
+type SetNodeDisabledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	Disabled      *bool                  `protobuf:"varint,3,req,name=disabled" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNodeDisabledRequest) Reset() {
+	*x = SetNodeDisabledRequest{}
+	mi := &file_peer_api_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNodeDisabledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNodeDisabledRequest) ProtoMessage() {}
+
+func (x *SetNodeDisabledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNodeDisabledRequest.ProtoReflect.Descriptor instead.
+func (*SetNodeDisabledRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetNodeDisabledRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *SetNodeDisabledRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *SetNodeDisabledRequest) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+type SetNodeDisabledResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNodeDisabledResponse) Reset() {
+	*x = SetNodeDisabledResponse{}
+	mi := &file_peer_api_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNodeDisabledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNodeDisabledResponse) ProtoMessage() {}
+
+func (x *SetNodeDisabledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNodeDisabledResponse.ProtoReflect.Descriptor instead.
+func (*SetNodeDisabledResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{25}
+}
+
+type RevokeNodeCertificatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,2,req,name=datacenter" json:"datacenter,omitempty"`
+	Serial        *string                `protobuf:"bytes,3,opt,name=serial" json:"serial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeNodeCertificatesRequest) Reset() {
+	*x = RevokeNodeCertificatesRequest{}
+	mi := &file_peer_api_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeNodeCertificatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeNodeCertificatesRequest) ProtoMessage() {}
+
+func (x *RevokeNodeCertificatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeNodeCertificatesRequest.ProtoReflect.Descriptor instead.
+func (*RevokeNodeCertificatesRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RevokeNodeCertificatesRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *RevokeNodeCertificatesRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *RevokeNodeCertificatesRequest) GetSerial() string {
+	if x != nil && x.Serial != nil {
+		return *x.Serial
+	}
+	return ""
+}
+
+type RevokeNodeCertificatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Serials       []string               `protobuf:"bytes,1,rep,name=serials" json:"serials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeNodeCertificatesResponse) Reset() {
+	*x = RevokeNodeCertificatesResponse{}
+	mi := &file_peer_api_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeNodeCertificatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeNodeCertificatesResponse) ProtoMessage() {}
+
+func (x *RevokeNodeCertificatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeNodeCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*RevokeNodeCertificatesResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RevokeNodeCertificatesResponse) GetSerials() []string {
+	if x != nil {
+		return x.Serials
+	}
+	return nil
+}
+
+type CAGeneration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generation    *uint32                `protobuf:"varint,1,req,name=generation" json:"generation,omitempty"`
+	Start         *int64                 `protobuf:"varint,2,req,name=start" json:"start,omitempty"`
+	Active        *bool                  `protobuf:"varint,3,opt,name=active" json:"active,omitempty"`
+	TrustedBy     []string               `protobuf:"bytes,4,rep,name=trusted_by,json=trustedBy" json:"trusted_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CAGeneration) Reset() {
+	*x = CAGeneration{}
+	mi := &file_peer_api_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CAGeneration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CAGeneration) ProtoMessage() {}
+
+func (x *CAGeneration) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CAGeneration.ProtoReflect.Descriptor instead.
+func (*CAGeneration) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CAGeneration) GetGeneration() uint32 {
+	if x != nil && x.Generation != nil {
+		return *x.Generation
+	}
+	return 0
+}
+
+func (x *CAGeneration) GetStart() int64 {
+	if x != nil && x.Start != nil {
+		return *x.Start
+	}
+	return 0
+}
+
+func (x *CAGeneration) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+func (x *CAGeneration) GetTrustedBy() []string {
+	if x != nil {
+		return x.TrustedBy
+	}
+	return nil
+}
+
+type GetCAStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCAStateRequest) Reset() {
+	*x = GetCAStateRequest{}
+	mi := &file_peer_api_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCAStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCAStateRequest) ProtoMessage() {}
+
+func (x *GetCAStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCAStateRequest.ProtoReflect.Descriptor instead.
+func (*GetCAStateRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{29}
+}
+
+type GetCAStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generations   []*CAGeneration        `protobuf:"bytes,1,rep,name=generations" json:"generations,omitempty"`
+	JoinToken     *string                `protobuf:"bytes,2,req,name=join_token,json=joinToken" json:"join_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCAStateResponse) Reset() {
+	*x = GetCAStateResponse{}
+	mi := &file_peer_api_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCAStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCAStateResponse) ProtoMessage() {}
+
+func (x *GetCAStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCAStateResponse.ProtoReflect.Descriptor instead.
+func (*GetCAStateResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetCAStateResponse) GetGenerations() []*CAGeneration {
+	if x != nil {
+		return x.Generations
+	}
+	return nil
+}
+
+func (x *GetCAStateResponse) GetJoinToken() string {
+	if x != nil && x.JoinToken != nil {
+		return *x.JoinToken
+	}
+	return ""
+}
+
+type RotateCARequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phase         *CARotationPhase       `protobuf:"varint,1,req,name=phase,enum=CARotationPhase" json:"phase,omitempty"`
+	Force         *bool                  `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateCARequest) Reset() {
+	*x = RotateCARequest{}
+	mi := &file_peer_api_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateCARequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateCARequest) ProtoMessage() {}
+
+func (x *RotateCARequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateCARequest.ProtoReflect.Descriptor instead.
+func (*RotateCARequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RotateCARequest) GetPhase() CARotationPhase {
+	if x != nil && x.Phase != nil {
+		return *x.Phase
+	}
+	return CARotationPhase_INTRODUCE
+}
+
+func (x *RotateCARequest) GetForce() bool {
+	if x != nil && x.Force != nil {
+		return *x.Force
+	}
+	return false
+}
+
+type RotateCAResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generations   []*CAGeneration        `protobuf:"bytes,1,rep,name=generations" json:"generations,omitempty"`
+	JoinToken     *string                `protobuf:"bytes,2,req,name=join_token,json=joinToken" json:"join_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateCAResponse) Reset() {
+	*x = RotateCAResponse{}
+	mi := &file_peer_api_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateCAResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateCAResponse) ProtoMessage() {}
+
+func (x *RotateCAResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateCAResponse.ProtoReflect.Descriptor instead.
+func (*RotateCAResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RotateCAResponse) GetGenerations() []*CAGeneration {
+	if x != nil {
+		return x.Generations
+	}
+	return nil
+}
+
+func (x *RotateCAResponse) GetJoinToken() string {
+	if x != nil && x.JoinToken != nil {
+		return *x.JoinToken
+	}
+	return ""
+}
+
+type RotateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateTokenRequest) Reset() {
+	*x = RotateTokenRequest{}
+	mi := &file_peer_api_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateTokenRequest) ProtoMessage() {}
+
+func (x *RotateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateTokenRequest.ProtoReflect.Descriptor instead.
+func (*RotateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{33}
+}
+
+type RotateTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generations   []*CAGeneration        `protobuf:"bytes,1,rep,name=generations" json:"generations,omitempty"`
+	JoinToken     *string                `protobuf:"bytes,2,req,name=join_token,json=joinToken" json:"join_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateTokenResponse) Reset() {
+	*x = RotateTokenResponse{}
+	mi := &file_peer_api_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateTokenResponse) ProtoMessage() {}
+
+func (x *RotateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateTokenResponse.ProtoReflect.Descriptor instead.
+func (*RotateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RotateTokenResponse) GetGenerations() []*CAGeneration {
+	if x != nil {
+		return x.Generations
+	}
+	return nil
+}
+
+func (x *RotateTokenResponse) GetJoinToken() string {
+	if x != nil && x.JoinToken != nil {
+		return *x.JoinToken
+	}
+	return ""
+}
+
+type GetCATokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generation    *uint32                `protobuf:"varint,1,req,name=generation" json:"generation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCATokenRequest) Reset() {
+	*x = GetCATokenRequest{}
+	mi := &file_peer_api_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCATokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCATokenRequest) ProtoMessage() {}
+
+func (x *GetCATokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCATokenRequest.ProtoReflect.Descriptor instead.
+func (*GetCATokenRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetCATokenRequest) GetGeneration() uint32 {
+	if x != nil && x.Generation != nil {
+		return *x.Generation
+	}
+	return 0
+}
+
+type GetCATokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         []byte                 `protobuf:"bytes,1,req,name=token" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCATokenResponse) Reset() {
+	*x = GetCATokenResponse{}
+	mi := &file_peer_api_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCATokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCATokenResponse) ProtoMessage() {}
+
+func (x *GetCATokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCATokenResponse.ProtoReflect.Descriptor instead.
+func (*GetCATokenResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetCATokenResponse) GetToken() []byte {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+type SnapshotMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *string                `protobuf:"bytes,1,req,name=token" json:"token,omitempty"`
+	CaState       []byte                 `protobuf:"bytes,2,req,name=ca_state,json=caState" json:"ca_state,omitempty"`
+	Size          *uint64                `protobuf:"varint,3,req,name=size" json:"size,omitempty"`
+	CaTokens      []byte                 `protobuf:"bytes,4,opt,name=ca_tokens,json=caTokens" json:"ca_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotMetadata) Reset() {
+	*x = SnapshotMetadata{}
+	mi := &file_peer_api_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotMetadata) ProtoMessage() {}
+
+func (x *SnapshotMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotMetadata.ProtoReflect.Descriptor instead.
+func (*SnapshotMetadata) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SnapshotMetadata) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *SnapshotMetadata) GetCaState() []byte {
+	if x != nil {
+		return x.CaState
+	}
+	return nil
+}
+
+func (x *SnapshotMetadata) GetSize() uint64 {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return 0
+}
+
+func (x *SnapshotMetadata) GetCaTokens() []byte {
+	if x != nil {
+		return x.CaTokens
+	}
+	return nil
+}
+
+type SnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotRequest) Reset() {
+	*x = SnapshotRequest{}
+	mi := &file_peer_api_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotRequest) ProtoMessage() {}
+
+func (x *SnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{38}
+}
+
+type SnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *SnapshotMetadata      `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotResponse) Reset() {
+	*x = SnapshotResponse{}
+	mi := &file_peer_api_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotResponse) ProtoMessage() {}
+
+func (x *SnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SnapshotResponse) GetMetadata() *SnapshotMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *SnapshotResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type Operator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Role          *OperatorRole          `protobuf:"varint,2,req,name=role,enum=OperatorRole" json:"role,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,3,opt,name=datacenter" json:"datacenter,omitempty"`
+	Serial        *string                `protobuf:"bytes,4,opt,name=serial" json:"serial,omitempty"`
+	NotAfter      *int64                 `protobuf:"varint,5,opt,name=not_after,json=notAfter" json:"not_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operator) Reset() {
+	*x = Operator{}
+	mi := &file_peer_api_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operator) ProtoMessage() {}
+
+func (x *Operator) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operator.ProtoReflect.Descriptor instead.
+func (*Operator) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Operator) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Operator) GetRole() OperatorRole {
+	if x != nil && x.Role != nil {
+		return *x.Role
+	}
+	return OperatorRole_ADMIN
+}
+
+func (x *Operator) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *Operator) GetSerial() string {
+	if x != nil && x.Serial != nil {
+		return *x.Serial
+	}
+	return ""
+}
+
+func (x *Operator) GetNotAfter() int64 {
+	if x != nil && x.NotAfter != nil {
+		return *x.NotAfter
+	}
+	return 0
+}
+
+type CreateOperatorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Role          *OperatorRole          `protobuf:"varint,2,req,name=role,enum=OperatorRole" json:"role,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,3,opt,name=datacenter" json:"datacenter,omitempty"`
+	Csr           []byte                 `protobuf:"bytes,4,req,name=csr" json:"csr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOperatorRequest) Reset() {
+	*x = CreateOperatorRequest{}
+	mi := &file_peer_api_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOperatorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOperatorRequest) ProtoMessage() {}
+
+func (x *CreateOperatorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOperatorRequest.ProtoReflect.Descriptor instead.
+func (*CreateOperatorRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateOperatorRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *CreateOperatorRequest) GetRole() OperatorRole {
+	if x != nil && x.Role != nil {
+		return *x.Role
+	}
+	return OperatorRole_ADMIN
+}
+
+func (x *CreateOperatorRequest) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *CreateOperatorRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
+type CreateOperatorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificate   []byte                 `protobuf:"bytes,1,req,name=certificate" json:"certificate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOperatorResponse) Reset() {
+	*x = CreateOperatorResponse{}
+	mi := &file_peer_api_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOperatorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOperatorResponse) ProtoMessage() {}
+
+func (x *CreateOperatorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOperatorResponse.ProtoReflect.Descriptor instead.
+func (*CreateOperatorResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateOperatorResponse) GetCertificate() []byte {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+type ListOperatorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperatorsRequest) Reset() {
+	*x = ListOperatorsRequest{}
+	mi := &file_peer_api_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperatorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperatorsRequest) ProtoMessage() {}
+
+func (x *ListOperatorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperatorsRequest.ProtoReflect.Descriptor instead.
+func (*ListOperatorsRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{43}
+}
+
+type ListOperatorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operators     []*Operator            `protobuf:"bytes,1,rep,name=operators" json:"operators,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperatorsResponse) Reset() {
+	*x = ListOperatorsResponse{}
+	mi := &file_peer_api_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperatorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperatorsResponse) ProtoMessage() {}
+
+func (x *ListOperatorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperatorsResponse.ProtoReflect.Descriptor instead.
+func (*ListOperatorsResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListOperatorsResponse) GetOperators() []*Operator {
+	if x != nil {
+		return x.Operators
+	}
+	return nil
+}
+
+type RemoveOperatorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOperatorRequest) Reset() {
+	*x = RemoveOperatorRequest{}
+	mi := &file_peer_api_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOperatorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOperatorRequest) ProtoMessage() {}
+
+func (x *RemoveOperatorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOperatorRequest.ProtoReflect.Descriptor instead.
+func (*RemoveOperatorRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RemoveOperatorRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type RemoveOperatorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOperatorResponse) Reset() {
+	*x = RemoveOperatorResponse{}
+	mi := &file_peer_api_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOperatorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOperatorResponse) ProtoMessage() {}
+
+func (x *RemoveOperatorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOperatorResponse.ProtoReflect.Descriptor instead.
+func (*RemoveOperatorResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{46}
+}
+
+type AuditRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimestampMs   *int64                 `protobuf:"varint,1,req,name=timestamp_ms,json=timestampMs" json:"timestamp_ms,omitempty"`
+	Registry      *string                `protobuf:"bytes,2,req,name=registry" json:"registry,omitempty"`
+	Actor         *string                `protobuf:"bytes,3,req,name=actor" json:"actor,omitempty"`
+	ActorType     *string                `protobuf:"bytes,4,req,name=actor_type,json=actorType" json:"actor_type,omitempty"`
+	Address       *string                `protobuf:"bytes,5,opt,name=address" json:"address,omitempty"`
+	Action        *string                `protobuf:"bytes,6,req,name=action" json:"action,omitempty"`
+	Datacenter    *string                `protobuf:"bytes,7,opt,name=datacenter" json:"datacenter,omitempty"`
+	Target        *string                `protobuf:"bytes,8,opt,name=target" json:"target,omitempty"`
+	Outcome       *string                `protobuf:"bytes,9,req,name=outcome" json:"outcome,omitempty"`
+	Error         *string                `protobuf:"bytes,10,opt,name=error" json:"error,omitempty"`
+	RequestId     *string                `protobuf:"bytes,11,opt,name=request_id,json=requestId" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	mi := &file_peer_api_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *AuditRecord) GetTimestampMs() int64 {
+	if x != nil && x.TimestampMs != nil {
+		return *x.TimestampMs
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetRegistry() string {
+	if x != nil && x.Registry != nil {
+		return *x.Registry
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetActor() string {
+	if x != nil && x.Actor != nil {
+		return *x.Actor
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetActorType() string {
+	if x != nil && x.ActorType != nil {
+		return *x.ActorType
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAction() string {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetTarget() string {
+	if x != nil && x.Target != nil {
+		return *x.Target
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetOutcome() string {
+	if x != nil && x.Outcome != nil {
+		return *x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetRequestId() string {
+	if x != nil && x.RequestId != nil {
+		return *x.RequestId
+	}
+	return ""
+}
+
+type ListAuditRecordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SinceMs       *int64                 `protobuf:"varint,1,opt,name=since_ms,json=sinceMs" json:"since_ms,omitempty"`
+	Limit         *uint32                `protobuf:"varint,2,opt,name=limit" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditRecordsRequest) Reset() {
+	*x = ListAuditRecordsRequest{}
+	mi := &file_peer_api_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditRecordsRequest) ProtoMessage() {}
+
+func (x *ListAuditRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditRecordsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListAuditRecordsRequest) GetSinceMs() int64 {
+	if x != nil && x.SinceMs != nil {
+		return *x.SinceMs
+	}
+	return 0
+}
+
+func (x *ListAuditRecordsRequest) GetLimit() uint32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type ListAuditRecordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AuditRecord         `protobuf:"bytes,1,rep,name=records" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditRecordsResponse) Reset() {
+	*x = ListAuditRecordsResponse{}
+	mi := &file_peer_api_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditRecordsResponse) ProtoMessage() {}
+
+func (x *ListAuditRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditRecordsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListAuditRecordsResponse) GetRecords() []*AuditRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type Policy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Service          *string                `protobuf:"bytes,2,opt,name=service" json:"service,omitempty"`
+	Tags             []string               `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"`
+	Location         *string                `protobuf:"bytes,4,opt,name=location" json:"location,omitempty"`
+	Datacenter       *string                `protobuf:"bytes,5,opt,name=datacenter" json:"datacenter,omitempty"`
+	AllowLocations   []string               `protobuf:"bytes,6,rep,name=allow_locations,json=allowLocations" json:"allow_locations,omitempty"`
+	AllowDatacenters []string               `protobuf:"bytes,7,rep,name=allow_datacenters,json=allowDatacenters" json:"allow_datacenters,omitempty"`
+	AllowServices    []string               `protobuf:"bytes,8,rep,name=allow_services,json=allowServices" json:"allow_services,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_peer_api_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *Policy) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Policy) GetService() string {
+	if x != nil && x.Service != nil {
+		return *x.Service
+	}
+	return ""
+}
+
+func (x *Policy) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Policy) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *Policy) GetDatacenter() string {
+	if x != nil && x.Datacenter != nil {
+		return *x.Datacenter
+	}
+	return ""
+}
+
+func (x *Policy) GetAllowLocations() []string {
+	if x != nil {
+		return x.AllowLocations
+	}
+	return nil
+}
+
+func (x *Policy) GetAllowDatacenters() []string {
+	if x != nil {
+		return x.AllowDatacenters
+	}
+	return nil
+}
+
+func (x *Policy) GetAllowServices() []string {
+	if x != nil {
+		return x.AllowServices
+	}
+	return nil
+}
+
+type SetPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *Policy                `protobuf:"bytes,1,req,name=policy" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPolicyRequest) Reset() {
+	*x = SetPolicyRequest{}
+	mi := &file_peer_api_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPolicyRequest) ProtoMessage() {}
+
+func (x *SetPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SetPolicyRequest) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type SetPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPolicyResponse) Reset() {
+	*x = SetPolicyResponse{}
+	mi := &file_peer_api_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPolicyResponse) ProtoMessage() {}
+
+func (x *SetPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SetPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{52}
+}
+
+type ListPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesRequest) Reset() {
+	*x = ListPoliciesRequest{}
+	mi := &file_peer_api_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesRequest) ProtoMessage() {}
+
+func (x *ListPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{53}
+}
+
+type ListPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*Policy              `protobuf:"bytes,1,rep,name=policies" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoliciesResponse) Reset() {
+	*x = ListPoliciesResponse{}
+	mi := &file_peer_api_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesResponse) ProtoMessage() {}
+
+func (x *ListPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListPoliciesResponse) GetPolicies() []*Policy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type RemovePolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePolicyRequest) Reset() {
+	*x = RemovePolicyRequest{}
+	mi := &file_peer_api_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePolicyRequest) ProtoMessage() {}
+
+func (x *RemovePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePolicyRequest.ProtoReflect.Descriptor instead.
+func (*RemovePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RemovePolicyRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type RemovePolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePolicyResponse) Reset() {
+	*x = RemovePolicyResponse{}
+	mi := &file_peer_api_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePolicyResponse) ProtoMessage() {}
+
+func (x *RemovePolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePolicyResponse.ProtoReflect.Descriptor instead.
+func (*RemovePolicyResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{56}
+}
+
+type Intention struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *string                `protobuf:"bytes,1,req,name=source" json:"source,omitempty"`
+	Destination   *string                `protobuf:"bytes,2,req,name=destination" json:"destination,omitempty"`
+	Action        *IntentionAction       `protobuf:"varint,3,req,name=action,enum=IntentionAction" json:"action,omitempty"`
+	Description   *string                `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Intention) Reset() {
+	*x = Intention{}
+	mi := &file_peer_api_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Intention) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Intention) ProtoMessage() {}
+
+func (x *Intention) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Intention.ProtoReflect.Descriptor instead.
+func (*Intention) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *Intention) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
+}
+
+func (x *Intention) GetDestination() string {
+	if x != nil && x.Destination != nil {
+		return *x.Destination
+	}
+	return ""
+}
+
+func (x *Intention) GetAction() IntentionAction {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return IntentionAction_ALLOW
+}
+
+func (x *Intention) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+type SetIntentionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Intention     *Intention             `protobuf:"bytes,1,req,name=intention" json:"intention,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIntentionRequest) Reset() {
+	*x = SetIntentionRequest{}
+	mi := &file_peer_api_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIntentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIntentionRequest) ProtoMessage() {}
+
+func (x *SetIntentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIntentionRequest.ProtoReflect.Descriptor instead.
+func (*SetIntentionRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SetIntentionRequest) GetIntention() *Intention {
+	if x != nil {
+		return x.Intention
+	}
+	return nil
+}
+
+type SetIntentionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIntentionResponse) Reset() {
+	*x = SetIntentionResponse{}
+	mi := &file_peer_api_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIntentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIntentionResponse) ProtoMessage() {}
+
+func (x *SetIntentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIntentionResponse.ProtoReflect.Descriptor instead.
+func (*SetIntentionResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{59}
+}
+
+type ListIntentionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIntentionsRequest) Reset() {
+	*x = ListIntentionsRequest{}
+	mi := &file_peer_api_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIntentionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIntentionsRequest) ProtoMessage() {}
+
+func (x *ListIntentionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIntentionsRequest.ProtoReflect.Descriptor instead.
+func (*ListIntentionsRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{60}
+}
+
+type ListIntentionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Intentions    []*Intention           `protobuf:"bytes,1,rep,name=intentions" json:"intentions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIntentionsResponse) Reset() {
+	*x = ListIntentionsResponse{}
+	mi := &file_peer_api_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIntentionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIntentionsResponse) ProtoMessage() {}
+
+func (x *ListIntentionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIntentionsResponse.ProtoReflect.Descriptor instead.
+func (*ListIntentionsResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListIntentionsResponse) GetIntentions() []*Intention {
+	if x != nil {
+		return x.Intentions
+	}
+	return nil
+}
+
+type RemoveIntentionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        *string                `protobuf:"bytes,1,req,name=source" json:"source,omitempty"`
+	Destination   *string                `protobuf:"bytes,2,req,name=destination" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveIntentionRequest) Reset() {
+	*x = RemoveIntentionRequest{}
+	mi := &file_peer_api_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveIntentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveIntentionRequest) ProtoMessage() {}
+
+func (x *RemoveIntentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveIntentionRequest.ProtoReflect.Descriptor instead.
+func (*RemoveIntentionRequest) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *RemoveIntentionRequest) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
+}
+
+func (x *RemoveIntentionRequest) GetDestination() string {
+	if x != nil && x.Destination != nil {
+		return *x.Destination
+	}
+	return ""
+}
+
+type RemoveIntentionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveIntentionResponse) Reset() {
+	*x = RemoveIntentionResponse{}
+	mi := &file_peer_api_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveIntentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveIntentionResponse) ProtoMessage() {}
+
+func (x *RemoveIntentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peer_api_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveIntentionResponse.ProtoReflect.Descriptor instead.
+func (*RemoveIntentionResponse) Descriptor() ([]byte, []int) {
+	return file_peer_api_proto_rawDescGZIP(), []int{63}
+}
+
+var File_peer_api_proto protoreflect.FileDescriptor
+
+const file_peer_api_proto_rawDesc = "" +
+	"\n" +
+	"\x0epeer_api.proto\x1a\x0fagent_api.proto\"\x87\x01\n" +
+	"\x04Peer\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x02(\tR\x04name\x12\x1b\n" +
+	"\tpeer_urls\x18\x03 \x03(\tR\bpeerUrls\x12\x1f\n" +
+	"\vclient_urls\x18\x04 \x03(\tR\n" +
+	"clientUrls\x12\x1d\n" +
+	"\n" +
+	"is_learner\x18\x05 \x01(\bR\tisLearner\"\x11\n" +
+	"\x0fGetPeersRequest\"/\n" +
+	"\x10GetPeersResponse\x12\x1b\n" +
+	"\x05peers\x18\x01 \x03(\v2\x05.PeerR\x05peers\"=\n" +
+	"\x12AddSelfPeerRequest\x12'\n" +
+	"\x0fadvertised_urls\x18\x01 \x03(\tR\x0eadvertisedUrls\"\x15\n" +
+	"\x13AddSelfPeerResponse\"'\n" +
+	"\x11RemovePeerRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x02(\tR\x04peer\"\x14\n" +
+	"\x12RemovePeerResponse\"(\n" +
+	"\x12PromotePeerRequest\x12\x12\n" +
+	"\x04peer\x18\x01 \x02(\tR\x04peer\"\x15\n" +
+	"\x13PromotePeerResponse\"\xb3\x02\n" +
+	"\n" +
+	"PeerStatus\x12\x19\n" +
+	"\x04peer\x18\x01 \x02(\v2\x05.PeerR\x04peer\x12\x18\n" +
+	"\ahealthy\x18\x02 \x01(\bR\ahealthy\x12\x16\n" +
+	"\x06leader\x18\x03 \x01(\bR\x06leader\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x17\n" +
+	"\adb_size\x18\x05 \x01(\x03R\x06dbSize\x12\x1b\n" +
+	"\traft_term\x18\x06 \x01(\x04R\braftTerm\x12\x1d\n" +
+	"\n" +
+	"raft_index\x18\a \x01(\x04R\traftIndex\x12,\n" +
+	"\x12raft_applied_index\x18\b \x01(\x04R\x10raftAppliedIndex\x12\x16\n" +
+	"\x06errors\x18\t \x03(\tR\x06errors\x12#\n" +
+	"\x0edb_size_in_use\x18\n" +
+	" \x01(\x03R\vdbSizeInUse\"\x13\n" +
+	"\x11PeerStatusRequest\"7\n" +
+	"\x12PeerStatusResponse\x12!\n" +
+	"\x05peers\x18\x01 \x03(\v2\v.PeerStatusR\x05peers\"\xc3\x01\n" +
 	"\x0eAddNodeRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
 	"\n" +
 	"datacenter\x18\x02 \x02(\tR\n" +
 	"datacenter\x12\x1a\n" +
 	"\blocation\x18\x03 \x02(\tR\blocation\x12&\n" +
-	"\tnode_type\x18\x04 \x02(\x0e2\t.NodeTypeR\bnodeType\"E\n" +
+	"\tnode_type\x18\x04 \x02(\x0e2\t.NodeTypeR\bnodeType\x12\x1c\n" +
+	"\tbootstrap\x18\x05 \x01(\bR\tbootstrap\x12\x1b\n" +
+	"\ttoken_ttl\x18\x06 \x01(\rR\btokenTtl\"[\n" +
 	"\x0fAddNodeResponse\x12 \n" +
-	"\vcertificate\x18\x01 \x02(\fR\vcertificate\x12\x10\n" +
-	"\x03key\x18\x02 \x02(\fR\x03key\"O\n" +
+	"\vcertificate\x18\x01 \x01(\fR\vcertificate\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\fR\x03key\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"O\n" +
 	"\x19GetNodeCredentialsRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
 	"\n" +
@@ -563,15 +3601,229 @@ const file_peer_api_proto_rawDesc = "" +
 	"datacenter\"P\n" +
 	"\x1aGetNodeCredentialsResponse\x12 \n" +
 	"\vcertificate\x18\x01 \x02(\fR\vcertificate\x12\x10\n" +
-	"\x03key\x18\x02 \x02(\fR\x03key*#\n" +
+	"\x03key\x18\x02 \x02(\fR\x03key\"\x9a\x01\n" +
+	"\x04Node\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x02 \x02(\tR\n" +
+	"datacenter\x12\x1a\n" +
+	"\blocation\x18\x03 \x02(\tR\blocation\x12&\n" +
+	"\tnode_type\x18\x04 \x02(\x0e2\t.NodeTypeR\bnodeType\x12\x1a\n" +
+	"\bdisabled\x18\x05 \x01(\bR\bdisabled\"2\n" +
+	"\x10ListNodesRequest\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x01 \x01(\tR\n" +
+	"datacenter\"0\n" +
+	"\x11ListNodesResponse\x12\x1b\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x05.NodeR\x05nodes\"\x7f\n" +
+	"\x0fNodeCertificate\x12\x16\n" +
+	"\x06serial\x18\x01 \x02(\tR\x06serial\x12\x1d\n" +
+	"\n" +
+	"not_before\x18\x02 \x02(\x03R\tnotBefore\x12\x1b\n" +
+	"\tnot_after\x18\x03 \x02(\x03R\bnotAfter\x12\x18\n" +
+	"\arevoked\x18\x04 \x01(\bR\arevoked\"D\n" +
+	"\x0eGetNodeRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x02 \x02(\tR\n" +
+	"datacenter\"\x8c\x01\n" +
+	"\x0fGetNodeResponse\x12\x19\n" +
+	"\x04node\x18\x01 \x02(\v2\x05.NodeR\x04node\x12(\n" +
+	"\bservices\x18\x02 \x03(\v2\f.ServiceSpecR\bservices\x124\n" +
+	"\fcertificates\x18\x03 \x03(\v2\x10.NodeCertificateR\fcertificates\"G\n" +
+	"\x11RemoveNodeRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x02 \x02(\tR\n" +
+	"datacenter\"\x14\n" +
+	"\x12RemoveNodeResponse\"h\n" +
+	"\x16SetNodeDisabledRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x02 \x02(\tR\n" +
+	"datacenter\x12\x1a\n" +
+	"\bdisabled\x18\x03 \x02(\bR\bdisabled\"\x19\n" +
+	"\x17SetNodeDisabledResponse\"k\n" +
+	"\x1dRevokeNodeCertificatesRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x02 \x02(\tR\n" +
+	"datacenter\x12\x16\n" +
+	"\x06serial\x18\x03 \x01(\tR\x06serial\":\n" +
+	"\x1eRevokeNodeCertificatesResponse\x12\x18\n" +
+	"\aserials\x18\x01 \x03(\tR\aserials\"{\n" +
+	"\fCAGeneration\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x02(\rR\n" +
+	"generation\x12\x14\n" +
+	"\x05start\x18\x02 \x02(\x03R\x05start\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\x12\x1d\n" +
+	"\n" +
+	"trusted_by\x18\x04 \x03(\tR\ttrustedBy\"\x13\n" +
+	"\x11GetCAStateRequest\"d\n" +
+	"\x12GetCAStateResponse\x12/\n" +
+	"\vgenerations\x18\x01 \x03(\v2\r.CAGenerationR\vgenerations\x12\x1d\n" +
+	"\n" +
+	"join_token\x18\x02 \x02(\tR\tjoinToken\"O\n" +
+	"\x0fRotateCARequest\x12&\n" +
+	"\x05phase\x18\x01 \x02(\x0e2\x10.CARotationPhaseR\x05phase\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"b\n" +
+	"\x10RotateCAResponse\x12/\n" +
+	"\vgenerations\x18\x01 \x03(\v2\r.CAGenerationR\vgenerations\x12\x1d\n" +
+	"\n" +
+	"join_token\x18\x02 \x02(\tR\tjoinToken\"\x14\n" +
+	"\x12RotateTokenRequest\"e\n" +
+	"\x13RotateTokenResponse\x12/\n" +
+	"\vgenerations\x18\x01 \x03(\v2\r.CAGenerationR\vgenerations\x12\x1d\n" +
+	"\n" +
+	"join_token\x18\x02 \x02(\tR\tjoinToken\"3\n" +
+	"\x11GetCATokenRequest\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x02(\rR\n" +
+	"generation\"*\n" +
+	"\x12GetCATokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x02(\fR\x05token\"t\n" +
+	"\x10SnapshotMetadata\x12\x14\n" +
+	"\x05token\x18\x01 \x02(\tR\x05token\x12\x19\n" +
+	"\bca_state\x18\x02 \x02(\fR\acaState\x12\x12\n" +
+	"\x04size\x18\x03 \x02(\x04R\x04size\x12\x1b\n" +
+	"\tca_tokens\x18\x04 \x01(\fR\bcaTokens\"\x11\n" +
+	"\x0fSnapshotRequest\"U\n" +
+	"\x10SnapshotResponse\x12-\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x11.SnapshotMetadataR\bmetadata\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\x96\x01\n" +
+	"\bOperator\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12!\n" +
+	"\x04role\x18\x02 \x02(\x0e2\r.OperatorRoleR\x04role\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x03 \x01(\tR\n" +
+	"datacenter\x12\x16\n" +
+	"\x06serial\x18\x04 \x01(\tR\x06serial\x12\x1b\n" +
+	"\tnot_after\x18\x05 \x01(\x03R\bnotAfter\"\x80\x01\n" +
+	"\x15CreateOperatorRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12!\n" +
+	"\x04role\x18\x02 \x02(\x0e2\r.OperatorRoleR\x04role\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x03 \x01(\tR\n" +
+	"datacenter\x12\x10\n" +
+	"\x03csr\x18\x04 \x02(\fR\x03csr\":\n" +
+	"\x16CreateOperatorResponse\x12 \n" +
+	"\vcertificate\x18\x01 \x02(\fR\vcertificate\"\x16\n" +
+	"\x14ListOperatorsRequest\"@\n" +
+	"\x15ListOperatorsResponse\x12'\n" +
+	"\toperators\x18\x01 \x03(\v2\t.OperatorR\toperators\"+\n" +
+	"\x15RemoveOperatorRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\"\x18\n" +
+	"\x16RemoveOperatorResponse\"\xba\x02\n" +
+	"\vAuditRecord\x12!\n" +
+	"\ftimestamp_ms\x18\x01 \x02(\x03R\vtimestampMs\x12\x1a\n" +
+	"\bregistry\x18\x02 \x02(\tR\bregistry\x12\x14\n" +
+	"\x05actor\x18\x03 \x02(\tR\x05actor\x12\x1d\n" +
+	"\n" +
+	"actor_type\x18\x04 \x02(\tR\tactorType\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06action\x18\x06 \x02(\tR\x06action\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\a \x01(\tR\n" +
+	"datacenter\x12\x16\n" +
+	"\x06target\x18\b \x01(\tR\x06target\x12\x18\n" +
+	"\aoutcome\x18\t \x02(\tR\aoutcome\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\v \x01(\tR\trequestId\"J\n" +
+	"\x17ListAuditRecordsRequest\x12\x19\n" +
+	"\bsince_ms\x18\x01 \x01(\x03R\asinceMs\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\"B\n" +
+	"\x18ListAuditRecordsResponse\x12&\n" +
+	"\arecords\x18\x01 \x03(\v2\f.AuditRecordR\arecords\"\x83\x02\n" +
+	"\x06Policy\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x18\n" +
+	"\aservice\x18\x02 \x01(\tR\aservice\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12\x1e\n" +
+	"\n" +
+	"datacenter\x18\x05 \x01(\tR\n" +
+	"datacenter\x12'\n" +
+	"\x0fallow_locations\x18\x06 \x03(\tR\x0eallowLocations\x12+\n" +
+	"\x11allow_datacenters\x18\a \x03(\tR\x10allowDatacenters\x12%\n" +
+	"\x0eallow_services\x18\b \x03(\tR\rallowServices\"3\n" +
+	"\x10SetPolicyRequest\x12\x1f\n" +
+	"\x06policy\x18\x01 \x02(\v2\a.PolicyR\x06policy\"\x13\n" +
+	"\x11SetPolicyResponse\"\x15\n" +
+	"\x13ListPoliciesRequest\";\n" +
+	"\x14ListPoliciesResponse\x12#\n" +
+	"\bpolicies\x18\x01 \x03(\v2\a.PolicyR\bpolicies\")\n" +
+	"\x13RemovePolicyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\"\x16\n" +
+	"\x14RemovePolicyResponse\"\x91\x01\n" +
+	"\tIntention\x12\x16\n" +
+	"\x06source\x18\x01 \x02(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x02(\tR\vdestination\x12(\n" +
+	"\x06action\x18\x03 \x02(\x0e2\x10.IntentionActionR\x06action\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\"?\n" +
+	"\x13SetIntentionRequest\x12(\n" +
+	"\tintention\x18\x01 \x02(\v2\n" +
+	".IntentionR\tintention\"\x16\n" +
+	"\x14SetIntentionResponse\"\x17\n" +
+	"\x15ListIntentionsRequest\"D\n" +
+	"\x16ListIntentionsResponse\x12*\n" +
+	"\n" +
+	"intentions\x18\x01 \x03(\v2\n" +
+	".IntentionR\n" +
+	"intentions\"R\n" +
+	"\x16RemoveIntentionRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x02(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x02(\tR\vdestination\"\x19\n" +
+	"\x17RemoveIntentionResponse*#\n" +
 	"\bNodeType\x12\t\n" +
 	"\x05AGENT\x10\x01\x12\f\n" +
-	"\bOBSERVER\x10\x022\xf9\x01\n" +
+	"\bOBSERVER\x10\x02*:\n" +
+	"\x0fCARotationPhase\x12\r\n" +
+	"\tINTRODUCE\x10\x01\x12\f\n" +
+	"\bACTIVATE\x10\x02\x12\n" +
+	"\n" +
+	"\x06RETIRE\x10\x03*:\n" +
+	"\fOperatorRole\x12\t\n" +
+	"\x05ADMIN\x10\x01\x12\x10\n" +
+	"\fNODE_MANAGER\x10\x02\x12\r\n" +
+	"\tREAD_ONLY\x10\x03*&\n" +
+	"\x0fIntentionAction\x12\t\n" +
+	"\x05ALLOW\x10\x01\x12\b\n" +
+	"\x04DENY\x10\x022\x98\r\n" +
 	"\aPeerAPI\x121\n" +
 	"\bGetPeers\x12\x10.GetPeersRequest\x1a\x11.GetPeersResponse\"\x00\x12:\n" +
-	"\vAddSelfPeer\x12\x13.AddSelfPeerRequest\x1a\x14.AddSelfPeerResponse\"\x00\x12.\n" +
+	"\vAddSelfPeer\x12\x13.AddSelfPeerRequest\x1a\x14.AddSelfPeerResponse\"\x00\x127\n" +
+	"\n" +
+	"RemovePeer\x12\x12.RemovePeerRequest\x1a\x13.RemovePeerResponse\"\x00\x12:\n" +
+	"\vPromotePeer\x12\x13.PromotePeerRequest\x1a\x14.PromotePeerResponse\"\x00\x127\n" +
+	"\n" +
+	"PeerStatus\x12\x12.PeerStatusRequest\x1a\x13.PeerStatusResponse\"\x00\x123\n" +
+	"\bSnapshot\x12\x10.SnapshotRequest\x1a\x11.SnapshotResponse\"\x000\x01\x12.\n" +
 	"\aAddNode\x12\x0f.AddNodeRequest\x1a\x10.AddNodeResponse\"\x00\x12O\n" +
-	"\x12GetNodeCredentials\x12\x1a.GetNodeCredentialsRequest\x1a\x1b.GetNodeCredentialsResponse\"\x00B\x0fZ\rssle/services"
+	"\x12GetNodeCredentials\x12\x1a.GetNodeCredentialsRequest\x1a\x1b.GetNodeCredentialsResponse\"\x00\x124\n" +
+	"\tListNodes\x12\x11.ListNodesRequest\x1a\x12.ListNodesResponse\"\x00\x12.\n" +
+	"\aGetNode\x12\x0f.GetNodeRequest\x1a\x10.GetNodeResponse\"\x00\x127\n" +
+	"\n" +
+	"RemoveNode\x12\x12.RemoveNodeRequest\x1a\x13.RemoveNodeResponse\"\x00\x12F\n" +
+	"\x0fSetNodeDisabled\x12\x17.SetNodeDisabledRequest\x1a\x18.SetNodeDisabledResponse\"\x00\x12[\n" +
+	"\x16RevokeNodeCertificates\x12\x1e.RevokeNodeCertificatesRequest\x1a\x1f.RevokeNodeCertificatesResponse\"\x00\x127\n" +
+	"\n" +
+	"GetCAState\x12\x12.GetCAStateRequest\x1a\x13.GetCAStateResponse\"\x00\x121\n" +
+	"\bRotateCA\x12\x10.RotateCARequest\x1a\x11.RotateCAResponse\"\x00\x12:\n" +
+	"\vRotateToken\x12\x13.RotateTokenRequest\x1a\x14.RotateTokenResponse\"\x00\x127\n" +
+	"\n" +
+	"GetCAToken\x12\x12.GetCATokenRequest\x1a\x13.GetCATokenResponse\"\x00\x12C\n" +
+	"\x0eCreateOperator\x12\x16.CreateOperatorRequest\x1a\x17.CreateOperatorResponse\"\x00\x12@\n" +
+	"\rListOperators\x12\x15.ListOperatorsRequest\x1a\x16.ListOperatorsResponse\"\x00\x12C\n" +
+	"\x0eRemoveOperator\x12\x16.RemoveOperatorRequest\x1a\x17.RemoveOperatorResponse\"\x00\x12I\n" +
+	"\x10ListAuditRecords\x12\x18.ListAuditRecordsRequest\x1a\x19.ListAuditRecordsResponse\"\x00\x124\n" +
+	"\tSetPolicy\x12\x11.SetPolicyRequest\x1a\x12.SetPolicyResponse\"\x00\x12=\n" +
+	"\fListPolicies\x12\x14.ListPoliciesRequest\x1a\x15.ListPoliciesResponse\"\x00\x12=\n" +
+	"\fRemovePolicy\x12\x14.RemovePolicyRequest\x1a\x15.RemovePolicyResponse\"\x00\x12=\n" +
+	"\fSetIntention\x12\x14.SetIntentionRequest\x1a\x15.SetIntentionResponse\"\x00\x12C\n" +
+	"\x0eListIntentions\x12\x16.ListIntentionsRequest\x1a\x17.ListIntentionsResponse\"\x00\x12F\n" +
+	"\x0fRemoveIntention\x12\x17.RemoveIntentionRequest\x1a\x18.RemoveIntentionResponse\"\x00B\x0fZ\rssle/services"
 
 var (
 	file_peer_api_proto_rawDescOnce sync.Once
@@ -585,36 +3837,162 @@ func file_peer_api_proto_rawDescGZIP() []byte {
 	return file_peer_api_proto_rawDescData
 }
 
-var file_peer_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_peer_api_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_peer_api_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_peer_api_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
 var file_peer_api_proto_goTypes = []any{
-	(NodeType)(0),                      // 0: NodeType
-	(*Peer)(nil),                       // 1: Peer
-	(*GetPeersRequest)(nil),            // 2: GetPeersRequest
-	(*GetPeersResponse)(nil),           // 3: GetPeersResponse
-	(*AddSelfPeerRequest)(nil),         // 4: AddSelfPeerRequest
-	(*AddSelfPeerResponse)(nil),        // 5: AddSelfPeerResponse
-	(*AddNodeRequest)(nil),             // 6: AddNodeRequest
-	(*AddNodeResponse)(nil),            // 7: AddNodeResponse
-	(*GetNodeCredentialsRequest)(nil),  // 8: GetNodeCredentialsRequest
-	(*GetNodeCredentialsResponse)(nil), // 9: GetNodeCredentialsResponse
+	(NodeType)(0),                          // 0: NodeType
+	(CARotationPhase)(0),                   // 1: CARotationPhase
+	(OperatorRole)(0),                      // 2: OperatorRole
+	(IntentionAction)(0),                   // 3: IntentionAction
+	(*Peer)(nil),                           // 4: Peer
+	(*GetPeersRequest)(nil),                // 5: GetPeersRequest
+	(*GetPeersResponse)(nil),               // 6: GetPeersResponse
+	(*AddSelfPeerRequest)(nil),             // 7: AddSelfPeerRequest
+	(*AddSelfPeerResponse)(nil),            // 8: AddSelfPeerResponse
+	(*RemovePeerRequest)(nil),              // 9: RemovePeerRequest
+	(*RemovePeerResponse)(nil),             // 10: RemovePeerResponse
+	(*PromotePeerRequest)(nil),             // 11: PromotePeerRequest
+	(*PromotePeerResponse)(nil),            // 12: PromotePeerResponse
+	(*PeerStatus)(nil),                     // 13: PeerStatus
+	(*PeerStatusRequest)(nil),              // 14: PeerStatusRequest
+	(*PeerStatusResponse)(nil),             // 15: PeerStatusResponse
+	(*AddNodeRequest)(nil),                 // 16: AddNodeRequest
+	(*AddNodeResponse)(nil),                // 17: AddNodeResponse
+	(*GetNodeCredentialsRequest)(nil),      // 18: GetNodeCredentialsRequest
+	(*GetNodeCredentialsResponse)(nil),     // 19: GetNodeCredentialsResponse
+	(*Node)(nil),                           // 20: Node
+	(*ListNodesRequest)(nil),               // 21: ListNodesRequest
+	(*ListNodesResponse)(nil),              // 22: ListNodesResponse
+	(*NodeCertificate)(nil),                // 23: NodeCertificate
+	(*GetNodeRequest)(nil),                 // 24: GetNodeRequest
+	(*GetNodeResponse)(nil),                // 25: GetNodeResponse
+	(*RemoveNodeRequest)(nil),              // 26: RemoveNodeRequest
+	(*RemoveNodeResponse)(nil),             // 27: RemoveNodeResponse
+	(*SetNodeDisabledRequest)(nil),         // 28: SetNodeDisabledRequest
+	(*SetNodeDisabledResponse)(nil),        // 29: SetNodeDisabledResponse
+	(*RevokeNodeCertificatesRequest)(nil),  // 30: RevokeNodeCertificatesRequest
+	(*RevokeNodeCertificatesResponse)(nil), // 31: RevokeNodeCertificatesResponse
+	(*CAGeneration)(nil),                   // 32: CAGeneration
+	(*GetCAStateRequest)(nil),              // 33: GetCAStateRequest
+	(*GetCAStateResponse)(nil),             // 34: GetCAStateResponse
+	(*RotateCARequest)(nil),                // 35: RotateCARequest
+	(*RotateCAResponse)(nil),               // 36: RotateCAResponse
+	(*RotateTokenRequest)(nil),             // 37: RotateTokenRequest
+	(*RotateTokenResponse)(nil),            // 38: RotateTokenResponse
+	(*GetCATokenRequest)(nil),              // 39: GetCATokenRequest
+	(*GetCATokenResponse)(nil),             // 40: GetCATokenResponse
+	(*SnapshotMetadata)(nil),               // 41: SnapshotMetadata
+	(*SnapshotRequest)(nil),                // 42: SnapshotRequest
+	(*SnapshotResponse)(nil),               // 43: SnapshotResponse
+	(*Operator)(nil),                       // 44: Operator
+	(*CreateOperatorRequest)(nil),          // 45: CreateOperatorRequest
+	(*CreateOperatorResponse)(nil),         // 46: CreateOperatorResponse
+	(*ListOperatorsRequest)(nil),           // 47: ListOperatorsRequest
+	(*ListOperatorsResponse)(nil),          // 48: ListOperatorsResponse
+	(*RemoveOperatorRequest)(nil),          // 49: RemoveOperatorRequest
+	(*RemoveOperatorResponse)(nil),         // 50: RemoveOperatorResponse
+	(*AuditRecord)(nil),                    // 51: AuditRecord
+	(*ListAuditRecordsRequest)(nil),        // 52: ListAuditRecordsRequest
+	(*ListAuditRecordsResponse)(nil),       // 53: ListAuditRecordsResponse
+	(*Policy)(nil),                         // 54: Policy
+	(*SetPolicyRequest)(nil),               // 55: SetPolicyRequest
+	(*SetPolicyResponse)(nil),              // 56: SetPolicyResponse
+	(*ListPoliciesRequest)(nil),            // 57: ListPoliciesRequest
+	(*ListPoliciesResponse)(nil),           // 58: ListPoliciesResponse
+	(*RemovePolicyRequest)(nil),            // 59: RemovePolicyRequest
+	(*RemovePolicyResponse)(nil),           // 60: RemovePolicyResponse
+	(*Intention)(nil),                      // 61: Intention
+	(*SetIntentionRequest)(nil),            // 62: SetIntentionRequest
+	(*SetIntentionResponse)(nil),           // 63: SetIntentionResponse
+	(*ListIntentionsRequest)(nil),          // 64: ListIntentionsRequest
+	(*ListIntentionsResponse)(nil),         // 65: ListIntentionsResponse
+	(*RemoveIntentionRequest)(nil),         // 66: RemoveIntentionRequest
+	(*RemoveIntentionResponse)(nil),        // 67: RemoveIntentionResponse
+	(*ServiceSpec)(nil),                    // 68: ServiceSpec
 }
 var file_peer_api_proto_depIdxs = []int32{
-	1, // 0: GetPeersResponse.peers:type_name -> Peer
-	0, // 1: AddNodeRequest.node_type:type_name -> NodeType
-	2, // 2: PeerAPI.GetPeers:input_type -> GetPeersRequest
-	4, // 3: PeerAPI.AddSelfPeer:input_type -> AddSelfPeerRequest
-	6, // 4: PeerAPI.AddNode:input_type -> AddNodeRequest
-	8, // 5: PeerAPI.GetNodeCredentials:input_type -> GetNodeCredentialsRequest
-	3, // 6: PeerAPI.GetPeers:output_type -> GetPeersResponse
-	5, // 7: PeerAPI.AddSelfPeer:output_type -> AddSelfPeerResponse
-	7, // 8: PeerAPI.AddNode:output_type -> AddNodeResponse
-	9, // 9: PeerAPI.GetNodeCredentials:output_type -> GetNodeCredentialsResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4,  // 0: GetPeersResponse.peers:type_name -> Peer
+	4,  // 1: PeerStatus.peer:type_name -> Peer
+	13, // 2: PeerStatusResponse.peers:type_name -> PeerStatus
+	0,  // 3: AddNodeRequest.node_type:type_name -> NodeType
+	0,  // 4: Node.node_type:type_name -> NodeType
+	20, // 5: ListNodesResponse.nodes:type_name -> Node
+	20, // 6: GetNodeResponse.node:type_name -> Node
+	68, // 7: GetNodeResponse.services:type_name -> ServiceSpec
+	23, // 8: GetNodeResponse.certificates:type_name -> NodeCertificate
+	32, // 9: GetCAStateResponse.generations:type_name -> CAGeneration
+	1,  // 10: RotateCARequest.phase:type_name -> CARotationPhase
+	32, // 11: RotateCAResponse.generations:type_name -> CAGeneration
+	32, // 12: RotateTokenResponse.generations:type_name -> CAGeneration
+	41, // 13: SnapshotResponse.metadata:type_name -> SnapshotMetadata
+	2,  // 14: Operator.role:type_name -> OperatorRole
+	2,  // 15: CreateOperatorRequest.role:type_name -> OperatorRole
+	44, // 16: ListOperatorsResponse.operators:type_name -> Operator
+	51, // 17: ListAuditRecordsResponse.records:type_name -> AuditRecord
+	54, // 18: SetPolicyRequest.policy:type_name -> Policy
+	54, // 19: ListPoliciesResponse.policies:type_name -> Policy
+	3,  // 20: Intention.action:type_name -> IntentionAction
+	61, // 21: SetIntentionRequest.intention:type_name -> Intention
+	61, // 22: ListIntentionsResponse.intentions:type_name -> Intention
+	5,  // 23: PeerAPI.GetPeers:input_type -> GetPeersRequest
+	7,  // 24: PeerAPI.AddSelfPeer:input_type -> AddSelfPeerRequest
+	9,  // 25: PeerAPI.RemovePeer:input_type -> RemovePeerRequest
+	11, // 26: PeerAPI.PromotePeer:input_type -> PromotePeerRequest
+	14, // 27: PeerAPI.PeerStatus:input_type -> PeerStatusRequest
+	42, // 28: PeerAPI.Snapshot:input_type -> SnapshotRequest
+	16, // 29: PeerAPI.AddNode:input_type -> AddNodeRequest
+	18, // 30: PeerAPI.GetNodeCredentials:input_type -> GetNodeCredentialsRequest
+	21, // 31: PeerAPI.ListNodes:input_type -> ListNodesRequest
+	24, // 32: PeerAPI.GetNode:input_type -> GetNodeRequest
+	26, // 33: PeerAPI.RemoveNode:input_type -> RemoveNodeRequest
+	28, // 34: PeerAPI.SetNodeDisabled:input_type -> SetNodeDisabledRequest
+	30, // 35: PeerAPI.RevokeNodeCertificates:input_type -> RevokeNodeCertificatesRequest
+	33, // 36: PeerAPI.GetCAState:input_type -> GetCAStateRequest
+	35, // 37: PeerAPI.RotateCA:input_type -> RotateCARequest
+	37, // 38: PeerAPI.RotateToken:input_type -> RotateTokenRequest
+	39, // 39: PeerAPI.GetCAToken:input_type -> GetCATokenRequest
+	45, // 40: PeerAPI.CreateOperator:input_type -> CreateOperatorRequest
+	47, // 41: PeerAPI.ListOperators:input_type -> ListOperatorsRequest
+	49, // 42: PeerAPI.RemoveOperator:input_type -> RemoveOperatorRequest
+	52, // 43: PeerAPI.ListAuditRecords:input_type -> ListAuditRecordsRequest
+	55, // 44: PeerAPI.SetPolicy:input_type -> SetPolicyRequest
+	57, // 45: PeerAPI.ListPolicies:input_type -> ListPoliciesRequest
+	59, // 46: PeerAPI.RemovePolicy:input_type -> RemovePolicyRequest
+	62, // 47: PeerAPI.SetIntention:input_type -> SetIntentionRequest
+	64, // 48: PeerAPI.ListIntentions:input_type -> ListIntentionsRequest
+	66, // 49: PeerAPI.RemoveIntention:input_type -> RemoveIntentionRequest
+	6,  // 50: PeerAPI.GetPeers:output_type -> GetPeersResponse
+	8,  // 51: PeerAPI.AddSelfPeer:output_type -> AddSelfPeerResponse
+	10, // 52: PeerAPI.RemovePeer:output_type -> RemovePeerResponse
+	12, // 53: PeerAPI.PromotePeer:output_type -> PromotePeerResponse
+	15, // 54: PeerAPI.PeerStatus:output_type -> PeerStatusResponse
+	43, // 55: PeerAPI.Snapshot:output_type -> SnapshotResponse
+	17, // 56: PeerAPI.AddNode:output_type -> AddNodeResponse
+	19, // 57: PeerAPI.GetNodeCredentials:output_type -> GetNodeCredentialsResponse
+	22, // 58: PeerAPI.ListNodes:output_type -> ListNodesResponse
+	25, // 59: PeerAPI.GetNode:output_type -> GetNodeResponse
+	27, // 60: PeerAPI.RemoveNode:output_type -> RemoveNodeResponse
+	29, // 61: PeerAPI.SetNodeDisabled:output_type -> SetNodeDisabledResponse
+	31, // 62: PeerAPI.RevokeNodeCertificates:output_type -> RevokeNodeCertificatesResponse
+	34, // 63: PeerAPI.GetCAState:output_type -> GetCAStateResponse
+	36, // 64: PeerAPI.RotateCA:output_type -> RotateCAResponse
+	38, // 65: PeerAPI.RotateToken:output_type -> RotateTokenResponse
+	40, // 66: PeerAPI.GetCAToken:output_type -> GetCATokenResponse
+	46, // 67: PeerAPI.CreateOperator:output_type -> CreateOperatorResponse
+	48, // 68: PeerAPI.ListOperators:output_type -> ListOperatorsResponse
+	50, // 69: PeerAPI.RemoveOperator:output_type -> RemoveOperatorResponse
+	53, // 70: PeerAPI.ListAuditRecords:output_type -> ListAuditRecordsResponse
+	56, // 71: PeerAPI.SetPolicy:output_type -> SetPolicyResponse
+	58, // 72: PeerAPI.ListPolicies:output_type -> ListPoliciesResponse
+	60, // 73: PeerAPI.RemovePolicy:output_type -> RemovePolicyResponse
+	63, // 74: PeerAPI.SetIntention:output_type -> SetIntentionResponse
+	65, // 75: PeerAPI.ListIntentions:output_type -> ListIntentionsResponse
+	67, // 76: PeerAPI.RemoveIntention:output_type -> RemoveIntentionResponse
+	50, // [50:77] is the sub-list for method output_type
+	23, // [23:50] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_peer_api_proto_init() }
@@ -622,13 +4000,14 @@ func file_peer_api_proto_init() {
 	if File_peer_api_proto != nil {
 		return
 	}
+	file_agent_api_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_peer_api_proto_rawDesc), len(file_peer_api_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      4,
+			NumMessages:   64,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,33 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PeerAPI_GetPeers_FullMethodName           = "/PeerAPI/GetPeers"
-	PeerAPI_AddSelfPeer_FullMethodName        = "/PeerAPI/AddSelfPeer"
-	PeerAPI_AddNode_FullMethodName            = "/PeerAPI/AddNode"
-	PeerAPI_GetNodeCredentials_FullMethodName = "/PeerAPI/GetNodeCredentials"
+	PeerAPI_GetPeers_FullMethodName               = "/PeerAPI/GetPeers"
+	PeerAPI_AddSelfPeer_FullMethodName            = "/PeerAPI/AddSelfPeer"
+	PeerAPI_RemovePeer_FullMethodName             = "/PeerAPI/RemovePeer"
+	PeerAPI_PromotePeer_FullMethodName            = "/PeerAPI/PromotePeer"
+	PeerAPI_PeerStatus_FullMethodName             = "/PeerAPI/PeerStatus"
+	PeerAPI_Snapshot_FullMethodName               = "/PeerAPI/Snapshot"
+	PeerAPI_AddNode_FullMethodName                = "/PeerAPI/AddNode"
+	PeerAPI_GetNodeCredentials_FullMethodName     = "/PeerAPI/GetNodeCredentials"
+	PeerAPI_ListNodes_FullMethodName              = "/PeerAPI/ListNodes"
+	PeerAPI_GetNode_FullMethodName                = "/PeerAPI/GetNode"
+	PeerAPI_RemoveNode_FullMethodName             = "/PeerAPI/RemoveNode"
+	PeerAPI_SetNodeDisabled_FullMethodName        = "/PeerAPI/SetNodeDisabled"
+	PeerAPI_RevokeNodeCertificates_FullMethodName = "/PeerAPI/RevokeNodeCertificates"
+	PeerAPI_GetCAState_FullMethodName             = "/PeerAPI/GetCAState"
+	PeerAPI_RotateCA_FullMethodName               = "/PeerAPI/RotateCA"
+	PeerAPI_RotateToken_FullMethodName            = "/PeerAPI/RotateToken"
+	PeerAPI_GetCAToken_FullMethodName             = "/PeerAPI/GetCAToken"
+	PeerAPI_CreateOperator_FullMethodName         = "/PeerAPI/CreateOperator"
+	PeerAPI_ListOperators_FullMethodName          = "/PeerAPI/ListOperators"
+	PeerAPI_RemoveOperator_FullMethodName         = "/PeerAPI/RemoveOperator"
+	PeerAPI_ListAuditRecords_FullMethodName       = "/PeerAPI/ListAuditRecords"
+	PeerAPI_SetPolicy_FullMethodName              = "/PeerAPI/SetPolicy"
+	PeerAPI_ListPolicies_FullMethodName           = "/PeerAPI/ListPolicies"
+	PeerAPI_RemovePolicy_FullMethodName           = "/PeerAPI/RemovePolicy"
+	PeerAPI_SetIntention_FullMethodName           = "/PeerAPI/SetIntention"
+	PeerAPI_ListIntentions_FullMethodName         = "/PeerAPI/ListIntentions"
+	PeerAPI_RemoveIntention_FullMethodName        = "/PeerAPI/RemoveIntention"
 )
 
 // PeerAPIClient is the client API for PeerAPI service.
@@ -31,8 +54,31 @@ const (
 type PeerAPIClient interface {
 	GetPeers(ctx context.Context, in *GetPeersRequest, opts ...grpc.CallOption) (*GetPeersResponse, error)
 	AddSelfPeer(ctx context.Context, in *AddSelfPeerRequest, opts ...grpc.CallOption) (*AddSelfPeerResponse, error)
+	RemovePeer(ctx context.Context, in *RemovePeerRequest, opts ...grpc.CallOption) (*RemovePeerResponse, error)
+	PromotePeer(ctx context.Context, in *PromotePeerRequest, opts ...grpc.CallOption) (*PromotePeerResponse, error)
+	PeerStatus(ctx context.Context, in *PeerStatusRequest, opts ...grpc.CallOption) (*PeerStatusResponse, error)
+	Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotResponse], error)
 	AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error)
 	GetNodeCredentials(ctx context.Context, in *GetNodeCredentialsRequest, opts ...grpc.CallOption) (*GetNodeCredentialsResponse, error)
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+	GetNode(ctx context.Context, in *GetNodeRequest, opts ...grpc.CallOption) (*GetNodeResponse, error)
+	RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*RemoveNodeResponse, error)
+	SetNodeDisabled(ctx context.Context, in *SetNodeDisabledRequest, opts ...grpc.CallOption) (*SetNodeDisabledResponse, error)
+	RevokeNodeCertificates(ctx context.Context, in *RevokeNodeCertificatesRequest, opts ...grpc.CallOption) (*RevokeNodeCertificatesResponse, error)
+	GetCAState(ctx context.Context, in *GetCAStateRequest, opts ...grpc.CallOption) (*GetCAStateResponse, error)
+	RotateCA(ctx context.Context, in *RotateCARequest, opts ...grpc.CallOption) (*RotateCAResponse, error)
+	RotateToken(ctx context.Context, in *RotateTokenRequest, opts ...grpc.CallOption) (*RotateTokenResponse, error)
+	GetCAToken(ctx context.Context, in *GetCATokenRequest, opts ...grpc.CallOption) (*GetCATokenResponse, error)
+	CreateOperator(ctx context.Context, in *CreateOperatorRequest, opts ...grpc.CallOption) (*CreateOperatorResponse, error)
+	ListOperators(ctx context.Context, in *ListOperatorsRequest, opts ...grpc.CallOption) (*ListOperatorsResponse, error)
+	RemoveOperator(ctx context.Context, in *RemoveOperatorRequest, opts ...grpc.CallOption) (*RemoveOperatorResponse, error)
+	ListAuditRecords(ctx context.Context, in *ListAuditRecordsRequest, opts ...grpc.CallOption) (*ListAuditRecordsResponse, error)
+	SetPolicy(ctx context.Context, in *SetPolicyRequest, opts ...grpc.CallOption) (*SetPolicyResponse, error)
+	ListPolicies(ctx context.Context, in *ListPoliciesRequest, opts ...grpc.CallOption) (*ListPoliciesResponse, error)
+	RemovePolicy(ctx context.Context, in *RemovePolicyRequest, opts ...grpc.CallOption) (*RemovePolicyResponse, error)
+	SetIntention(ctx context.Context, in *SetIntentionRequest, opts ...grpc.CallOption) (*SetIntentionResponse, error)
+	ListIntentions(ctx context.Context, in *ListIntentionsRequest, opts ...grpc.CallOption) (*ListIntentionsResponse, error)
+	RemoveIntention(ctx context.Context, in *RemoveIntentionRequest, opts ...grpc.CallOption) (*RemoveIntentionResponse, error)
 }
 
 type peerAPIClient struct {
@@ -63,6 +109,55 @@ func (c *peerAPIClient) AddSelfPeer(ctx context.Context, in *AddSelfPeerRequest,
 	return out, nil
 }
 
+func (c *peerAPIClient) RemovePeer(ctx context.Context, in *RemovePeerRequest, opts ...grpc.CallOption) (*RemovePeerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePeerResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RemovePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) PromotePeer(ctx context.Context, in *PromotePeerRequest, opts ...grpc.CallOption) (*PromotePeerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromotePeerResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_PromotePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) PeerStatus(ctx context.Context, in *PeerStatusRequest, opts ...grpc.CallOption) (*PeerStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeerStatusResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_PeerStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PeerAPI_ServiceDesc.Streams[0], PeerAPI_Snapshot_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SnapshotRequest, SnapshotResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerAPI_SnapshotClient = grpc.ServerStreamingClient[SnapshotResponse]
+
 func (c *peerAPIClient) AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AddNodeResponse)
@@ -83,14 +178,227 @@ func (c *peerAPIClient) GetNodeCredentials(ctx context.Context, in *GetNodeCrede
 	return out, nil
 }
 
+func (c *peerAPIClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) GetNode(ctx context.Context, in *GetNodeRequest, opts ...grpc.CallOption) (*GetNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNodeResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_GetNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*RemoveNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveNodeResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RemoveNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) SetNodeDisabled(ctx context.Context, in *SetNodeDisabledRequest, opts ...grpc.CallOption) (*SetNodeDisabledResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNodeDisabledResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_SetNodeDisabled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RevokeNodeCertificates(ctx context.Context, in *RevokeNodeCertificatesRequest, opts ...grpc.CallOption) (*RevokeNodeCertificatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeNodeCertificatesResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RevokeNodeCertificates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) GetCAState(ctx context.Context, in *GetCAStateRequest, opts ...grpc.CallOption) (*GetCAStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCAStateResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_GetCAState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RotateCA(ctx context.Context, in *RotateCARequest, opts ...grpc.CallOption) (*RotateCAResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateCAResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RotateCA_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RotateToken(ctx context.Context, in *RotateTokenRequest, opts ...grpc.CallOption) (*RotateTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateTokenResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RotateToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) GetCAToken(ctx context.Context, in *GetCATokenRequest, opts ...grpc.CallOption) (*GetCATokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCATokenResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_GetCAToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) CreateOperator(ctx context.Context, in *CreateOperatorRequest, opts ...grpc.CallOption) (*CreateOperatorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOperatorResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_CreateOperator_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) ListOperators(ctx context.Context, in *ListOperatorsRequest, opts ...grpc.CallOption) (*ListOperatorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOperatorsResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_ListOperators_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RemoveOperator(ctx context.Context, in *RemoveOperatorRequest, opts ...grpc.CallOption) (*RemoveOperatorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveOperatorResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RemoveOperator_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) ListAuditRecords(ctx context.Context, in *ListAuditRecordsRequest, opts ...grpc.CallOption) (*ListAuditRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditRecordsResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_ListAuditRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) SetPolicy(ctx context.Context, in *SetPolicyRequest, opts ...grpc.CallOption) (*SetPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPolicyResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_SetPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) ListPolicies(ctx context.Context, in *ListPoliciesRequest, opts ...grpc.CallOption) (*ListPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPoliciesResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_ListPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RemovePolicy(ctx context.Context, in *RemovePolicyRequest, opts ...grpc.CallOption) (*RemovePolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePolicyResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RemovePolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) SetIntention(ctx context.Context, in *SetIntentionRequest, opts ...grpc.CallOption) (*SetIntentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetIntentionResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_SetIntention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) ListIntentions(ctx context.Context, in *ListIntentionsRequest, opts ...grpc.CallOption) (*ListIntentionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIntentionsResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_ListIntentions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerAPIClient) RemoveIntention(ctx context.Context, in *RemoveIntentionRequest, opts ...grpc.CallOption) (*RemoveIntentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveIntentionResponse)
+	err := c.cc.Invoke(ctx, PeerAPI_RemoveIntention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeerAPIServer is the server API for PeerAPI service.
 // All implementations must embed UnimplementedPeerAPIServer
 // for forward compatibility.
 type PeerAPIServer interface {
 	GetPeers(context.Context, *GetPeersRequest) (*GetPeersResponse, error)
 	AddSelfPeer(context.Context, *AddSelfPeerRequest) (*AddSelfPeerResponse, error)
+	RemovePeer(context.Context, *RemovePeerRequest) (*RemovePeerResponse, error)
+	PromotePeer(context.Context, *PromotePeerRequest) (*PromotePeerResponse, error)
+	PeerStatus(context.Context, *PeerStatusRequest) (*PeerStatusResponse, error)
+	Snapshot(*SnapshotRequest, grpc.ServerStreamingServer[SnapshotResponse]) error
 	AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error)
 	GetNodeCredentials(context.Context, *GetNodeCredentialsRequest) (*GetNodeCredentialsResponse, error)
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	GetNode(context.Context, *GetNodeRequest) (*GetNodeResponse, error)
+	RemoveNode(context.Context, *RemoveNodeRequest) (*RemoveNodeResponse, error)
+	SetNodeDisabled(context.Context, *SetNodeDisabledRequest) (*SetNodeDisabledResponse, error)
+	RevokeNodeCertificates(context.Context, *RevokeNodeCertificatesRequest) (*RevokeNodeCertificatesResponse, error)
+	GetCAState(context.Context, *GetCAStateRequest) (*GetCAStateResponse, error)
+	RotateCA(context.Context, *RotateCARequest) (*RotateCAResponse, error)
+	RotateToken(context.Context, *RotateTokenRequest) (*RotateTokenResponse, error)
+	GetCAToken(context.Context, *GetCATokenRequest) (*GetCATokenResponse, error)
+	CreateOperator(context.Context, *CreateOperatorRequest) (*CreateOperatorResponse, error)
+	ListOperators(context.Context, *ListOperatorsRequest) (*ListOperatorsResponse, error)
+	RemoveOperator(context.Context, *RemoveOperatorRequest) (*RemoveOperatorResponse, error)
+	ListAuditRecords(context.Context, *ListAuditRecordsRequest) (*ListAuditRecordsResponse, error)
+	SetPolicy(context.Context, *SetPolicyRequest) (*SetPolicyResponse, error)
+	ListPolicies(context.Context, *ListPoliciesRequest) (*ListPoliciesResponse, error)
+	RemovePolicy(context.Context, *RemovePolicyRequest) (*RemovePolicyResponse, error)
+	SetIntention(context.Context, *SetIntentionRequest) (*SetIntentionResponse, error)
+	ListIntentions(context.Context, *ListIntentionsRequest) (*ListIntentionsResponse, error)
+	RemoveIntention(context.Context, *RemoveIntentionRequest) (*RemoveIntentionResponse, error)
 	mustEmbedUnimplementedPeerAPIServer()
 }
 
@@ -107,12 +415,81 @@ func (UnimplementedPeerAPIServer) GetPeers(context.Context, *GetPeersRequest) (*
 func (UnimplementedPeerAPIServer) AddSelfPeer(context.Context, *AddSelfPeerRequest) (*AddSelfPeerResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method AddSelfPeer not implemented")
 }
+func (UnimplementedPeerAPIServer) RemovePeer(context.Context, *RemovePeerRequest) (*RemovePeerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemovePeer not implemented")
+}
+func (UnimplementedPeerAPIServer) PromotePeer(context.Context, *PromotePeerRequest) (*PromotePeerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PromotePeer not implemented")
+}
+func (UnimplementedPeerAPIServer) PeerStatus(context.Context, *PeerStatusRequest) (*PeerStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PeerStatus not implemented")
+}
+func (UnimplementedPeerAPIServer) Snapshot(*SnapshotRequest, grpc.ServerStreamingServer[SnapshotResponse]) error {
+	return status.Error(codes.Unimplemented, "method Snapshot not implemented")
+}
 func (UnimplementedPeerAPIServer) AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method AddNode not implemented")
 }
 func (UnimplementedPeerAPIServer) GetNodeCredentials(context.Context, *GetNodeCredentialsRequest) (*GetNodeCredentialsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetNodeCredentials not implemented")
 }
+func (UnimplementedPeerAPIServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedPeerAPIServer) GetNode(context.Context, *GetNodeRequest) (*GetNodeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNode not implemented")
+}
+func (UnimplementedPeerAPIServer) RemoveNode(context.Context, *RemoveNodeRequest) (*RemoveNodeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveNode not implemented")
+}
+func (UnimplementedPeerAPIServer) SetNodeDisabled(context.Context, *SetNodeDisabledRequest) (*SetNodeDisabledResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetNodeDisabled not implemented")
+}
+func (UnimplementedPeerAPIServer) RevokeNodeCertificates(context.Context, *RevokeNodeCertificatesRequest) (*RevokeNodeCertificatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeNodeCertificates not implemented")
+}
+func (UnimplementedPeerAPIServer) GetCAState(context.Context, *GetCAStateRequest) (*GetCAStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCAState not implemented")
+}
+func (UnimplementedPeerAPIServer) RotateCA(context.Context, *RotateCARequest) (*RotateCAResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RotateCA not implemented")
+}
+func (UnimplementedPeerAPIServer) RotateToken(context.Context, *RotateTokenRequest) (*RotateTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RotateToken not implemented")
+}
+func (UnimplementedPeerAPIServer) GetCAToken(context.Context, *GetCATokenRequest) (*GetCATokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCAToken not implemented")
+}
+func (UnimplementedPeerAPIServer) CreateOperator(context.Context, *CreateOperatorRequest) (*CreateOperatorResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateOperator not implemented")
+}
+func (UnimplementedPeerAPIServer) ListOperators(context.Context, *ListOperatorsRequest) (*ListOperatorsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOperators not implemented")
+}
+func (UnimplementedPeerAPIServer) RemoveOperator(context.Context, *RemoveOperatorRequest) (*RemoveOperatorResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveOperator not implemented")
+}
+func (UnimplementedPeerAPIServer) ListAuditRecords(context.Context, *ListAuditRecordsRequest) (*ListAuditRecordsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAuditRecords not implemented")
+}
+func (UnimplementedPeerAPIServer) SetPolicy(context.Context, *SetPolicyRequest) (*SetPolicyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetPolicy not implemented")
+}
+func (UnimplementedPeerAPIServer) ListPolicies(context.Context, *ListPoliciesRequest) (*ListPoliciesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPolicies not implemented")
+}
+func (UnimplementedPeerAPIServer) RemovePolicy(context.Context, *RemovePolicyRequest) (*RemovePolicyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemovePolicy not implemented")
+}
+func (UnimplementedPeerAPIServer) SetIntention(context.Context, *SetIntentionRequest) (*SetIntentionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetIntention not implemented")
+}
+func (UnimplementedPeerAPIServer) ListIntentions(context.Context, *ListIntentionsRequest) (*ListIntentionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListIntentions not implemented")
+}
+func (UnimplementedPeerAPIServer) RemoveIntention(context.Context, *RemoveIntentionRequest) (*RemoveIntentionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveIntention not implemented")
+}
 func (UnimplementedPeerAPIServer) mustEmbedUnimplementedPeerAPIServer() {}
 func (UnimplementedPeerAPIServer) testEmbeddedByValue()                 {}
 
@@ -170,6 +547,71 @@ func _PeerAPI_AddSelfPeer_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PeerAPI_RemovePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RemovePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RemovePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RemovePeer(ctx, req.(*RemovePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_PromotePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromotePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).PromotePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_PromotePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).PromotePeer(ctx, req.(*PromotePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_PeerStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).PeerStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_PeerStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).PeerStatus(ctx, req.(*PeerStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_Snapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerAPIServer).Snapshot(m, &grpc.GenericServerStream[SnapshotRequest, SnapshotResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerAPI_SnapshotServer = grpc.ServerStreamingServer[SnapshotResponse]
+
 func _PeerAPI_AddNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddNodeRequest)
 	if err := dec(in); err != nil {
@@ -206,30 +648,466 @@ func _PeerAPI_GetNodeCredentials_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
-// PeerAPI_ServiceDesc is the grpc.ServiceDesc for PeerAPI service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var PeerAPI_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "PeerAPI",
-	HandlerType: (*PeerAPIServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetPeers",
-			Handler:    _PeerAPI_GetPeers_Handler,
-		},
-		{
-			MethodName: "AddSelfPeer",
-			Handler:    _PeerAPI_AddSelfPeer_Handler,
-		},
-		{
-			MethodName: "AddNode",
-			Handler:    _PeerAPI_AddNode_Handler,
-		},
-		{
-			MethodName: "GetNodeCredentials",
-			Handler:    _PeerAPI_GetNodeCredentials_Handler,
+func _PeerAPI_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_GetNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).GetNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_GetNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).GetNode(ctx, req.(*GetNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RemoveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RemoveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RemoveNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RemoveNode(ctx, req.(*RemoveNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_SetNodeDisabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNodeDisabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).SetNodeDisabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_SetNodeDisabled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).SetNodeDisabled(ctx, req.(*SetNodeDisabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RevokeNodeCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeNodeCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RevokeNodeCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RevokeNodeCertificates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RevokeNodeCertificates(ctx, req.(*RevokeNodeCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_GetCAState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCAStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).GetCAState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_GetCAState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).GetCAState(ctx, req.(*GetCAStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RotateCA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateCARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RotateCA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RotateCA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RotateCA(ctx, req.(*RotateCARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RotateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RotateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RotateToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RotateToken(ctx, req.(*RotateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_GetCAToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCATokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).GetCAToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_GetCAToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).GetCAToken(ctx, req.(*GetCATokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_CreateOperator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOperatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).CreateOperator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_CreateOperator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).CreateOperator(ctx, req.(*CreateOperatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_ListOperators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOperatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).ListOperators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_ListOperators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).ListOperators(ctx, req.(*ListOperatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RemoveOperator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOperatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RemoveOperator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RemoveOperator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RemoveOperator(ctx, req.(*RemoveOperatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_ListAuditRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).ListAuditRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_ListAuditRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).ListAuditRecords(ctx, req.(*ListAuditRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_SetPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).SetPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_SetPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).SetPolicy(ctx, req.(*SetPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_ListPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).ListPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_ListPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).ListPolicies(ctx, req.(*ListPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RemovePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RemovePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RemovePolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RemovePolicy(ctx, req.(*RemovePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_SetIntention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetIntentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).SetIntention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_SetIntention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).SetIntention(ctx, req.(*SetIntentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_ListIntentions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIntentionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).ListIntentions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_ListIntentions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).ListIntentions(ctx, req.(*ListIntentionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerAPI_RemoveIntention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveIntentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerAPIServer).RemoveIntention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerAPI_RemoveIntention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerAPIServer).RemoveIntention(ctx, req.(*RemoveIntentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeerAPI_ServiceDesc is the grpc.ServiceDesc for PeerAPI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerAPI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "PeerAPI",
+	HandlerType: (*PeerAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPeers",
+			Handler:    _PeerAPI_GetPeers_Handler,
+		},
+		{
+			MethodName: "AddSelfPeer",
+			Handler:    _PeerAPI_AddSelfPeer_Handler,
+		},
+		{
+			MethodName: "RemovePeer",
+			Handler:    _PeerAPI_RemovePeer_Handler,
+		},
+		{
+			MethodName: "PromotePeer",
+			Handler:    _PeerAPI_PromotePeer_Handler,
+		},
+		{
+			MethodName: "PeerStatus",
+			Handler:    _PeerAPI_PeerStatus_Handler,
+		},
+		{
+			MethodName: "AddNode",
+			Handler:    _PeerAPI_AddNode_Handler,
+		},
+		{
+			MethodName: "GetNodeCredentials",
+			Handler:    _PeerAPI_GetNodeCredentials_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _PeerAPI_ListNodes_Handler,
+		},
+		{
+			MethodName: "GetNode",
+			Handler:    _PeerAPI_GetNode_Handler,
+		},
+		{
+			MethodName: "RemoveNode",
+			Handler:    _PeerAPI_RemoveNode_Handler,
+		},
+		{
+			MethodName: "SetNodeDisabled",
+			Handler:    _PeerAPI_SetNodeDisabled_Handler,
+		},
+		{
+			MethodName: "RevokeNodeCertificates",
+			Handler:    _PeerAPI_RevokeNodeCertificates_Handler,
+		},
+		{
+			MethodName: "GetCAState",
+			Handler:    _PeerAPI_GetCAState_Handler,
+		},
+		{
+			MethodName: "RotateCA",
+			Handler:    _PeerAPI_RotateCA_Handler,
+		},
+		{
+			MethodName: "RotateToken",
+			Handler:    _PeerAPI_RotateToken_Handler,
+		},
+		{
+			MethodName: "GetCAToken",
+			Handler:    _PeerAPI_GetCAToken_Handler,
+		},
+		{
+			MethodName: "CreateOperator",
+			Handler:    _PeerAPI_CreateOperator_Handler,
+		},
+		{
+			MethodName: "ListOperators",
+			Handler:    _PeerAPI_ListOperators_Handler,
+		},
+		{
+			MethodName: "RemoveOperator",
+			Handler:    _PeerAPI_RemoveOperator_Handler,
+		},
+		{
+			MethodName: "ListAuditRecords",
+			Handler:    _PeerAPI_ListAuditRecords_Handler,
+		},
+		{
+			MethodName: "SetPolicy",
+			Handler:    _PeerAPI_SetPolicy_Handler,
+		},
+		{
+			MethodName: "ListPolicies",
+			Handler:    _PeerAPI_ListPolicies_Handler,
+		},
+		{
+			MethodName: "RemovePolicy",
+			Handler:    _PeerAPI_RemovePolicy_Handler,
+		},
+		{
+			MethodName: "SetIntention",
+			Handler:    _PeerAPI_SetIntention_Handler,
+		},
+		{
+			MethodName: "ListIntentions",
+			Handler:    _PeerAPI_ListIntentions_Handler,
+		},
+		{
+			MethodName: "RemoveIntention",
+			Handler:    _PeerAPI_RemoveIntention_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Snapshot",
+			Handler:       _PeerAPI_Snapshot_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "peer_api.proto",
 }
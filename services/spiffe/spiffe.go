@@ -0,0 +1,52 @@
+package spiffe
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Trust domain of the workload identities issued by the registry
+const TrustDomain = "cluster.internal"
+
+// SPIFFE style id of the workloads of a service in a datacenter,
+// spiffe://cluster.internal/dc/<datacenter>/svc/<service>
+func ID(datacenter string, service string) *url.URL {
+	return &url.URL{
+		Scheme: "spiffe",
+		Host:   TrustDomain,
+		Path:   fmt.Sprintf("/dc/%s/svc/%s", datacenter, service),
+	}
+}
+
+// Datacenter and service of a workload id
+func Parse(id string) (string, string, error) {
+	u, err := url.Parse(id)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Scheme != "spiffe" || u.Host != TrustDomain {
+		return "", "", fmt.Errorf("%v is not in the %v trust domain", id, TrustDomain)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "dc" || parts[2] != "svc" || parts[1] == "" || parts[3] == "" {
+		return "", "", fmt.Errorf("malformed workload id %v", id)
+	}
+
+	return parts[1], parts[3], nil
+}
+
+// Workload id of a certificate issued by the registry
+func FromCertificate(cert *x509.Certificate) (string, error) {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" {
+			return uri.String(), nil
+		}
+	}
+
+	return "", errors.New("certificate has no workload id")
+}